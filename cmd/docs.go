@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"io"
+	"os"
+
+	"github.com/hc/hc/internal/docs"
+	"github.com/hc/hc/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var (
+	docsFolder int
+	docsOutput string
+)
+
+var docsCmd = &cobra.Command{
+	Use:          "docs",
+	Short:        "Generate HTML documentation",
+	Long:         `Generate a self-contained HTML page documenting saved requests, optionally limited to a single folder.`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		db, err := storage.InitDB()
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+		var folderID *int
+		if cmd.Flags().Changed("folder") {
+			folderID = &docsFolder
+		}
+		var out io.Writer = cmd.OutOrStdout()
+		if docsOutput != "" {
+			file, err := os.Create(docsOutput)
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			out = file
+		}
+		return docs.Generate(db, folderID, out)
+	},
+}
+
+func init() {
+	docsCmd.Flags().IntVarP(&docsFolder, "folder", "f", 0, "Only document the folder with this ID")
+	docsCmd.Flags().StringVarP(&docsOutput, "output", "o", "", "Write the documentation to a file instead of stdout")
+}
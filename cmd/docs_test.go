@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hc/hc/internal/models"
+	"github.com/hc/hc/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+func TestDocsCommandFlags(t *testing.T) {
+	if docsCmd.Use != "docs" {
+		t.Errorf("Expected Use to be 'docs', got %s", docsCmd.Use)
+	}
+	for _, name := range []string{"folder", "output"} {
+		if docsCmd.Flag(name) == nil {
+			t.Errorf("%s flag not defined", name)
+		}
+	}
+}
+
+func TestDocsCommand(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("HC_TEST_DB_PATH", filepath.Join(dir, "test.db"))
+	db, err := storage.InitDB()
+	if err != nil {
+		t.Fatalf("InitDB() error = %v", err)
+	}
+	folder := &models.Folder{Name: "Orders"}
+	if err := db.CreateFolder(folder); err != nil {
+		t.Fatalf("CreateFolder() error = %v", err)
+	}
+	if err := db.CreateRequest(&models.Request{Name: "List orders", FolderID: &folder.ID, Method: "GET", URL: "https://example.com/orders"}); err != nil {
+		t.Fatalf("CreateRequest() error = %v", err)
+	}
+	db.Close()
+	output := filepath.Join(dir, "docs.html")
+
+	tests := []struct {
+		name    string
+		args    []string
+		file    string
+		wantErr bool
+		want    string
+	}{
+		{name: "Stdout", args: []string{"docs"}, want: "<title>API Reference</title>"},
+		{name: "Folder to file", args: []string{"docs", "--folder", strconv.Itoa(folder.ID), "--output", output}, file: output, want: "<title>Orders</title>"},
+		{name: "Missing folder", args: []string{"docs", "--folder", "999"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			docsOutput = ""
+			rootCmd := &cobra.Command{Use: "hc", SilenceErrors: true}
+			AddToRoot(rootCmd)
+			var out bytes.Buffer
+			rootCmd.SetOut(&out)
+			rootCmd.SetArgs(tt.args)
+			err := rootCmd.ExecuteContext(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr {
+				return
+			}
+			got := out.String()
+			if tt.file != "" {
+				data, err := os.ReadFile(tt.file)
+				if err != nil {
+					t.Fatalf("ReadFile() error = %v", err)
+				}
+				got = string(data)
+			}
+			if !strings.Contains(got, tt.want) || !strings.Contains(got, "List orders") {
+				t.Errorf("Expected output to contain %q and the request, got %q", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/hc/hc/internal/mock"
+	"github.com/hc/hc/internal/models"
+	"github.com/hc/hc/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var (
+	mockPort         int
+	mockLatency      time.Duration
+	mockMatchHeaders bool
+	mockCORSOrigins  []string
+)
+
+var mockCmd = &cobra.Command{
+	Use:          "mock <folder>",
+	Short:        "Serve saved example responses as a mock API",
+	Long:         `Start a local HTTP server that matches incoming requests against the saved requests in a folder and replies with their saved example responses. The folder can be given by ID or name. Send the X-Mock-Example or X-Mock-Status header to choose a specific example.`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		db, err := storage.InitDB()
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+		folder, err := findFolder(db, args[0])
+		if err != nil {
+			return err
+		}
+		config := models.MockConfig{
+			FolderID:     &folder.ID,
+			Port:         mockPort,
+			Latency:      mockLatency.Milliseconds(),
+			MatchHeaders: mockMatchHeaders,
+			CORSOrigins:  mockCORSOrigins,
+		}
+		out := cmd.OutOrStdout()
+		m, err := mock.Load(db, config, func(entry models.MockLogEntry) {
+			line := fmt.Sprintf("%s %s %s -> %d", entry.Time.Local().Format("15:04:05"), entry.Method, entry.Path, entry.StatusCode)
+			if entry.RequestName != "" {
+				line += " " + entry.RequestName
+			}
+			if entry.Example != "" {
+				line += " (" + entry.Example + ")"
+			}
+			fmt.Fprintf(out, "%s %dms\n", line, entry.Duration)
+		})
+		if err != nil {
+			return err
+		}
+		server, err := mock.Start(m, mockPort)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Mocking %d request(s) from %q on %s\n", m.Routes(), folder.Name, server.Address())
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return server.Stop(shutdown)
+	},
+}
+
+func init() {
+	mockCmd.Flags().IntVarP(&mockPort, "port", "p", models.DefaultMockPort, "Port to run the mock server on")
+	mockCmd.Flags().DurationVar(&mockLatency, "latency", 0, "Delay every response by this duration, e.g. 250ms")
+	mockCmd.Flags().BoolVar(&mockMatchHeaders, "match-headers", false, "Only match requests that send the saved request headers")
+	mockCmd.Flags().StringSliceVar(&mockCORSOrigins, "cors-origin", nil, "Allow cross-origin requests from this origin, or * for any origin (repeatable)")
+}
+
+func findFolder(db *storage.DB, value string) (*models.Folder, error) {
+	if id, err := strconv.Atoi(value); err == nil {
+		var folder models.Folder
+		if err := db.GetFolder(id, &folder); err != nil {
+			return nil, err
+		}
+		return &folder, nil
+	}
+	folders, err := db.GetFolders()
+	if err != nil {
+		return nil, err
+	}
+	var matches []models.Folder
+	for _, folder := range folders {
+		if strings.EqualFold(folder.Name, value) {
+			matches = append(matches, folder)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("folder %q not found", value)
+	case 1:
+		return &matches[0], nil
+	default:
+		return nil, fmt.Errorf("folder name %q is ambiguous, use the folder ID instead", value)
+	}
+}
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hc/hc/internal/models"
+	"github.com/hc/hc/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+func TestMockCommandFlags(t *testing.T) {
+	if mockCmd.Use != "mock <folder>" {
+		t.Errorf("Expected Use to be 'mock <folder>', got %s", mockCmd.Use)
+	}
+	for _, name := range []string{"port", "latency", "match-headers", "cors-origin"} {
+		if mockCmd.Flag(name) == nil {
+			t.Errorf("%s flag not defined", name)
+		}
+	}
+}
+
+func TestFindFolder(t *testing.T) {
+	t.Setenv("HC_TEST_DB_PATH", filepath.Join(t.TempDir(), "test.db"))
+	db, err := storage.InitDB()
+	if err != nil {
+		t.Fatalf("InitDB() error = %v", err)
+	}
+	defer db.Close()
+	users := &models.Folder{Name: "Users"}
+	for _, folder := range []*models.Folder{users, {Name: "Shared"}, {Name: "shared"}} {
+		if err := db.CreateFolder(folder); err != nil {
+			t.Fatalf("CreateFolder() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		value   string
+		wantID  int
+		wantErr bool
+	}{
+		{name: "By ID", value: strconv.Itoa(users.ID), wantID: users.ID},
+		{name: "By name", value: "users", wantID: users.ID},
+		{name: "Ambiguous name", value: "Shared", wantErr: true},
+		{name: "Missing name", value: "Orders", wantErr: true},
+		{name: "Missing ID", value: "999", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			folder, err := findFolder(db, tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if !tt.wantErr && folder.ID != tt.wantID {
+				t.Errorf("Expected folder %d, got %d", tt.wantID, folder.ID)
+			}
+		})
+	}
+}
+
+func TestMockCommand(t *testing.T) {
+	t.Setenv("HC_TEST_DB_PATH", filepath.Join(t.TempDir(), "test.db"))
+	db, err := storage.InitDB()
+	if err != nil {
+		t.Fatalf("InitDB() error = %v", err)
+	}
+	folder := &models.Folder{Name: "Users"}
+	if err := db.CreateFolder(folder); err != nil {
+		t.Fatalf("CreateFolder() error = %v", err)
+	}
+	request := &models.Request{Name: "Get user", FolderID: &folder.ID, Method: "GET", URL: "https://example.com/users/:id", Examples: []models.ExampleResponse{{Name: "Found", StatusCode: 200, Body: `{"id": 1}`}}}
+	if err := db.CreateRequest(request); err != nil {
+		t.Fatalf("CreateRequest() error = %v", err)
+	}
+	db.Close()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	rootCmd := &cobra.Command{Use: "hc", SilenceErrors: true}
+	AddToRoot(rootCmd)
+	var out bytes.Buffer
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs([]string{"mock", "Users", "--port", strconv.Itoa(port)})
+	done := make(chan error, 1)
+	go func() {
+		done <- rootCmd.ExecuteContext(ctx)
+	}()
+
+	url := "http://127.0.0.1:" + strconv.Itoa(port) + "/users/7"
+	var resp *http.Response
+	for i := 0; i < 50; i++ {
+		resp, err = http.Get(url)
+		if err == nil {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || string(body) != `{"id": 1}` {
+		t.Errorf("Expected saved example, got %d %q", resp.StatusCode, body)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	for _, want := range []string{`Mocking 1 request(s) from "Users"`, "GET /users/7 -> 200 Get user (Found)"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected output to contain %q, got %q", want, out.String())
+		}
+	}
+}
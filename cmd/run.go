@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/hc/hc/internal/models"
+	"github.com/hc/hc/internal/proxy"
+	"github.com/hc/hc/internal/runner"
+	"github.com/hc/hc/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var (
+	runTags      []string
+	runFolder    int
+	runFavorites bool
+)
+
+var runCmd = &cobra.Command{
+	Use:          "run",
+	Short:        "Run saved requests",
+	Long:         `Run saved requests in collection order, optionally selecting them by tag, folder or favorite flag.`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		db, err := storage.InitDB()
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+		client := proxy.NewClient()
+		settings, err := db.GetSettings()
+		if err != nil {
+			return err
+		}
+		if err := client.ApplySettings(settings); err != nil {
+			return err
+		}
+		filter := &models.RequestFilter{Tags: runTags, Favorite: runFavorites}
+		if cmd.Flags().Changed("folder") {
+			filter.FolderID = &runFolder
+		}
+		r := runner.New(db, client)
+		requests, err := r.Select(filter)
+		if err != nil {
+			return err
+		}
+		out := cmd.OutOrStdout()
+		if len(requests) == 0 {
+			fmt.Fprintln(out, "No requests matched")
+			return nil
+		}
+		var passed, failed, skipped int
+		r.Run(cmd.Context(), requests, func(result runner.Result) {
+			switch {
+			case result.Skipped:
+				skipped++
+				fmt.Fprintf(out, "SKIP  %s (%s requests are not supported)\n", result.Request.Name, result.Request.Type)
+			case result.Err != nil:
+				failed++
+				fmt.Fprintf(out, "FAIL  %s %s %s: %v\n", result.Request.Name, result.Request.Method, result.Request.URL, result.Err)
+			default:
+				status := "PASS"
+				if result.Passed() {
+					passed++
+				} else {
+					failed++
+					status = "FAIL"
+				}
+				fmt.Fprintf(out, "%s  %s %s %s -> %d (%d ms)\n", status, result.Request.Name, result.Request.Method, result.Request.URL, result.Response.StatusCode, result.Response.Duration)
+				for _, warning := range result.Response.Warnings {
+					fmt.Fprintf(out, "      warning: %s\n", warning)
+				}
+			}
+		})
+		fmt.Fprintf(out, "%d passed, %d failed, %d skipped\n", passed, failed, skipped)
+		if failed > 0 {
+			return fmt.Errorf("%d request(s) failed", failed)
+		}
+		return nil
+	},
+}
+
+func init() {
+	runCmd.Flags().StringSliceVarP(&runTags, "tag", "t", nil, "Only run requests with all of these tags")
+	runCmd.Flags().IntVarP(&runFolder, "folder", "f", 0, "Only run requests inside this folder and its subfolders")
+	runCmd.Flags().BoolVar(&runFavorites, "favorites", false, "Only run favorite requests")
+}
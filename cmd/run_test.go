@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hc/hc/internal/models"
+	"github.com/hc/hc/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+func TestRunCommandFlags(t *testing.T) {
+	if runCmd.Use != "run" {
+		t.Errorf("Expected Use to be 'run', got %s", runCmd.Use)
+	}
+	for _, name := range []string{"tag", "folder", "favorites"} {
+		if runCmd.Flag(name) == nil {
+			t.Errorf("%s flag not defined", name)
+		}
+	}
+}
+
+func TestRunCommand(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/broken" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	t.Setenv("HC_TEST_DB_PATH", filepath.Join(t.TempDir(), "test.db"))
+	db, err := storage.InitDB()
+	if err != nil {
+		t.Fatalf("InitDB() error = %v", err)
+	}
+	for _, request := range []*models.Request{
+		{Name: "Health", Method: "GET", URL: ts.URL + "/health", Tags: []string{"smoke"}},
+		{Name: "Broken", Method: "GET", URL: ts.URL + "/broken", Tags: []string{"flaky"}},
+		{Name: "Frobnicate", Method: "FROBNICATE", URL: ts.URL + "/cache", Tags: []string{"custom"}},
+	} {
+		if err := db.CreateRequest(request); err != nil {
+			t.Fatalf("CreateRequest() error = %v", err)
+		}
+	}
+	db.Close()
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantErr    bool
+		wantOutput string
+	}{
+		{name: "Passing tag", args: []string{"run", "--tag", "smoke"}, wantOutput: "1 passed, 0 failed, 0 skipped"},
+		{name: "Failing tag", args: []string{"run", "--tag", "flaky"}, wantErr: true, wantOutput: "0 passed, 1 failed, 0 skipped"},
+		{name: "Warnings", args: []string{"run", "--tag", "custom"}, wantOutput: "warning: FROBNICATE is not a registered method"},
+		{name: "No match", args: []string{"run", "--tag", "missing"}, wantOutput: "No requests matched"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runTags = nil
+			rootCmd := &cobra.Command{Use: "hc", SilenceErrors: true}
+			AddToRoot(rootCmd)
+			var out bytes.Buffer
+			rootCmd.SetOut(&out)
+			rootCmd.SetArgs(tt.args)
+			err := rootCmd.ExecuteContext(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if !strings.Contains(out.String(), tt.wantOutput) {
+				t.Errorf("Expected output to contain %q, got %q", tt.wantOutput, out.String())
+			}
+		})
+	}
+}
@@ -52,4 +52,8 @@ func init() {
 }
 func AddToRoot(rootCmd *cobra.Command) {
 	rootCmd.AddCommand(serveCmd)
+	rootCmd.AddCommand(trashCmd)
+	rootCmd.AddCommand(runCmd)
+	rootCmd.AddCommand(docsCmd)
+	rootCmd.AddCommand(mockCmd)
 }
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/hc/hc/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var trashCmd = &cobra.Command{
+	Use:   "trash",
+	Short: "Manage deleted requests and folders",
+	Long:  `Manage requests and folders that were moved to the trash.`,
+}
+
+var trashEmptyCmd = &cobra.Command{
+	Use:   "empty",
+	Short: "Permanently delete everything in the trash",
+	Long:  `Permanently delete all requests and folders in the trash. This cannot be undone.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		db, err := storage.InitDB()
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+		count, err := db.EmptyTrash()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Permanently deleted %d item(s)\n", count)
+		return nil
+	},
+}
+
+func init() {
+	trashCmd.AddCommand(trashEmptyCmd)
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/hc/hc/internal/models"
+	"github.com/hc/hc/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+func TestTrashCommand(t *testing.T) {
+	if trashCmd.Use != "trash" {
+		t.Errorf("Expected Use to be 'trash', got %s", trashCmd.Use)
+	}
+	found := false
+	for _, cmd := range trashCmd.Commands() {
+		if cmd == trashEmptyCmd {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("empty command was not added to trash command")
+	}
+
+	rootCmd := &cobra.Command{Use: "test"}
+	AddToRoot(rootCmd)
+	if cmd, _, err := rootCmd.Find([]string{"trash", "empty"}); err != nil || cmd != trashEmptyCmd {
+		t.Errorf("Expected trash empty command to be registered, got %v", err)
+	}
+}
+
+func TestTrashEmptyCommand(t *testing.T) {
+	t.Setenv("HC_TEST_DB_PATH", filepath.Join(t.TempDir(), "test.db"))
+	db, err := storage.InitDB()
+	if err != nil {
+		t.Fatalf("InitDB() error = %v", err)
+	}
+	kept := &models.Request{Name: "Kept", Method: "GET", URL: "https://example.com"}
+	deleted := &models.Request{Name: "Deleted", Method: "GET", URL: "https://example.com"}
+	for _, request := range []*models.Request{kept, deleted} {
+		if err := db.CreateRequest(request); err != nil {
+			t.Fatalf("CreateRequest() error = %v", err)
+		}
+	}
+	if err := db.DeleteRequest(deleted.ID); err != nil {
+		t.Fatalf("DeleteRequest() error = %v", err)
+	}
+	db.Close()
+
+	var out bytes.Buffer
+	trashEmptyCmd.SetOut(&out)
+	defer trashEmptyCmd.SetOut(nil)
+	if err := trashEmptyCmd.RunE(trashEmptyCmd, nil); err != nil {
+		t.Fatalf("RunE() error = %v", err)
+	}
+	if out.String() != "Permanently deleted 1 item(s)\n" {
+		t.Errorf("Expected output %q, got %q", "Permanently deleted 1 item(s)\n", out.String())
+	}
+
+	db, err = storage.InitDB()
+	if err != nil {
+		t.Fatalf("InitDB() error = %v", err)
+	}
+	defer db.Close()
+	requests, err := db.GetRequests()
+	if err != nil {
+		t.Fatalf("GetRequests() error = %v", err)
+	}
+	if len(requests) != 1 || requests[0].ID != kept.ID {
+		t.Errorf("Expected only request %d to remain, got %+v", kept.ID, requests)
+	}
+}
@@ -0,0 +1,273 @@
+package docs
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/hc/hc/internal/models"
+	"github.com/hc/hc/internal/proxy"
+	"github.com/hc/hc/internal/storage"
+	"github.com/yuin/goldmark"
+)
+
+const (
+	defaultTitle        = "API Reference"
+	redactedHeaderValue = "<value>"
+)
+
+//go:embed docs.html.tmpl
+var pageTemplate string
+
+var tmpl = template.Must(template.New("docs").Parse(pageTemplate))
+
+type page struct {
+	Title       string
+	Description template.HTML
+	Folders     []folderSection
+	Requests    []requestSection
+}
+
+type folderSection struct {
+	ID          int
+	Name        string
+	Description template.HTML
+	Folders     []folderSection
+	Requests    []requestSection
+}
+
+type requestSection struct {
+	ID          int
+	Name        string
+	Type        string
+	Method      string
+	URL         string
+	Description template.HTML
+	Tags        []string
+	Auth        string
+	Headers     []header
+	Body        string
+	Curl        string
+	Examples    []exampleSection
+}
+
+type exampleSection struct {
+	Name       string
+	StatusCode int
+	Headers    []header
+	Body       string
+}
+
+type header struct {
+	Name  string
+	Value string
+}
+
+func Generate(db *storage.DB, folderID *int, w io.Writer) error {
+	tree, err := db.GetTree()
+	if err != nil {
+		return err
+	}
+	if folderID == nil {
+		return Render(w, defaultTitle, "", tree, nil)
+	}
+	folder := findFolder(tree.Folders, *folderID)
+	if folder == nil {
+		return fmt.Errorf("folder not found")
+	}
+	var ancestors []models.Folder
+	if folder.ParentID != nil {
+		ancestors, err = db.GetFolderChain(*folder.ParentID)
+		if err != nil {
+			return err
+		}
+	}
+	subtree := &models.Tree{Folders: folder.Folders, Requests: folder.Requests}
+	return Render(w, folder.Name, folder.Description, subtree, append(ancestors, folder.Folder))
+}
+
+func Render(w io.Writer, title, description string, tree *models.Tree, ancestors []models.Folder) error {
+	chain := withoutVariables(ancestors)
+	p := page{
+		Title:       title,
+		Description: markdown(description),
+		Folders:     folderSections(tree.Folders, chain),
+		Requests:    requestSections(tree.Requests, chain),
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, p); err != nil {
+		return err
+	}
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+func findFolder(folders []models.TreeFolder, id int) *models.TreeFolder {
+	for i := range folders {
+		if folders[i].ID == id {
+			return &folders[i]
+		}
+		if found := findFolder(folders[i].Folders, id); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func withoutVariables(folders []models.Folder) []models.Folder {
+	copied := make([]models.Folder, len(folders))
+	for i, folder := range folders {
+		folder.Variables = nil
+		copied[i] = folder
+	}
+	return copied
+}
+
+func folderSections(folders []models.TreeFolder, chain []models.Folder) []folderSection {
+	sections := make([]folderSection, 0, len(folders))
+	for _, folder := range folders {
+		folderChain := append(append([]models.Folder{}, chain...), withoutVariables([]models.Folder{folder.Folder})...)
+		sections = append(sections, folderSection{
+			ID:          folder.ID,
+			Name:        folder.Name,
+			Description: markdown(folder.Description),
+			Folders:     folderSections(folder.Folders, folderChain),
+			Requests:    requestSections(folder.Requests, folderChain),
+		})
+	}
+	return sections
+}
+
+func requestSections(requests []models.Request, chain []models.Folder) []requestSection {
+	sections := make([]requestSection, 0, len(requests))
+	for _, request := range requests {
+		effective := proxy.ResolveRequest(&request, chain)
+		section := requestSection{
+			ID:          request.ID,
+			Name:        request.Name,
+			Type:        request.Type,
+			Method:      effective.Method,
+			URL:         effective.URL,
+			Description: markdown(request.Description),
+			Tags:        request.Tags,
+			Auth:        describeAuth(effective.Auth),
+			Body:        effective.Body,
+		}
+		if request.GraphQL != nil {
+			section.Body = request.GraphQL.Query
+		}
+		section.Headers = sortedHeaders(redactHeaders(effective.Headers, effective.Auth))
+		if request.Type != models.RequestTypeWebSocket && request.Type != models.RequestTypeGRPC {
+			section.Curl = curlCommand(&effective.Request, section.Headers)
+		}
+		for _, example := range request.Examples {
+			section.Examples = append(section.Examples, exampleSection{
+				Name:       example.Name,
+				StatusCode: example.StatusCode,
+				Headers:    sortedHeaders(redactHeaders(example.Headers, nil)),
+				Body:       example.Body,
+			})
+		}
+		sections = append(sections, section)
+	}
+	return sections
+}
+
+func sortedHeaders(values map[string]string) []header {
+	headers := make([]header, 0, len(values))
+	for name, value := range values {
+		headers = append(headers, header{Name: name, Value: value})
+	}
+	sort.Slice(headers, func(i, j int) bool {
+		return headers[i].Name < headers[j].Name
+	})
+	return headers
+}
+
+func redactHeaders(values map[string]string, auth *models.Auth) map[string]string {
+	redacted := make(map[string]string, len(values))
+	for name, value := range values {
+		if proxy.IsSensitiveHeader(name) || isAPIKeyHeader(auth, name) {
+			value = redactedHeaderValue
+		}
+		redacted[name] = value
+	}
+	return redacted
+}
+
+func isAPIKeyHeader(auth *models.Auth, name string) bool {
+	return auth != nil && auth.Type == models.AuthTypeAPIKey && auth.In != models.AuthInQuery && strings.EqualFold(auth.Key, name)
+}
+
+func markdown(source string) template.HTML {
+	if strings.TrimSpace(source) == "" {
+		return ""
+	}
+	var buf bytes.Buffer
+	if err := goldmark.Convert([]byte(source), &buf); err != nil {
+		return template.HTML("<p>" + template.HTMLEscapeString(source) + "</p>")
+	}
+	return template.HTML(buf.String())
+}
+
+func describeAuth(auth *models.Auth) string {
+	if auth == nil {
+		return ""
+	}
+	switch auth.Type {
+	case models.AuthTypeBasic:
+		return "Basic authentication"
+	case models.AuthTypeBearer:
+		return "Bearer token"
+	case models.AuthTypeAPIKey:
+		if auth.In == models.AuthInQuery {
+			return "API key in query parameter " + auth.Key
+		}
+		return "API key in header " + auth.Key
+	default:
+		return ""
+	}
+}
+
+func curlCommand(request *models.Request, headers []header) string {
+	target := request.URL
+	args := []string{"curl"}
+	if request.Method != "" && (request.Method != "GET" || request.Body != "") {
+		args = append(args, "-X "+request.Method)
+	}
+	for _, h := range headers {
+		args = append(args, "-H "+shellQuote(h.Name+": "+h.Value))
+	}
+	if auth := request.Auth; auth != nil {
+		switch auth.Type {
+		case models.AuthTypeBasic:
+			args = append(args, "-u "+shellQuote("<username>:<password>"))
+		case models.AuthTypeBearer:
+			args = append(args, "-H "+shellQuote("Authorization: Bearer <token>"))
+		case models.AuthTypeAPIKey:
+			if auth.In == models.AuthInQuery {
+				separator := "?"
+				if strings.Contains(target, "?") {
+					separator = "&"
+				}
+				target += separator + url.QueryEscape(auth.Key) + "=<value>"
+			} else {
+				args = append(args, "-H "+shellQuote(auth.Key+": <value>"))
+			}
+		}
+	}
+	if request.Body != "" {
+		args = append(args, "--data-raw "+shellQuote(request.Body))
+	}
+	args = append(args, shellQuote(target))
+	return strings.Join(args, " \\\n  ")
+}
+
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
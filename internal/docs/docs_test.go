@@ -0,0 +1,181 @@
+package docs
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hc/hc/internal/models"
+	"github.com/hc/hc/internal/storage"
+)
+
+func setupTestDB(t *testing.T) *storage.DB {
+	t.Helper()
+	t.Setenv("HC_TEST_DB_PATH", filepath.Join(t.TempDir(), "test.db"))
+	db, err := storage.InitDB()
+	if err != nil {
+		t.Fatalf("InitDB() error = %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestGenerate(t *testing.T) {
+	db := setupTestDB(t)
+	api := &models.Folder{
+		Name:        "Billing API",
+		Description: "Endpoints for **invoices**.",
+		BaseURL:     "https://{{host}}/v1",
+		Headers:     map[string]string{"Accept": "application/json", "Cookie": "sid=hunter2"},
+		Variables:   map[string]string{"host": "secret.internal"},
+		Auth:        &models.Auth{Type: models.AuthTypeBearer, Token: "s3cr3t"},
+	}
+	if err := db.CreateFolder(api); err != nil {
+		t.Fatalf("CreateFolder() error = %v", err)
+	}
+	other := &models.Folder{Name: "Other"}
+	if err := db.CreateFolder(other); err != nil {
+		t.Fatalf("CreateFolder() error = %v", err)
+	}
+	for _, request := range []*models.Request{
+		{
+			Name:        "Create invoice",
+			Description: "Creates an invoice.\n\n<script>alert(1)</script>",
+			FolderID:    &api.ID,
+			Method:      "POST",
+			URL:         "/invoices",
+			Headers:     map[string]string{"X-Api-Key": "hunter2-key"},
+			Body:        `{"note": "it's due"}`,
+			Tags:        []string{"billing"},
+			Examples:    []models.ExampleResponse{{Name: "Invalid amount", StatusCode: 422, Headers: map[string]string{"Retry-After": "120", "Set-Cookie": "sid=hunter2"}, Body: "amount must be positive"}},
+		},
+		{Name: "Unrelated", FolderID: &other.ID, Method: "GET", URL: "https://example.com"},
+	} {
+		if err := db.CreateRequest(request); err != nil {
+			t.Fatalf("CreateRequest() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		folderID *int
+		want     []string
+		notWant  []string
+	}{
+		{
+			name:    "Whole collection",
+			want:    []string{"<title>API Reference</title>", `id="folder-` + strconv.Itoa(api.ID) + `"`, "Unrelated"},
+			notWant: []string{"s3cr3t", "secret.internal", "hunter2"},
+		},
+		{
+			name:     "Single folder",
+			folderID: &api.ID,
+			want: []string{
+				"<title>Billing API</title>",
+				"<strong>invoices</strong>",
+				"https://{{host}}/v1/invoices",
+				"Bearer token",
+				"&#39;Authorization: Bearer &lt;token&gt;&#39;",
+				"&#39;Cookie: &lt;value&gt;&#39;",
+				"&#39;X-Api-Key: &lt;value&gt;&#39;",
+				`&#39;{&#34;note&#34;: &#34;it&#39;\&#39;&#39;s due&#34;}&#39;`,
+				`<span class="tag">billing</span>`,
+				`href="#request-`,
+				"Example response: Invalid amount",
+				`<span class="status error">422</span>`,
+				"Retry-After",
+				"amount must be positive",
+			},
+			notWant: []string{"Unrelated", "s3cr3t", "secret.internal", "<script>", "hunter2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Generate(db, tt.folderID, &buf); err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			html := buf.String()
+			for _, want := range tt.want {
+				if !strings.Contains(html, want) {
+					t.Errorf("Expected output to contain %q", want)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(html, notWant) {
+					t.Errorf("Expected output not to contain %q", notWant)
+				}
+			}
+		})
+	}
+
+	missing := 999
+	if err := Generate(db, &missing, &bytes.Buffer{}); err == nil {
+		t.Error("Expected error for missing folder")
+	}
+}
+
+func TestRedactHeaders(t *testing.T) {
+	apiKey := &models.Auth{Type: models.AuthTypeAPIKey, Key: "X-Billing", Value: "k3y"}
+	tests := []struct {
+		name    string
+		headers map[string]string
+		auth    *models.Auth
+		want    map[string]string
+	}{
+		{name: "Sensitive headers", headers: map[string]string{"Authorization": "Bearer t", "Accept": "*/*"}, want: map[string]string{"Authorization": redactedHeaderValue, "Accept": "*/*"}},
+		{name: "API key header", headers: map[string]string{"x-billing": "k3y"}, auth: apiKey, want: map[string]string{"x-billing": redactedHeaderValue}},
+		{name: "API key in query", headers: map[string]string{"X-Billing": "k3y"}, auth: &models.Auth{Type: models.AuthTypeAPIKey, Key: "X-Billing", In: models.AuthInQuery}, want: map[string]string{"X-Billing": "k3y"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := redactHeaders(tt.headers, tt.auth)
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCurlCommand(t *testing.T) {
+	tests := []struct {
+		name    string
+		request models.Request
+		headers []header
+		want    string
+	}{
+		{
+			name:    "Simple GET",
+			request: models.Request{Method: "GET", URL: "https://example.com"},
+			want:    "curl \\\n  'https://example.com'",
+		},
+		{
+			name:    "POST with header and body",
+			request: models.Request{Method: "POST", URL: "https://example.com", Body: "a'b"},
+			headers: []header{{Name: "Content-Type", Value: "text/plain"}},
+			want:    "curl \\\n  -X POST \\\n  -H 'Content-Type: text/plain' \\\n  --data-raw 'a'\\''b' \\\n  'https://example.com'",
+		},
+		{
+			name:    "API key in query",
+			request: models.Request{Method: "GET", URL: "https://example.com?a=1", Auth: &models.Auth{Type: models.AuthTypeAPIKey, Key: "api_key", Value: "secret", In: models.AuthInQuery}},
+			want:    "curl \\\n  'https://example.com?a=1&api_key=<value>'",
+		},
+		{
+			name:    "Basic auth",
+			request: models.Request{Method: "GET", URL: "https://example.com", Auth: &models.Auth{Type: models.AuthTypeBasic, Username: "user", Password: "secret"}},
+			want:    "curl \\\n  -u '<username>:<password>' \\\n  'https://example.com'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := curlCommand(&tt.request, tt.headers)
+			if got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,236 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hc/hc/internal/models"
+	"github.com/hc/hc/internal/proxy"
+)
+
+const IntrospectionQuery = `query IntrospectionQuery {
+  __schema {
+    queryType { name }
+    mutationType { name }
+    subscriptionType { name }
+    types { ...FullType }
+    directives {
+      name
+      description
+      locations
+      args { ...InputValue }
+    }
+  }
+}
+
+fragment FullType on __Type {
+  kind
+  name
+  description
+  fields(includeDeprecated: true) {
+    name
+    description
+    args { ...InputValue }
+    type { ...TypeRef }
+    isDeprecated
+    deprecationReason
+  }
+  inputFields { ...InputValue }
+  interfaces { ...TypeRef }
+  enumValues(includeDeprecated: true) {
+    name
+    description
+    isDeprecated
+    deprecationReason
+  }
+  possibleTypes { ...TypeRef }
+}
+
+fragment InputValue on __InputValue {
+  name
+  description
+  type { ...TypeRef }
+  defaultValue
+}
+
+fragment TypeRef on __Type {
+  kind
+  name
+  ofType {
+    kind
+    name
+    ofType {
+      kind
+      name
+      ofType {
+        kind
+        name
+        ofType {
+          kind
+          name
+          ofType {
+            kind
+            name
+            ofType {
+              kind
+              name
+              ofType {
+                kind
+                name
+              }
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+
+type introspectionSchema struct {
+	QueryType        *namedRef                `json:"queryType"`
+	MutationType     *namedRef                `json:"mutationType"`
+	SubscriptionType *namedRef                `json:"subscriptionType"`
+	Types            []introspectionType      `json:"types"`
+	Directives       []introspectionDirective `json:"directives"`
+}
+
+type namedRef struct {
+	Name string `json:"name"`
+}
+
+type introspectionType struct {
+	Kind          string                   `json:"kind"`
+	Name          string                   `json:"name"`
+	Description   string                   `json:"description"`
+	Fields        []introspectionField     `json:"fields"`
+	InputFields   []introspectionValue     `json:"inputFields"`
+	Interfaces    []typeRef                `json:"interfaces"`
+	EnumValues    []introspectionEnumValue `json:"enumValues"`
+	PossibleTypes []typeRef                `json:"possibleTypes"`
+}
+
+type introspectionField struct {
+	Name              string               `json:"name"`
+	Description       string               `json:"description"`
+	Args              []introspectionValue `json:"args"`
+	Type              typeRef              `json:"type"`
+	IsDeprecated      bool                 `json:"isDeprecated"`
+	DeprecationReason *string              `json:"deprecationReason"`
+}
+
+type introspectionValue struct {
+	Name         string  `json:"name"`
+	Description  string  `json:"description"`
+	Type         typeRef `json:"type"`
+	DefaultValue *string `json:"defaultValue"`
+}
+
+type introspectionEnumValue struct {
+	Name              string  `json:"name"`
+	Description       string  `json:"description"`
+	IsDeprecated      bool    `json:"isDeprecated"`
+	DeprecationReason *string `json:"deprecationReason"`
+}
+
+type introspectionDirective struct {
+	Name        string               `json:"name"`
+	Description string               `json:"description"`
+	Locations   []string             `json:"locations"`
+	Args        []introspectionValue `json:"args"`
+}
+
+type typeRef struct {
+	Kind   string   `json:"kind"`
+	Name   *string  `json:"name"`
+	OfType *typeRef `json:"ofType"`
+}
+
+func (t typeRef) String() string {
+	switch t.Kind {
+	case "NON_NULL":
+		if t.OfType != nil {
+			return t.OfType.String() + "!"
+		}
+	case "LIST":
+		if t.OfType != nil {
+			return "[" + t.OfType.String() + "]"
+		}
+	}
+	if t.Name != nil {
+		return *t.Name
+	}
+	return ""
+}
+
+func (t typeRef) namedType() string {
+	if t.OfType != nil {
+		return t.OfType.namedType()
+	}
+	if t.Name != nil {
+		return *t.Name
+	}
+	return ""
+}
+
+type graphQLError struct {
+	Message string `json:"message"`
+}
+
+func Introspect(ctx context.Context, client *proxy.Client, url string, headers map[string]string) (json.RawMessage, error) {
+	resp, err := client.ExecuteRequestContext(ctx, &models.Request{
+		Method:  "POST",
+		URL:     url,
+		Headers: headers,
+		GraphQL: &models.GraphQLBody{
+			Query:         IntrospectionQuery,
+			OperationName: "IntrospectionQuery",
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("introspection request returned status %d", resp.StatusCode)
+	}
+	return ParseIntrospectionResponse([]byte(resp.Body))
+}
+
+func ParseIntrospectionResponse(body []byte) (json.RawMessage, error) {
+	var result struct {
+		Data *struct {
+			Schema json.RawMessage `json:"__schema"`
+		} `json:"data"`
+		Errors []graphQLError `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, errors.New("introspection response is not valid JSON")
+	}
+	if result.Data == nil || len(result.Data.Schema) == 0 || string(result.Data.Schema) == "null" {
+		if len(result.Errors) > 0 {
+			messages := make([]string, len(result.Errors))
+			for i, e := range result.Errors {
+				messages[i] = e.Message
+			}
+			return nil, errors.New("introspection failed: " + strings.Join(messages, "; "))
+		}
+		return nil, errors.New("introspection response does not contain a schema")
+	}
+	if _, err := parseSchema(result.Data.Schema); err != nil {
+		return nil, err
+	}
+	return result.Data.Schema, nil
+}
+
+func parseSchema(raw json.RawMessage) (*introspectionSchema, error) {
+	var schema introspectionSchema
+	if err := json.Unmarshal(raw, &schema); err != nil {
+		return nil, errors.New("invalid introspection schema: " + err.Error())
+	}
+	if schema.QueryType == nil || schema.QueryType.Name == "" {
+		return nil, errors.New("invalid introspection schema: missing query type")
+	}
+	return &schema, nil
+}
@@ -0,0 +1,111 @@
+package graphql
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hc/hc/internal/proxy"
+)
+
+func TestTypeRefString(t *testing.T) {
+	var ref typeRef
+	raw := `{"kind": "NON_NULL", "name": null, "ofType": {"kind": "LIST", "name": null, "ofType": {"kind": "SCALAR", "name": "String", "ofType": null}}}`
+	if err := json.Unmarshal([]byte(raw), &ref); err != nil {
+		t.Fatalf("Failed to unmarshal type ref: %v", err)
+	}
+	if got := ref.String(); got != "[String]!" {
+		t.Errorf("String() = %q, want %q", got, "[String]!")
+	}
+	if got := ref.namedType(); got != "String" {
+		t.Errorf("namedType() = %q, want %q", got, "String")
+	}
+}
+
+func TestParseIntrospectionResponse(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantErr string
+	}{
+		{
+			name: "Valid schema",
+			body: `{"data": {"__schema": ` + testSchema + `}}`,
+		},
+		{
+			name:    "GraphQL errors",
+			body:    `{"data": null, "errors": [{"message": "introspection is disabled"}]}`,
+			wantErr: "introspection failed: introspection is disabled",
+		},
+		{
+			name:    "Missing schema",
+			body:    `{"data": {}}`,
+			wantErr: "introspection response does not contain a schema",
+		},
+		{
+			name:    "Not JSON",
+			body:    `<html></html>`,
+			wantErr: "introspection response is not valid JSON",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, err := ParseIntrospectionResponse([]byte(tt.body))
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("Expected error %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseIntrospectionResponse() error = %v", err)
+			}
+			if len(schema) == 0 {
+				t.Error("Expected schema to be returned")
+			}
+		})
+	}
+}
+
+func TestIntrospect(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			Query         string `json:"query"`
+			OperationName string `json:"operationName"`
+		}
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &payload); err != nil {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer token" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if !strings.Contains(payload.Query, "__schema") || payload.OperationName != "IntrospectionQuery" {
+			http.Error(w, "unexpected query", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": {"__schema": ` + testSchema + `}}`))
+	}))
+	defer server.Close()
+
+	client := proxy.NewClient()
+	schema, err := Introspect(t.Context(), client, server.URL, map[string]string{"Authorization": "Bearer token"})
+	if err != nil {
+		t.Fatalf("Introspect() error = %v", err)
+	}
+	if _, err := Summarize(schema); err != nil {
+		t.Errorf("Expected introspected schema to be usable, got %v", err)
+	}
+
+	_, err = Introspect(t.Context(), client, server.URL, nil)
+	if err == nil || err.Error() != "introspection request returned status 401" {
+		t.Errorf("Expected status error, got %v", err)
+	}
+}
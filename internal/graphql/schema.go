@@ -0,0 +1,273 @@
+package graphql
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"github.com/vektah/gqlparser/v2/parser"
+	"github.com/vektah/gqlparser/v2/validator"
+)
+
+type Schema struct {
+	QueryType        string `json:"query_type"`
+	MutationType     string `json:"mutation_type,omitempty"`
+	SubscriptionType string `json:"subscription_type,omitempty"`
+	Types            []Type `json:"types"`
+}
+
+type Type struct {
+	Kind          string     `json:"kind"`
+	Name          string     `json:"name"`
+	Description   string     `json:"description,omitempty"`
+	Fields        []Field    `json:"fields,omitempty"`
+	InputFields   []Argument `json:"input_fields,omitempty"`
+	EnumValues    []string   `json:"enum_values,omitempty"`
+	Interfaces    []string   `json:"interfaces,omitempty"`
+	PossibleTypes []string   `json:"possible_types,omitempty"`
+}
+
+type Field struct {
+	Name              string     `json:"name"`
+	Description       string     `json:"description,omitempty"`
+	Type              string     `json:"type"`
+	Args              []Argument `json:"args,omitempty"`
+	Deprecated        bool       `json:"deprecated,omitempty"`
+	DeprecationReason string     `json:"deprecation_reason,omitempty"`
+}
+
+type Argument struct {
+	Name         string  `json:"name"`
+	Description  string  `json:"description,omitempty"`
+	Type         string  `json:"type"`
+	DefaultValue *string `json:"default_value,omitempty"`
+}
+
+type ValidationError struct {
+	Message string `json:"message"`
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+}
+
+func Summarize(raw json.RawMessage) (*Schema, error) {
+	introspected, err := parseSchema(raw)
+	if err != nil {
+		return nil, err
+	}
+	schema := &Schema{
+		QueryType: introspected.QueryType.Name,
+		Types:     make([]Type, 0, len(introspected.Types)),
+	}
+	if introspected.MutationType != nil {
+		schema.MutationType = introspected.MutationType.Name
+	}
+	if introspected.SubscriptionType != nil {
+		schema.SubscriptionType = introspected.SubscriptionType.Name
+	}
+	for _, t := range introspected.Types {
+		if strings.HasPrefix(t.Name, "__") {
+			continue
+		}
+		summary := Type{
+			Kind:          t.Kind,
+			Name:          t.Name,
+			Description:   t.Description,
+			InputFields:   summarizeArguments(t.InputFields),
+			Interfaces:    typeNames(t.Interfaces),
+			PossibleTypes: typeNames(t.PossibleTypes),
+		}
+		for _, f := range t.Fields {
+			field := Field{
+				Name:        f.Name,
+				Description: f.Description,
+				Type:        f.Type.String(),
+				Args:        summarizeArguments(f.Args),
+				Deprecated:  f.IsDeprecated,
+			}
+			if f.DeprecationReason != nil {
+				field.DeprecationReason = *f.DeprecationReason
+			}
+			summary.Fields = append(summary.Fields, field)
+		}
+		for _, v := range t.EnumValues {
+			summary.EnumValues = append(summary.EnumValues, v.Name)
+		}
+		schema.Types = append(schema.Types, summary)
+	}
+	sort.Slice(schema.Types, func(i, j int) bool {
+		return schema.Types[i].Name < schema.Types[j].Name
+	})
+	return schema, nil
+}
+
+func summarizeArguments(values []introspectionValue) []Argument {
+	var args []Argument
+	for _, v := range values {
+		args = append(args, Argument{
+			Name:         v.Name,
+			Description:  v.Description,
+			Type:         v.Type.String(),
+			DefaultValue: v.DefaultValue,
+		})
+	}
+	return args
+}
+
+func typeNames(refs []typeRef) []string {
+	var names []string
+	for _, ref := range refs {
+		names = append(names, ref.namedType())
+	}
+	return names
+}
+
+func ToSDL(raw json.RawMessage) (string, error) {
+	introspected, err := parseSchema(raw)
+	if err != nil {
+		return "", err
+	}
+	builtinTypes, builtinDirectives, err := preludeNames()
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	b.WriteString("schema {\n")
+	fmt.Fprintf(&b, "  query: %s\n", introspected.QueryType.Name)
+	if introspected.MutationType != nil && introspected.MutationType.Name != "" {
+		fmt.Fprintf(&b, "  mutation: %s\n", introspected.MutationType.Name)
+	}
+	if introspected.SubscriptionType != nil && introspected.SubscriptionType.Name != "" {
+		fmt.Fprintf(&b, "  subscription: %s\n", introspected.SubscriptionType.Name)
+	}
+	b.WriteString("}\n")
+	for _, d := range introspected.Directives {
+		if builtinDirectives[d.Name] {
+			continue
+		}
+		fmt.Fprintf(&b, "\ndirective @%s%s on %s\n", d.Name, argumentsSDL(d.Args), strings.Join(d.Locations, " | "))
+	}
+	for _, t := range introspected.Types {
+		if strings.HasPrefix(t.Name, "__") || builtinTypes[t.Name] {
+			continue
+		}
+		b.WriteString("\n")
+		writeTypeSDL(&b, t)
+	}
+	return b.String(), nil
+}
+
+func writeTypeSDL(b *strings.Builder, t introspectionType) {
+	switch t.Kind {
+	case "SCALAR":
+		fmt.Fprintf(b, "scalar %s\n", t.Name)
+	case "OBJECT", "INTERFACE":
+		keyword := "type"
+		if t.Kind == "INTERFACE" {
+			keyword = "interface"
+		}
+		fmt.Fprintf(b, "%s %s", keyword, t.Name)
+		if interfaces := typeNames(t.Interfaces); len(interfaces) > 0 {
+			fmt.Fprintf(b, " implements %s", strings.Join(interfaces, " & "))
+		}
+		b.WriteString(" {\n")
+		for _, f := range t.Fields {
+			fmt.Fprintf(b, "  %s%s: %s\n", f.Name, argumentsSDL(f.Args), f.Type.String())
+		}
+		b.WriteString("}\n")
+	case "UNION":
+		fmt.Fprintf(b, "union %s = %s\n", t.Name, strings.Join(typeNames(t.PossibleTypes), " | "))
+	case "ENUM":
+		fmt.Fprintf(b, "enum %s {\n", t.Name)
+		for _, v := range t.EnumValues {
+			fmt.Fprintf(b, "  %s\n", v.Name)
+		}
+		b.WriteString("}\n")
+	case "INPUT_OBJECT":
+		fmt.Fprintf(b, "input %s {\n", t.Name)
+		for _, f := range t.InputFields {
+			fmt.Fprintf(b, "  %s\n", inputValueSDL(f))
+		}
+		b.WriteString("}\n")
+	}
+}
+
+func argumentsSDL(args []introspectionValue) string {
+	if len(args) == 0 {
+		return ""
+	}
+	parts := make([]string, len(args))
+	for i, arg := range args {
+		parts[i] = inputValueSDL(arg)
+	}
+	return "(" + strings.Join(parts, ", ") + ")"
+}
+
+func inputValueSDL(v introspectionValue) string {
+	sdl := v.Name + ": " + v.Type.String()
+	if v.DefaultValue != nil {
+		sdl += " = " + *v.DefaultValue
+	}
+	return sdl
+}
+
+func preludeNames() (map[string]bool, map[string]bool, error) {
+	doc, err := parser.ParseSchema(validator.Prelude)
+	if err != nil {
+		return nil, nil, err
+	}
+	types := make(map[string]bool)
+	for _, def := range doc.Definitions {
+		types[def.Name] = true
+	}
+	directives := make(map[string]bool)
+	for _, def := range doc.Directives {
+		directives[def.Name] = true
+	}
+	return types, directives, nil
+}
+
+func LoadSchema(raw json.RawMessage) (*ast.Schema, error) {
+	sdl, err := ToSDL(raw)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := gqlparser.LoadSchema(&ast.Source{Name: "schema.graphql", Input: sdl})
+	if err != nil {
+		return nil, errors.New("failed to load schema: " + err.Error())
+	}
+	return schema, nil
+}
+
+func Validate(schema *ast.Schema, query string) []ValidationError {
+	if schema == nil {
+		_, err := parser.ParseQuery(&ast.Source{Name: "query.graphql", Input: query})
+		if err == nil {
+			return nil
+		}
+		var gqlErr *gqlerror.Error
+		if errors.As(err, &gqlErr) {
+			return validationErrors(gqlerror.List{gqlErr})
+		}
+		return []ValidationError{{Message: err.Error()}}
+	}
+	_, errs := gqlparser.LoadQuery(schema, query)
+	return validationErrors(errs)
+}
+
+func validationErrors(errs gqlerror.List) []ValidationError {
+	var result []ValidationError
+	for _, err := range errs {
+		validationErr := ValidationError{Message: err.Message}
+		if len(err.Locations) > 0 {
+			validationErr.Line = err.Locations[0].Line
+			validationErr.Column = err.Locations[0].Column
+		}
+		result = append(result, validationErr)
+	}
+	return result
+}
@@ -0,0 +1,167 @@
+package graphql
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const testSchema = `{
+  "queryType": {"name": "Query"},
+  "mutationType": null,
+  "subscriptionType": null,
+  "types": [
+    {"kind": "OBJECT", "name": "Query", "fields": [
+      {"name": "user", "args": [
+        {"name": "id", "type": {"kind": "NON_NULL", "name": null, "ofType": {"kind": "SCALAR", "name": "ID", "ofType": null}}, "defaultValue": null}
+      ], "type": {"kind": "OBJECT", "name": "User", "ofType": null}, "isDeprecated": false, "deprecationReason": null},
+      {"name": "users", "args": [
+        {"name": "first", "type": {"kind": "SCALAR", "name": "Int", "ofType": null}, "defaultValue": "10"}
+      ], "type": {"kind": "NON_NULL", "name": null, "ofType": {"kind": "LIST", "name": null, "ofType": {"kind": "NON_NULL", "name": null, "ofType": {"kind": "OBJECT", "name": "User", "ofType": null}}}}, "isDeprecated": false, "deprecationReason": null}
+    ], "interfaces": []},
+    {"kind": "OBJECT", "name": "User", "description": "A registered user", "fields": [
+      {"name": "id", "args": [], "type": {"kind": "NON_NULL", "name": null, "ofType": {"kind": "SCALAR", "name": "ID", "ofType": null}}, "isDeprecated": false, "deprecationReason": null},
+      {"name": "name", "args": [], "type": {"kind": "SCALAR", "name": "String", "ofType": null}, "isDeprecated": false, "deprecationReason": null},
+      {"name": "login", "args": [], "type": {"kind": "SCALAR", "name": "String", "ofType": null}, "isDeprecated": true, "deprecationReason": "Use name"},
+      {"name": "role", "args": [], "type": {"kind": "ENUM", "name": "Role", "ofType": null}, "isDeprecated": false, "deprecationReason": null}
+    ], "interfaces": []},
+    {"kind": "ENUM", "name": "Role", "enumValues": [
+      {"name": "ADMIN", "isDeprecated": false, "deprecationReason": null},
+      {"name": "MEMBER", "isDeprecated": false, "deprecationReason": null}
+    ]},
+    {"kind": "SCALAR", "name": "ID"},
+    {"kind": "SCALAR", "name": "Int"},
+    {"kind": "SCALAR", "name": "String"},
+    {"kind": "SCALAR", "name": "Boolean"},
+    {"kind": "OBJECT", "name": "__Schema", "fields": []}
+  ],
+  "directives": [
+    {"name": "include", "locations": ["FIELD"], "args": [
+      {"name": "if", "type": {"kind": "NON_NULL", "name": null, "ofType": {"kind": "SCALAR", "name": "Boolean", "ofType": null}}, "defaultValue": null}
+    ]}
+  ]
+}`
+
+func TestSummarize(t *testing.T) {
+	schema, err := Summarize(json.RawMessage(testSchema))
+	if err != nil {
+		t.Fatalf("Summarize() error = %v", err)
+	}
+	if schema.QueryType != "Query" {
+		t.Errorf("Expected query type Query, got %q", schema.QueryType)
+	}
+	types := make(map[string]Type)
+	for _, typ := range schema.Types {
+		types[typ.Name] = typ
+	}
+	if _, ok := types["__Schema"]; ok {
+		t.Error("Expected introspection types to be skipped")
+	}
+	query := types["Query"]
+	if len(query.Fields) != 2 {
+		t.Fatalf("Expected 2 query fields, got %d", len(query.Fields))
+	}
+	users := query.Fields[1]
+	if users.Type != "[User!]!" {
+		t.Errorf("Expected users type [User!]!, got %q", users.Type)
+	}
+	if len(users.Args) != 1 || users.Args[0].DefaultValue == nil || *users.Args[0].DefaultValue != "10" {
+		t.Errorf("Expected first argument with default 10, got %+v", users.Args)
+	}
+	login := types["User"].Fields[2]
+	if !login.Deprecated || login.DeprecationReason != "Use name" {
+		t.Errorf("Expected deprecated login field, got %+v", login)
+	}
+	if got := types["Role"].EnumValues; len(got) != 2 || got[0] != "ADMIN" {
+		t.Errorf("Expected Role enum values, got %v", got)
+	}
+}
+
+func TestSummarizeInvalid(t *testing.T) {
+	if _, err := Summarize(json.RawMessage(`{"types": []}`)); err == nil {
+		t.Error("Expected error for schema without query type")
+	}
+}
+
+func TestToSDL(t *testing.T) {
+	sdl, err := ToSDL(json.RawMessage(testSchema))
+	if err != nil {
+		t.Fatalf("ToSDL() error = %v", err)
+	}
+	for _, want := range []string{
+		"schema {\n  query: Query\n}",
+		"users(first: Int = 10): [User!]!",
+		"enum Role {",
+	} {
+		if !strings.Contains(sdl, want) {
+			t.Errorf("Expected SDL to contain %q, got:\n%s", want, sdl)
+		}
+	}
+	for _, unwanted := range []string{"scalar String", "__Schema", "directive @include"} {
+		if strings.Contains(sdl, unwanted) {
+			t.Errorf("Expected SDL not to contain %q, got:\n%s", unwanted, sdl)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	schema, err := LoadSchema(json.RawMessage(testSchema))
+	if err != nil {
+		t.Fatalf("LoadSchema() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		query   string
+		wantErr string
+	}{
+		{
+			name:  "Valid query",
+			query: `query GetUser($id: ID!) { user(id: $id) { id name role } }`,
+		},
+		{
+			name:    "Unknown field",
+			query:   `{ users { email } }`,
+			wantErr: `Cannot query field "email" on type "User".`,
+		},
+		{
+			name:    "Missing required argument",
+			query:   `{ user { id } }`,
+			wantErr: `Field "user" argument "id" of type "ID!" is required, but it was not provided.`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := Validate(schema, tt.query)
+			if tt.wantErr == "" {
+				if len(errs) != 0 {
+					t.Errorf("Expected no errors, got %+v", errs)
+				}
+				return
+			}
+			if len(errs) == 0 {
+				t.Fatal("Expected validation errors")
+			}
+			if errs[0].Message != tt.wantErr {
+				t.Errorf("Expected error %q, got %q", tt.wantErr, errs[0].Message)
+			}
+			if errs[0].Line != 1 || errs[0].Column == 0 {
+				t.Errorf("Expected error location, got %+v", errs[0])
+			}
+		})
+	}
+}
+
+func TestValidateWithoutSchema(t *testing.T) {
+	if errs := Validate(nil, `{ anything { goes } }`); len(errs) != 0 {
+		t.Errorf("Expected syntax-only validation to pass, got %+v", errs)
+	}
+	errs := Validate(nil, "{\n  user {\n}")
+	if len(errs) != 1 {
+		t.Fatalf("Expected 1 syntax error, got %+v", errs)
+	}
+	if errs[0].Line == 0 {
+		t.Errorf("Expected syntax error location, got %+v", errs[0])
+	}
+}
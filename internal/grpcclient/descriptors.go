@@ -0,0 +1,137 @@
+package grpcclient
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/bufbuild/protocompile"
+	"github.com/hc/hc/internal/models"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+type Descriptors struct {
+	files    *protoregistry.Files
+	types    *dynamicpb.Types
+	services []protoreflect.ServiceDescriptor
+}
+
+func NewDescriptors(set *descriptorpb.FileDescriptorSet, serviceNames []string) (*Descriptors, error) {
+	files, err := protodesc.NewFiles(set)
+	if err != nil {
+		return nil, errors.New("invalid descriptors: " + err.Error())
+	}
+	d := &Descriptors{
+		files: files,
+		types: dynamicpb.NewTypes(files),
+	}
+	if serviceNames == nil {
+		files.RangeFiles(func(file protoreflect.FileDescriptor) bool {
+			for i := 0; i < file.Services().Len(); i++ {
+				d.services = append(d.services, file.Services().Get(i))
+			}
+			return true
+		})
+	} else {
+		for _, name := range serviceNames {
+			desc, err := files.FindDescriptorByName(protoreflect.FullName(name))
+			if err != nil {
+				return nil, errors.New("service not found in descriptors: " + name)
+			}
+			service, ok := desc.(protoreflect.ServiceDescriptor)
+			if !ok {
+				return nil, errors.New("not a service: " + name)
+			}
+			d.services = append(d.services, service)
+		}
+	}
+	sort.Slice(d.services, func(i, j int) bool {
+		return d.services[i].FullName() < d.services[j].FullName()
+	})
+	return d, nil
+}
+
+func CompileProtos(ctx context.Context, sources map[string]string) (*Descriptors, error) {
+	if len(sources) == 0 {
+		return nil, errors.New("at least one .proto file is required")
+	}
+	names := make([]string, 0, len(sources))
+	for name := range sources {
+		if !strings.HasSuffix(name, ".proto") {
+			return nil, errors.New("not a .proto file: " + name)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	compiler := protocompile.Compiler{
+		Resolver: protocompile.WithStandardImports(&protocompile.SourceResolver{
+			Accessor: protocompile.SourceAccessorFromMap(sources),
+		}),
+	}
+	compiled, err := compiler.Compile(ctx, names...)
+	if err != nil {
+		return nil, errors.New("failed to compile protos: " + err.Error())
+	}
+	set := &descriptorpb.FileDescriptorSet{}
+	seen := make(map[string]bool)
+	var add func(file protoreflect.FileDescriptor)
+	add = func(file protoreflect.FileDescriptor) {
+		if seen[file.Path()] {
+			return
+		}
+		seen[file.Path()] = true
+		imports := file.Imports()
+		for i := 0; i < imports.Len(); i++ {
+			add(imports.Get(i).FileDescriptor)
+		}
+		set.File = append(set.File, protodesc.ToFileDescriptorProto(file))
+	}
+	for _, file := range compiled {
+		add(file)
+	}
+	return NewDescriptors(set, nil)
+}
+
+func (d *Descriptors) Services() []models.GRPCService {
+	services := make([]models.GRPCService, 0, len(d.services))
+	for _, sd := range d.services {
+		service := models.GRPCService{
+			Name:    string(sd.FullName()),
+			Methods: []models.GRPCMethod{},
+		}
+		for i := 0; i < sd.Methods().Len(); i++ {
+			md := sd.Methods().Get(i)
+			service.Methods = append(service.Methods, models.GRPCMethod{
+				Name:            string(md.Name()),
+				FullName:        string(sd.FullName()) + "/" + string(md.Name()),
+				InputType:       string(md.Input().FullName()),
+				OutputType:      string(md.Output().FullName()),
+				ClientStreaming: md.IsStreamingClient(),
+				ServerStreaming: md.IsStreamingServer(),
+			})
+		}
+		services = append(services, service)
+	}
+	return services
+}
+
+func (d *Descriptors) FindMethod(name string) (protoreflect.MethodDescriptor, error) {
+	name = strings.TrimPrefix(name, "/")
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[:i] + "." + name[i+1:]
+	}
+	desc, err := d.files.FindDescriptorByName(protoreflect.FullName(name))
+	if err != nil {
+		return nil, errors.New("method not found: " + name)
+	}
+	method, ok := desc.(protoreflect.MethodDescriptor)
+	if !ok {
+		return nil, errors.New("not a method: " + name)
+	}
+	return method, nil
+}
@@ -0,0 +1,122 @@
+package grpcclient
+
+import (
+	"strings"
+	"testing"
+)
+
+var testProtos = map[string]string{
+	"types.proto": `syntax = "proto3";
+package test.v1;
+
+import "google/protobuf/timestamp.proto";
+
+message HelloRequest {
+  string name = 1;
+  int32 count = 2;
+}
+
+message HelloReply {
+  string message = 1;
+  google.protobuf.Timestamp at = 2;
+}
+`,
+	"greeter.proto": `syntax = "proto3";
+package test.v1;
+
+import "types.proto";
+
+service Greeter {
+  rpc SayHello(HelloRequest) returns (HelloReply);
+  rpc StreamHellos(HelloRequest) returns (stream HelloReply);
+  rpc Collect(stream HelloRequest) returns (HelloReply);
+}
+`,
+}
+
+func TestCompileProtos(t *testing.T) {
+	descriptors, err := CompileProtos(t.Context(), testProtos)
+	if err != nil {
+		t.Fatalf("CompileProtos() error = %v", err)
+	}
+
+	services := descriptors.Services()
+	if len(services) != 1 || services[0].Name != "test.v1.Greeter" {
+		t.Fatalf("Expected test.v1.Greeter service, got %+v", services)
+	}
+	methods := services[0].Methods
+	if len(methods) != 3 {
+		t.Fatalf("Expected 3 methods, got %d", len(methods))
+	}
+	if methods[0].FullName != "test.v1.Greeter/SayHello" || methods[0].InputType != "test.v1.HelloRequest" {
+		t.Errorf("Unexpected unary method %+v", methods[0])
+	}
+	if !methods[1].ServerStreaming || methods[1].ClientStreaming {
+		t.Errorf("Expected server streaming method, got %+v", methods[1])
+	}
+	if !methods[2].ClientStreaming {
+		t.Errorf("Expected client streaming method, got %+v", methods[2])
+	}
+}
+
+func TestCompileProtosErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		sources map[string]string
+		wantErr string
+	}{
+		{
+			name:    "No files",
+			sources: map[string]string{},
+			wantErr: "at least one .proto file is required",
+		},
+		{
+			name:    "Not a proto file",
+			sources: map[string]string{"service.txt": ""},
+			wantErr: "not a .proto file: service.txt",
+		},
+		{
+			name:    "Missing import",
+			sources: map[string]string{"greeter.proto": testProtos["greeter.proto"]},
+			wantErr: "failed to compile protos",
+		},
+		{
+			name:    "Syntax error",
+			sources: map[string]string{"broken.proto": `syntax = "proto3"; message {`},
+			wantErr: "failed to compile protos",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := CompileProtos(t.Context(), tt.sources)
+			if err == nil || !strings.HasPrefix(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error starting with %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestFindMethod(t *testing.T) {
+	descriptors, err := CompileProtos(t.Context(), testProtos)
+	if err != nil {
+		t.Fatalf("CompileProtos() error = %v", err)
+	}
+
+	for _, name := range []string{"test.v1.Greeter/SayHello", "/test.v1.Greeter/SayHello", "test.v1.Greeter.SayHello"} {
+		method, err := descriptors.FindMethod(name)
+		if err != nil {
+			t.Errorf("FindMethod(%q) error = %v", name, err)
+			continue
+		}
+		if method.Name() != "SayHello" {
+			t.Errorf("FindMethod(%q) = %s", name, method.FullName())
+		}
+	}
+
+	for _, name := range []string{"test.v1.Greeter/Missing", "test.v1.HelloRequest"} {
+		if _, err := descriptors.FindMethod(name); err == nil {
+			t.Errorf("Expected error for %q", name)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package grpcclient
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/hc/hc/internal/models"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+func ValidateTarget(target string) error {
+	if target == "" {
+		return errors.New("target is required")
+	}
+	if strings.Contains(target, "://") && !strings.HasPrefix(target, "unix://") && !strings.HasPrefix(target, "dns://") {
+		return errors.New("target must be host:port, dns:///host:port or unix:///path")
+	}
+	return nil
+}
+
+func ValidateRequest(req *models.GRPCRequest) error {
+	if err := ValidateTarget(req.Target); err != nil {
+		return err
+	}
+	if req.Method == "" {
+		return errors.New("method is required")
+	}
+	return nil
+}
+
+func Dial(req *models.GRPCRequest, dialer func(context.Context, string) (net.Conn, error)) (*grpc.ClientConn, error) {
+	creds := insecure.NewCredentials()
+	if req.TLS {
+		creds = credentials.NewTLS(&tls.Config{InsecureSkipVerify: req.Insecure})
+	}
+	target := req.Target
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(creds)}
+	if dialer != nil && !strings.HasPrefix(target, "unix://") {
+		if !strings.Contains(target, "://") {
+			target = "passthrough:///" + target
+		}
+		opts = append(opts, grpc.WithContextDialer(dialer))
+	}
+	return grpc.NewClient(target, opts...)
+}
+
+func Invoke(ctx context.Context, conn grpc.ClientConnInterface, descriptors *Descriptors, req *models.GRPCRequest) (*models.GRPCResponse, error) {
+	method, err := descriptors.FindMethod(req.Method)
+	if err != nil {
+		return nil, err
+	}
+	if method.IsStreamingClient() {
+		return nil, errors.New("client streaming methods are not supported")
+	}
+	input := dynamicpb.NewMessage(method.Input())
+	if strings.TrimSpace(req.Message) != "" {
+		if err := (protojson.UnmarshalOptions{Resolver: descriptors.types}).Unmarshal([]byte(req.Message), input); err != nil {
+			return nil, errors.New("invalid request message: " + err.Error())
+		}
+	}
+	if len(req.Metadata) > 0 {
+		ctx = metadata.NewOutgoingContext(ctx, metadata.New(req.Metadata))
+	}
+	fullMethod := "/" + string(method.Parent().FullName()) + "/" + string(method.Name())
+	var (
+		header, trailer metadata.MD
+		outputs         []proto.Message
+	)
+	start := time.Now()
+	if method.IsStreamingServer() {
+		outputs, header, trailer, err = invokeServerStream(ctx, conn, fullMethod, method, input)
+	} else {
+		output := dynamicpb.NewMessage(method.Output())
+		err = conn.Invoke(ctx, fullMethod, input, output, grpc.Header(&header), grpc.Trailer(&trailer))
+		if err == nil {
+			outputs = append(outputs, output)
+		}
+	}
+	duration := time.Since(start)
+	st := status.Convert(err)
+	resp := &models.GRPCResponse{
+		StatusCode:   int(st.Code()),
+		Status:       st.Code().String(),
+		Message:      st.Message(),
+		Headers:      copyMetadata(header),
+		Trailers:     copyMetadata(trailer),
+		MessageCount: len(outputs),
+		Duration:     duration.Milliseconds(),
+	}
+	marshal := protojson.MarshalOptions{Resolver: descriptors.types, EmitUnpopulated: true}
+	bodies := make([]string, 0, len(outputs))
+	for _, output := range outputs {
+		data, err := marshal.Marshal(output)
+		if err != nil {
+			return nil, err
+		}
+		bodies = append(bodies, string(data))
+	}
+	switch {
+	case method.IsStreamingServer():
+		resp.Body = "[" + strings.Join(bodies, ",") + "]"
+	case len(bodies) == 1:
+		resp.Body = bodies[0]
+	}
+	return resp, nil
+}
+
+func invokeServerStream(ctx context.Context, conn grpc.ClientConnInterface, fullMethod string, method protoreflect.MethodDescriptor, input proto.Message) ([]proto.Message, metadata.MD, metadata.MD, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := conn.NewStream(ctx, &grpc.StreamDesc{ServerStreams: true}, fullMethod)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if err := stream.SendMsg(input); err != nil && err != io.EOF {
+		return nil, nil, nil, err
+	}
+	if err := stream.CloseSend(); err != nil {
+		return nil, nil, nil, err
+	}
+	var outputs []proto.Message
+	for {
+		output := dynamicpb.NewMessage(method.Output())
+		err := stream.RecvMsg(output)
+		if err != nil {
+			header, _ := stream.Header()
+			if err == io.EOF {
+				err = nil
+			}
+			return outputs, header, stream.Trailer(), err
+		}
+		outputs = append(outputs, output)
+	}
+}
+
+func copyMetadata(md metadata.MD) map[string]string {
+	result := make(map[string]string)
+	for key, values := range md {
+		result[key] = strings.Join(values, ", ")
+	}
+	return result
+}
@@ -0,0 +1,227 @@
+package grpcclient
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/hc/hc/internal/models"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	reflectionalphapb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+func startTestServer(t *testing.T, reflectionVersion string) string {
+	t.Helper()
+	descriptors, err := CompileProtos(t.Context(), testProtos)
+	if err != nil {
+		t.Fatalf("CompileProtos() error = %v", err)
+	}
+	sayHello, _ := descriptors.FindMethod("test.v1.Greeter/SayHello")
+	streamHellos, _ := descriptors.FindMethod("test.v1.Greeter/StreamHellos")
+
+	reply := func(method protoreflect.MethodDescriptor, text string) *dynamicpb.Message {
+		out := dynamicpb.NewMessage(method.Output())
+		out.Set(method.Output().Fields().ByName("message"), protoreflect.ValueOfString(text))
+		return out
+	}
+
+	server := grpc.NewServer()
+	server.RegisterService(&grpc.ServiceDesc{
+		ServiceName: "test.v1.Greeter",
+		HandlerType: (*any)(nil),
+		Methods: []grpc.MethodDesc{{
+			MethodName: "SayHello",
+			Handler: func(_ any, ctx context.Context, dec func(any) error, _ grpc.UnaryServerInterceptor) (any, error) {
+				in := dynamicpb.NewMessage(sayHello.Input())
+				if err := dec(in); err != nil {
+					return nil, err
+				}
+				name := in.Get(sayHello.Input().Fields().ByName("name")).String()
+				if name == "" {
+					return nil, status.Error(codes.InvalidArgument, "name is required")
+				}
+				md, _ := metadata.FromIncomingContext(ctx)
+				grpc.SetHeader(ctx, metadata.Pairs("x-greeting", "hi"))
+				grpc.SetTrailer(ctx, metadata.Pairs("x-done", "yes"))
+				return reply(sayHello, "Hello "+name+strings.Join(md.Get("x-suffix"), "")), nil
+			},
+		}},
+		Streams: []grpc.StreamDesc{{
+			StreamName:    "StreamHellos",
+			ServerStreams: true,
+			Handler: func(_ any, stream grpc.ServerStream) error {
+				in := dynamicpb.NewMessage(streamHellos.Input())
+				if err := stream.RecvMsg(in); err != nil {
+					return err
+				}
+				count := in.Get(streamHellos.Input().Fields().ByName("count")).Int()
+				for i := int64(0); i < count; i++ {
+					if err := stream.SendMsg(reply(streamHellos, "hello")); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		}},
+	}, struct{}{})
+
+	opts := reflection.ServerOptions{
+		Services:           server,
+		DescriptorResolver: descriptors.files,
+		ExtensionResolver:  protoregistry.GlobalTypes,
+	}
+	switch reflectionVersion {
+	case "v1":
+		reflectionpb.RegisterServerReflectionServer(server, reflection.NewServerV1(opts))
+	case "v1alpha":
+		reflectionalphapb.RegisterServerReflectionServer(server, reflection.NewServer(opts))
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+	return listener.Addr().String()
+}
+
+func TestValidateTarget(t *testing.T) {
+	tests := []struct {
+		target  string
+		wantErr bool
+	}{
+		{target: "localhost:50051"},
+		{target: "dns:///api.local:443"},
+		{target: "unix:///tmp/grpc.sock"},
+		{target: "", wantErr: true},
+		{target: "http://localhost:50051", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			err := ValidateTarget(tt.target)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateTarget() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestInvoke(t *testing.T) {
+	target := startTestServer(t, "")
+	descriptors, err := CompileProtos(t.Context(), testProtos)
+	if err != nil {
+		t.Fatalf("CompileProtos() error = %v", err)
+	}
+
+	invoke := func(t *testing.T, req *models.GRPCRequest) (*models.GRPCResponse, error) {
+		t.Helper()
+		req.Target = target
+		conn, err := Dial(req, nil)
+		if err != nil {
+			t.Fatalf("Dial() error = %v", err)
+		}
+		defer conn.Close()
+		return Invoke(t.Context(), conn, descriptors, req)
+	}
+
+	t.Run("Unary", func(t *testing.T) {
+		resp, err := invoke(t, &models.GRPCRequest{
+			Method:   "test.v1.Greeter/SayHello",
+			Message:  `{"name": "hc"}`,
+			Metadata: map[string]string{"x-suffix": "!"},
+		})
+		if err != nil {
+			t.Fatalf("Invoke() error = %v", err)
+		}
+		if resp.StatusCode != 0 || resp.Status != "OK" {
+			t.Errorf("Expected OK status, got %d %s", resp.StatusCode, resp.Status)
+		}
+		var body map[string]any
+		if err := json.Unmarshal([]byte(resp.Body), &body); err != nil {
+			t.Fatalf("Failed to unmarshal body %q: %v", resp.Body, err)
+		}
+		if body["message"] != "Hello hc!" {
+			t.Errorf("Expected greeting, got %v", body)
+		}
+		if _, ok := body["at"]; !ok {
+			t.Errorf("Expected unpopulated fields to be emitted, got %v", body)
+		}
+		if resp.Headers["x-greeting"] != "hi" || resp.Trailers["x-done"] != "yes" {
+			t.Errorf("Expected header and trailer metadata, got %v %v", resp.Headers, resp.Trailers)
+		}
+		if resp.MessageCount != 1 {
+			t.Errorf("Expected 1 message, got %d", resp.MessageCount)
+		}
+	})
+
+	t.Run("Error status", func(t *testing.T) {
+		resp, err := invoke(t, &models.GRPCRequest{Method: "test.v1.Greeter/SayHello"})
+		if err != nil {
+			t.Fatalf("Invoke() error = %v", err)
+		}
+		if resp.StatusCode != int(codes.InvalidArgument) || resp.Status != "InvalidArgument" {
+			t.Errorf("Expected InvalidArgument, got %d %s", resp.StatusCode, resp.Status)
+		}
+		if resp.Message != "name is required" || resp.Body != "" {
+			t.Errorf("Unexpected error response %+v", resp)
+		}
+	})
+
+	t.Run("Server streaming", func(t *testing.T) {
+		resp, err := invoke(t, &models.GRPCRequest{
+			Method:  "test.v1.Greeter/StreamHellos",
+			Message: `{"count": 3}`,
+		})
+		if err != nil {
+			t.Fatalf("Invoke() error = %v", err)
+		}
+		var messages []map[string]any
+		if err := json.Unmarshal([]byte(resp.Body), &messages); err != nil {
+			t.Fatalf("Failed to unmarshal body %q: %v", resp.Body, err)
+		}
+		if len(messages) != 3 || resp.MessageCount != 3 || resp.Status != "OK" {
+			t.Errorf("Expected 3 streamed messages, got %+v", resp)
+		}
+	})
+
+	t.Run("Client streaming unsupported", func(t *testing.T) {
+		if _, err := invoke(t, &models.GRPCRequest{Method: "test.v1.Greeter/Collect"}); err == nil {
+			t.Error("Expected error for client streaming method")
+		}
+	})
+
+	t.Run("Invalid message", func(t *testing.T) {
+		_, err := invoke(t, &models.GRPCRequest{Method: "test.v1.Greeter/SayHello", Message: `{"unknown": 1}`})
+		if err == nil || !strings.HasPrefix(err.Error(), "invalid request message") {
+			t.Errorf("Expected invalid message error, got %v", err)
+		}
+	})
+
+	t.Run("Unavailable", func(t *testing.T) {
+		req := &models.GRPCRequest{Target: "127.0.0.1:1", Method: "test.v1.Greeter/SayHello", Message: `{"name": "hc"}`}
+		conn, err := Dial(req, nil)
+		if err != nil {
+			t.Fatalf("Dial() error = %v", err)
+		}
+		defer conn.Close()
+		resp, err := Invoke(t.Context(), conn, descriptors, req)
+		if err != nil {
+			t.Fatalf("Invoke() error = %v", err)
+		}
+		if resp.Status != "Unavailable" {
+			t.Errorf("Expected Unavailable, got %s", resp.Status)
+		}
+	})
+}
@@ -0,0 +1,117 @@
+package grpcclient
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+const (
+	reflectionMethodV1      = "/grpc.reflection.v1.ServerReflection/ServerReflectionInfo"
+	reflectionMethodV1Alpha = "/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo"
+)
+
+func Reflect(ctx context.Context, conn grpc.ClientConnInterface) (*Descriptors, error) {
+	descriptors, err := reflect(ctx, conn, reflectionMethodV1)
+	if status.Code(err) == codes.Unimplemented {
+		return reflect(ctx, conn, reflectionMethodV1Alpha)
+	}
+	return descriptors, err
+}
+
+type reflectionClient struct {
+	stream grpc.ClientStream
+	files  map[string]*descriptorpb.FileDescriptorProto
+	order  []string
+}
+
+func reflect(ctx context.Context, conn grpc.ClientConnInterface, method string) (*Descriptors, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := conn.NewStream(ctx, &grpc.StreamDesc{ClientStreams: true, ServerStreams: true}, method)
+	if err != nil {
+		return nil, err
+	}
+	rc := &reflectionClient{
+		stream: stream,
+		files:  make(map[string]*descriptorpb.FileDescriptorProto),
+	}
+	resp, err := rc.roundTrip(&reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var services []string
+	for _, service := range resp.GetListServicesResponse().GetService() {
+		if strings.HasPrefix(service.GetName(), "grpc.reflection.") {
+			continue
+		}
+		services = append(services, service.GetName())
+		if err := rc.fetch(&reflectionpb.ServerReflectionRequest{
+			MessageRequest: &reflectionpb.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: service.GetName()},
+		}); err != nil {
+			return nil, err
+		}
+	}
+	stream.CloseSend()
+	set := &descriptorpb.FileDescriptorSet{}
+	for _, name := range rc.order {
+		set.File = append(set.File, rc.files[name])
+	}
+	if services == nil {
+		services = []string{}
+	}
+	return NewDescriptors(set, services)
+}
+
+func (rc *reflectionClient) roundTrip(req *reflectionpb.ServerReflectionRequest) (*reflectionpb.ServerReflectionResponse, error) {
+	if err := rc.stream.SendMsg(req); err != nil {
+		return nil, err
+	}
+	resp := &reflectionpb.ServerReflectionResponse{}
+	if err := rc.stream.RecvMsg(resp); err != nil {
+		return nil, err
+	}
+	if errResp := resp.GetErrorResponse(); errResp != nil {
+		return nil, status.Error(codes.Code(errResp.GetErrorCode()), errResp.GetErrorMessage())
+	}
+	return resp, nil
+}
+
+func (rc *reflectionClient) fetch(req *reflectionpb.ServerReflectionRequest) error {
+	resp, err := rc.roundTrip(req)
+	if err != nil {
+		return err
+	}
+	var missing []string
+	for _, raw := range resp.GetFileDescriptorResponse().GetFileDescriptorProto() {
+		file := &descriptorpb.FileDescriptorProto{}
+		if err := proto.Unmarshal(raw, file); err != nil {
+			return err
+		}
+		if _, ok := rc.files[file.GetName()]; ok {
+			continue
+		}
+		rc.files[file.GetName()] = file
+		rc.order = append(rc.order, file.GetName())
+		missing = append(missing, file.GetDependency()...)
+	}
+	for _, dependency := range missing {
+		if _, ok := rc.files[dependency]; ok {
+			continue
+		}
+		if err := rc.fetch(&reflectionpb.ServerReflectionRequest{
+			MessageRequest: &reflectionpb.ServerReflectionRequest_FileByFilename{FileByFilename: dependency},
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
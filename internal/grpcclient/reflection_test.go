@@ -0,0 +1,56 @@
+package grpcclient
+
+import (
+	"testing"
+
+	"github.com/hc/hc/internal/models"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestReflect(t *testing.T) {
+	for _, version := range []string{"v1", "v1alpha"} {
+		t.Run(version, func(t *testing.T) {
+			target := startTestServer(t, version)
+			conn, err := Dial(&models.GRPCRequest{Target: target}, nil)
+			if err != nil {
+				t.Fatalf("Dial() error = %v", err)
+			}
+			defer conn.Close()
+
+			descriptors, err := Reflect(t.Context(), conn)
+			if err != nil {
+				t.Fatalf("Reflect() error = %v", err)
+			}
+			services := descriptors.Services()
+			if len(services) != 1 || services[0].Name != "test.v1.Greeter" {
+				t.Fatalf("Expected only test.v1.Greeter, got %+v", services)
+			}
+
+			resp, err := Invoke(t.Context(), conn, descriptors, &models.GRPCRequest{
+				Method:  "test.v1.Greeter/SayHello",
+				Message: `{"name": "reflection"}`,
+			})
+			if err != nil {
+				t.Fatalf("Invoke() error = %v", err)
+			}
+			if resp.Status != "OK" {
+				t.Errorf("Expected OK, got %s: %s", resp.Status, resp.Message)
+			}
+		})
+	}
+}
+
+func TestReflectUnsupported(t *testing.T) {
+	target := startTestServer(t, "")
+	conn, err := Dial(&models.GRPCRequest{Target: target}, nil)
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	defer conn.Close()
+
+	_, err = Reflect(t.Context(), conn)
+	if status.Code(err) != codes.Unimplemented {
+		t.Errorf("Expected Unimplemented, got %v", err)
+	}
+}
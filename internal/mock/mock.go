@@ -0,0 +1,359 @@
+package mock
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hc/hc/internal/logger"
+	"github.com/hc/hc/internal/models"
+	"github.com/hc/hc/internal/proxy"
+	"github.com/hc/hc/internal/storage"
+)
+
+const (
+	HeaderExample             = "X-Mock-Example"
+	HeaderStatus              = "X-Mock-Status"
+	maxLogEntries             = 500
+	statusClientClosedRequest = 499
+)
+
+var paramPattern = regexp.MustCompile(`^(:[A-Za-z0-9_]+|\{[^{}]+\})$`)
+
+type route struct {
+	request  models.Request
+	method   string
+	segments []string
+	headers  map[string]string
+	literals int
+}
+
+type Mock struct {
+	config models.MockConfig
+	routes []route
+	report func(models.MockLogEntry)
+	mu     sync.Mutex
+	log    []models.MockLogEntry
+}
+
+func ValidateCORSOrigins(origins []string) error {
+	for _, origin := range origins {
+		if origin == "*" {
+			continue
+		}
+		u, err := url.Parse(origin)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || (u.Path != "" && u.Path != "/") || u.RawQuery != "" || u.Fragment != "" {
+			return fmt.Errorf("invalid CORS origin %q, expected * or scheme://host[:port]", origin)
+		}
+	}
+	return nil
+}
+
+func Load(db *storage.DB, config models.MockConfig, report func(models.MockLogEntry)) (*Mock, error) {
+	if err := ValidateCORSOrigins(config.CORSOrigins); err != nil {
+		return nil, err
+	}
+	if config.FolderID != nil {
+		var folder models.Folder
+		if err := db.GetFolder(*config.FolderID, &folder); err != nil {
+			return nil, err
+		}
+	}
+	requests, err := db.FilterRequests(&models.RequestFilter{FolderID: config.FolderID})
+	if err != nil {
+		return nil, err
+	}
+	chains := make(map[int][]models.Folder)
+	resolved := make([]models.Request, 0, len(requests))
+	for _, request := range requests {
+		if request.Type == models.RequestTypeWebSocket || request.Type == models.RequestTypeGRPC {
+			continue
+		}
+		var folders []models.Folder
+		if request.FolderID != nil {
+			chain, ok := chains[*request.FolderID]
+			if !ok {
+				chain, err = db.GetFolderChain(*request.FolderID)
+				if err != nil {
+					return nil, err
+				}
+				chains[*request.FolderID] = chain
+			}
+			folders = chain
+		}
+		resolved = append(resolved, proxy.ResolveRequest(&request, folders).Request)
+	}
+	return New(resolved, config, report), nil
+}
+
+func New(requests []models.Request, config models.MockConfig, report func(models.MockLogEntry)) *Mock {
+	m := &Mock{config: config, report: report, log: []models.MockLogEntry{}}
+	for _, request := range requests {
+		r := route{
+			request:  request,
+			method:   strings.ToUpper(request.Method),
+			segments: splitPath(requestPath(request.URL)),
+			headers:  make(map[string]string),
+		}
+		if r.method == "" {
+			r.method = http.MethodGet
+		}
+		if request.Type == models.RequestTypeGraphQL && request.Method == "" {
+			r.method = http.MethodPost
+		}
+		for _, segment := range r.segments {
+			if !isParam(segment) {
+				r.literals++
+			}
+		}
+		for name, value := range request.Headers {
+			if !strings.Contains(value, "{{") {
+				r.headers[name] = value
+			}
+		}
+		m.routes = append(m.routes, r)
+	}
+	sort.SliceStable(m.routes, func(i, j int) bool {
+		return m.routes[i].literals > m.routes[j].literals
+	})
+	return m
+}
+
+func (m *Mock) Routes() int {
+	return len(m.routes)
+}
+
+func (m *Mock) Log() []models.MockLogEntry {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]models.MockLogEntry{}, m.log...)
+}
+
+func (m *Mock) allowCORS(w http.ResponseWriter, r *http.Request) bool {
+	if len(m.config.CORSOrigins) == 0 {
+		return false
+	}
+	if slices.Contains(m.config.CORSOrigins, "*") {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		return true
+	}
+	w.Header().Add("Vary", "Origin")
+	origin := r.Header.Get("Origin")
+	if origin == "" || !slices.ContainsFunc(m.config.CORSOrigins, func(allowed string) bool { return strings.EqualFold(strings.TrimSuffix(allowed, "/"), origin) }) {
+		return false
+	}
+	w.Header().Set("Access-Control-Allow-Origin", origin)
+	return true
+}
+
+func (m *Mock) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	entry := models.MockLogEntry{Time: start.UTC(), Method: r.Method, Path: r.URL.RequestURI()}
+	defer func() {
+		entry.Duration = time.Since(start).Milliseconds()
+		m.record(entry)
+	}()
+	allowed := m.allowCORS(w, r)
+	if allowed && r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+		w.Header().Set("Access-Control-Allow-Methods", r.Header.Get("Access-Control-Request-Method"))
+		if headers := r.Header.Get("Access-Control-Request-Headers"); headers != "" {
+			w.Header().Set("Access-Control-Allow-Headers", headers)
+		}
+		entry.StatusCode = http.StatusNoContent
+		w.WriteHeader(entry.StatusCode)
+		return
+	}
+	if m.config.Latency > 0 {
+		select {
+		case <-time.After(time.Duration(m.config.Latency) * time.Millisecond):
+		case <-r.Context().Done():
+			entry.StatusCode = statusClientClosedRequest
+			return
+		}
+	}
+	matched := m.match(r)
+	if matched == nil {
+		entry.StatusCode = http.StatusNotFound
+		writeError(w, entry.StatusCode, fmt.Sprintf("No saved request matches %s %s", r.Method, r.URL.Path))
+		return
+	}
+	requestID := matched.request.ID
+	entry.RequestID = &requestID
+	entry.RequestName = matched.request.Name
+	example, err := selectExample(matched.request.Examples, r.Header)
+	if err != nil {
+		entry.StatusCode = http.StatusNotFound
+		if len(matched.request.Examples) == 0 {
+			entry.StatusCode = http.StatusNotImplemented
+		}
+		writeError(w, entry.StatusCode, err.Error())
+		return
+	}
+	entry.Example = example.Name
+	entry.StatusCode = example.StatusCode
+	for name, value := range example.Headers {
+		w.Header().Set(name, value)
+	}
+	w.WriteHeader(example.StatusCode)
+	w.Write([]byte(example.Body))
+}
+
+func (m *Mock) match(r *http.Request) *route {
+	segments := splitPath(r.URL.Path)
+	for i := range m.routes {
+		candidate := &m.routes[i]
+		if candidate.method != r.Method && !(r.Method == http.MethodHead && candidate.method == http.MethodGet) {
+			continue
+		}
+		if !matchSegments(candidate.segments, segments) {
+			continue
+		}
+		if m.config.MatchHeaders && !matchHeaders(candidate.headers, r.Header) {
+			continue
+		}
+		return candidate
+	}
+	return nil
+}
+
+func (m *Mock) record(entry models.MockLogEntry) {
+	m.mu.Lock()
+	m.log = append(m.log, entry)
+	if len(m.log) > maxLogEntries {
+		m.log = m.log[len(m.log)-maxLogEntries:]
+	}
+	m.mu.Unlock()
+	if m.report != nil {
+		m.report(entry)
+	}
+}
+
+func selectExample(examples []models.ExampleResponse, header http.Header) (*models.ExampleResponse, error) {
+	if len(examples) == 0 {
+		return nil, errors.New("no example responses saved for this request")
+	}
+	if name := header.Get(HeaderExample); name != "" {
+		for i := range examples {
+			if strings.EqualFold(examples[i].Name, name) {
+				return &examples[i], nil
+			}
+		}
+		return nil, fmt.Errorf("example %q not found", name)
+	}
+	if value := header.Get(HeaderStatus); value != "" {
+		status, err := strconv.Atoi(value)
+		if err == nil {
+			for i := range examples {
+				if examples[i].StatusCode == status {
+					return &examples[i], nil
+				}
+			}
+		}
+		return nil, fmt.Errorf("no example with status %s", value)
+	}
+	for i := range examples {
+		if examples[i].StatusCode >= 200 && examples[i].StatusCode < 300 {
+			return &examples[i], nil
+		}
+	}
+	return &examples[0], nil
+}
+
+func requestPath(rawURL string) string {
+	path := rawURL
+	if i := strings.Index(path, "://"); i >= 0 {
+		path = path[i+3:]
+	}
+	if !strings.HasPrefix(path, "/") {
+		i := strings.Index(path, "/")
+		if i < 0 {
+			return "/"
+		}
+		path = path[i:]
+	}
+	if i := strings.IndexAny(path, "?#"); i >= 0 {
+		path = path[:i]
+	}
+	return path
+}
+
+func splitPath(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
+
+func matchSegments(pattern, segments []string) bool {
+	if len(pattern) != len(segments) {
+		return false
+	}
+	for i, segment := range pattern {
+		if segment != segments[i] && !isParam(segment) {
+			return false
+		}
+	}
+	return true
+}
+
+func isParam(segment string) bool {
+	return strings.Contains(segment, "{{") || paramPattern.MatchString(segment)
+}
+
+func matchHeaders(expected map[string]string, header http.Header) bool {
+	for name, value := range expected {
+		if header.Get(name) != value {
+			return false
+		}
+	}
+	return true
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(models.NewErrorResponse(message))
+}
+
+type Server struct {
+	*Mock
+	listener net.Listener
+	server   *http.Server
+}
+
+func Start(m *Mock, port int) (*Server, error) {
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{Mock: m, listener: listener, server: &http.Server{Handler: m}}
+	logger.Get().Info("Starting mock server", slog.String("address", s.Address()), slog.Int("routes", m.Routes()))
+	go func() {
+		if err := s.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Get().Error("Mock server failed", slog.String("error", err.Error()))
+		}
+	}()
+	return s, nil
+}
+
+func (s *Server) Address() string {
+	return "http://" + s.listener.Addr().String()
+}
+
+func (s *Server) Stop(ctx context.Context) error {
+	logger.Get().Info("Stopping mock server", slog.String("address", s.Address()))
+	return s.server.Shutdown(ctx)
+}
@@ -0,0 +1,246 @@
+package mock
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hc/hc/internal/models"
+	"github.com/hc/hc/internal/storage"
+)
+
+func testRequests() []models.Request {
+	return []models.Request{
+		{
+			ID:     1,
+			Name:   "Get user",
+			Method: "GET",
+			URL:    "https://{{host}}/users/{{id}}",
+			Examples: []models.ExampleResponse{
+				{Name: "Not found", StatusCode: 404, Body: `{"error": "missing"}`},
+				{Name: "Found", StatusCode: 200, Headers: map[string]string{"Content-Type": "application/json"}, Body: `{"id": 1}`},
+			},
+		},
+		{
+			ID:       2,
+			Name:     "Current user",
+			Method:   "GET",
+			URL:      "https://example.com/users/me",
+			Examples: []models.ExampleResponse{{Name: "Me", StatusCode: 200, Body: `{"id": "me"}`}},
+		},
+		{
+			ID:       3,
+			Name:     "Create user",
+			Method:   "POST",
+			URL:      "/users?notify=true",
+			Headers:  map[string]string{"X-Tenant": "acme", "Authorization": "Bearer {{token}}"},
+			Examples: []models.ExampleResponse{{Name: "Created", StatusCode: 201}},
+		},
+		{ID: 4, Name: "Delete user", Method: "DELETE", URL: "/users/:id"},
+	}
+}
+
+func TestServeHTTP(t *testing.T) {
+	tests := []struct {
+		name         string
+		matchHeaders bool
+		method       string
+		path         string
+		headers      map[string]string
+		wantStatus   int
+		wantBody     string
+		wantRequest  int
+	}{
+		{name: "Prefers 2xx example", method: "GET", path: "/users/42", wantStatus: 200, wantBody: `{"id": 1}`, wantRequest: 1},
+		{name: "Literal path wins over parameter", method: "GET", path: "/users/me", wantStatus: 200, wantBody: `{"id": "me"}`, wantRequest: 2},
+		{name: "Example selected by name", method: "GET", path: "/users/42", headers: map[string]string{HeaderExample: "not found"}, wantStatus: 404, wantBody: `{"error": "missing"}`, wantRequest: 1},
+		{name: "Example selected by status", method: "GET", path: "/users/42", headers: map[string]string{HeaderStatus: "404"}, wantStatus: 404, wantRequest: 1},
+		{name: "Unknown example", method: "GET", path: "/users/42", headers: map[string]string{HeaderExample: "teapot"}, wantStatus: 404, wantRequest: 1},
+		{name: "Ignores query string", method: "POST", path: "/users?notify=false", wantStatus: 201, wantRequest: 3},
+		{name: "Header matching rejects missing headers", matchHeaders: true, method: "POST", path: "/users", wantStatus: 404},
+		{name: "Header matching accepts literal headers", matchHeaders: true, method: "POST", path: "/users", headers: map[string]string{"X-Tenant": "acme"}, wantStatus: 201, wantRequest: 3},
+		{name: "No examples saved", method: "DELETE", path: "/users/42", wantStatus: 501, wantRequest: 4},
+		{name: "No route", method: "PUT", path: "/users/42", wantStatus: 404},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := New(testRequests(), models.MockConfig{MatchHeaders: tt.matchHeaders}, nil)
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+			rec := httptest.NewRecorder()
+			m.ServeHTTP(rec, req)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			if tt.wantBody != "" && rec.Body.String() != tt.wantBody {
+				t.Errorf("Expected body %q, got %q", tt.wantBody, rec.Body.String())
+			}
+			if origin := rec.Header().Get("Access-Control-Allow-Origin"); origin != "" {
+				t.Errorf("Expected no CORS header by default, got %q", origin)
+			}
+			log := m.Log()
+			if len(log) != 1 {
+				t.Fatalf("Expected 1 log entry, got %d", len(log))
+			}
+			if log[0].StatusCode != tt.wantStatus || log[0].Method != tt.method {
+				t.Errorf("Expected log entry for %s with status %d, got %+v", tt.method, tt.wantStatus, log[0])
+			}
+			gotRequest := 0
+			if log[0].RequestID != nil {
+				gotRequest = *log[0].RequestID
+			}
+			if gotRequest != tt.wantRequest {
+				t.Errorf("Expected request %d, got %d", tt.wantRequest, gotRequest)
+			}
+		})
+	}
+}
+
+func TestCORS(t *testing.T) {
+	tests := []struct {
+		name       string
+		origins    []string
+		method     string
+		headers    map[string]string
+		wantStatus int
+		wantOrigin string
+	}{
+		{name: "Disabled by default", method: "GET", headers: map[string]string{"Origin": "https://app.example.com"}, wantStatus: 200},
+		{name: "Preflight disabled by default", method: "OPTIONS", headers: map[string]string{"Origin": "https://app.example.com", "Access-Control-Request-Method": "POST"}, wantStatus: 404},
+		{name: "Any origin", origins: []string{"*"}, method: "GET", headers: map[string]string{"Origin": "https://app.example.com"}, wantStatus: 200, wantOrigin: "*"},
+		{name: "Configured origin is echoed", origins: []string{"https://app.example.com"}, method: "GET", headers: map[string]string{"Origin": "https://app.example.com"}, wantStatus: 200, wantOrigin: "https://app.example.com"},
+		{name: "Other origin is not echoed", origins: []string{"https://app.example.com"}, method: "GET", headers: map[string]string{"Origin": "https://evil.example.com"}, wantStatus: 200},
+		{name: "Configured origin preflight", origins: []string{"https://app.example.com"}, method: "OPTIONS", headers: map[string]string{"Origin": "https://app.example.com", "Access-Control-Request-Method": "POST"}, wantStatus: 204, wantOrigin: "https://app.example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := New(testRequests(), models.MockConfig{CORSOrigins: tt.origins}, nil)
+			req := httptest.NewRequest(tt.method, "/users/42", nil)
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+			rec := httptest.NewRecorder()
+			m.ServeHTTP(rec, req)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+			if origin := rec.Header().Get("Access-Control-Allow-Origin"); origin != tt.wantOrigin {
+				t.Errorf("Expected allowed origin %q, got %q", tt.wantOrigin, origin)
+			}
+		})
+	}
+}
+
+func TestValidateCORSOrigins(t *testing.T) {
+	tests := []struct {
+		name    string
+		origins []string
+		wantErr bool
+	}{
+		{name: "None", origins: nil},
+		{name: "Wildcard and origins", origins: []string{"*", "http://localhost:3000", "https://app.example.com/"}},
+		{name: "Missing scheme", origins: []string{"app.example.com"}, wantErr: true},
+		{name: "Path", origins: []string{"https://app.example.com/path"}, wantErr: true},
+		{name: "Unsupported scheme", origins: []string{"ftp://app.example.com"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateCORSOrigins(tt.origins); (err != nil) != tt.wantErr {
+				t.Errorf("Expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestLatency(t *testing.T) {
+	var reported []models.MockLogEntry
+	m := New(testRequests(), models.MockConfig{Latency: 50}, func(entry models.MockLogEntry) {
+		reported = append(reported, entry)
+	})
+	start := time.Now()
+	rec := httptest.NewRecorder()
+	m.ServeHTTP(rec, httptest.NewRequest("GET", "/users/me", nil))
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("Expected response to be delayed by at least 50ms, got %v", elapsed)
+	}
+	if len(reported) != 1 || reported[0].Duration < 50 {
+		t.Errorf("Expected reported entry with duration, got %+v", reported)
+	}
+}
+
+func TestRequestPath(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{url: "https://example.com/v1/users?page=2", want: "/v1/users"},
+		{url: "{{baseUrl}}/users/{{id}}", want: "/users/{{id}}"},
+		{url: "example.com", want: "/"},
+		{url: "/health#top", want: "/health"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			if got := requestPath(tt.url); got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestLoadAndStart(t *testing.T) {
+	t.Setenv("HC_TEST_DB_PATH", filepath.Join(t.TempDir(), "test.db"))
+	db, err := storage.InitDB()
+	if err != nil {
+		t.Fatalf("InitDB() error = %v", err)
+	}
+	defer db.Close()
+	api := &models.Folder{Name: "API", BaseURL: "https://example.com/{{version}}", Variables: map[string]string{"version": "v2"}}
+	if err := db.CreateFolder(api); err != nil {
+		t.Fatalf("CreateFolder() error = %v", err)
+	}
+	for _, request := range []*models.Request{
+		{Name: "Health", FolderID: &api.ID, Method: "GET", URL: "/health", Examples: []models.ExampleResponse{{Name: "OK", StatusCode: 200, Body: "ok"}}},
+		{Name: "Outside", Method: "GET", URL: "https://example.com/outside", Examples: []models.ExampleResponse{{Name: "OK", StatusCode: 200}}},
+		{Name: "Socket", FolderID: &api.ID, Type: models.RequestTypeWebSocket, URL: "wss://example.com/ws"},
+	} {
+		if err := db.CreateRequest(request); err != nil {
+			t.Fatalf("CreateRequest() error = %v", err)
+		}
+	}
+
+	m, err := Load(db, models.MockConfig{FolderID: &api.ID}, nil)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if m.Routes() != 1 {
+		t.Errorf("Expected 1 route, got %d", m.Routes())
+	}
+	missing := 999
+	if _, err := Load(db, models.MockConfig{FolderID: &missing}, nil); err == nil {
+		t.Error("Expected error for missing folder")
+	}
+
+	server, err := Start(m, 0)
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer server.Stop(context.Background())
+	resp, err := http.Get(server.Address() + "/v2/health")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || strings.TrimSpace(string(body)) != "ok" {
+		t.Errorf("Expected 200 ok, got %d %q", resp.StatusCode, body)
+	}
+}
@@ -0,0 +1,23 @@
+package models
+
+const (
+	AuthTypeNone   = "none"
+	AuthTypeBasic  = "basic"
+	AuthTypeBearer = "bearer"
+	AuthTypeAPIKey = "api_key"
+)
+
+const (
+	AuthInHeader = "header"
+	AuthInQuery  = "query"
+)
+
+type Auth struct {
+	Type     string `json:"type"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Token    string `json:"token,omitempty"`
+	Key      string `json:"key,omitempty"`
+	Value    string `json:"value,omitempty"`
+	In       string `json:"in,omitempty"`
+}
@@ -2,6 +2,9 @@ package models
 
 type ErrorResponse struct {
 	Messages []string `json:"messages"`
+	Code     string   `json:"code,omitempty"`
+	Detail   string   `json:"detail,omitempty"`
+	Timing   *Timing  `json:"timing,omitempty"`
 }
 
 func NewErrorResponse(message string) *ErrorResponse {
@@ -14,3 +17,9 @@ func NewErrorResponseWithMessages(messages []string) *ErrorResponse {
 		Messages: messages,
 	}
 }
+func NewErrorResponseWithCode(code, message string) *ErrorResponse {
+	return &ErrorResponse{
+		Messages: []string{message},
+		Code:     code,
+	}
+}
@@ -115,3 +115,14 @@ func TestErrorResponseFields(t *testing.T) {
 		t.Errorf("Messages field should have json tag 'messages', got '%s'", jsonTag)
 	}
 }
+
+func TestNewErrorResponseWithCode(t *testing.T) {
+	got := NewErrorResponseWithCode("connection_refused", "The connection was refused")
+	want := &ErrorResponse{
+		Messages: []string{"The connection was refused"},
+		Code:     "connection_refused",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NewErrorResponseWithCode() = %v, want %v", got, want)
+	}
+}
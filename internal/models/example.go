@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+type ExampleResponse struct {
+	ID         int               `json:"id"`
+	RequestID  int               `json:"request_id"`
+	Name       string            `json:"name"`
+	StatusCode int               `json:"status_code"`
+	Headers    map[string]string `json:"headers"`
+	Body       string            `json:"body"`
+	HistoryID  *int              `json:"history_id,omitempty"`
+	CreatedAt  time.Time         `json:"created_at"`
+	UpdatedAt  time.Time         `json:"updated_at"`
+}
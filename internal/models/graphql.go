@@ -0,0 +1,18 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type GraphQLBody struct {
+	Query         string `json:"query"`
+	Variables     string `json:"variables"`
+	OperationName string `json:"operation_name"`
+}
+
+type GraphQLSchema struct {
+	URL       string          `json:"url"`
+	Schema    json.RawMessage `json:"schema"`
+	FetchedAt time.Time       `json:"fetched_at"`
+}
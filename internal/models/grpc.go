@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+type GRPCRequest struct {
+	Target     string            `json:"target"`
+	Method     string            `json:"method"`
+	Message    string            `json:"message"`
+	Metadata   map[string]string `json:"metadata"`
+	TLS        bool              `json:"tls"`
+	Insecure   bool              `json:"insecure"`
+	ProtoSetID *int              `json:"proto_set_id,omitempty"`
+}
+
+type GRPCResponse struct {
+	StatusCode   int               `json:"status_code"`
+	Status       string            `json:"status"`
+	Message      string            `json:"message,omitempty"`
+	Headers      map[string]string `json:"headers"`
+	Trailers     map[string]string `json:"trailers"`
+	Body         string            `json:"body"`
+	MessageCount int               `json:"message_count"`
+	Duration     int64             `json:"duration"`
+}
+
+type GRPCService struct {
+	Name    string       `json:"name"`
+	Methods []GRPCMethod `json:"methods"`
+}
+
+type GRPCMethod struct {
+	Name            string `json:"name"`
+	FullName        string `json:"full_name"`
+	InputType       string `json:"input_type"`
+	OutputType      string `json:"output_type"`
+	ClientStreaming bool   `json:"client_streaming"`
+	ServerStreaming bool   `json:"server_streaming"`
+}
+
+type GRPCProtoSet struct {
+	ID        int               `json:"id"`
+	Name      string            `json:"name"`
+	Files     map[string]string `json:"files"`
+	CreatedAt time.Time         `json:"created_at"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+type HistoryEntry struct {
+	ID              int               `json:"id"`
+	RequestID       *int              `json:"request_id"`
+	Method          string            `json:"method"`
+	URL             string            `json:"url"`
+	RequestHeaders  map[string]string `json:"request_headers"`
+	RequestBody     string            `json:"request_body"`
+	StatusCode      int               `json:"status_code"`
+	ResponseHeaders map[string]string `json:"response_headers"`
+	ResponseBody    string            `json:"response_body"`
+	Duration        int64             `json:"duration"`
+	Metrics         *ResponseMetrics  `json:"metrics,omitempty"`
+	ErrorCode       string            `json:"error_code,omitempty"`
+	Streamed        bool              `json:"streamed"`
+	CreatedAt       time.Time         `json:"created_at"`
+}
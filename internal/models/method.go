@@ -0,0 +1,9 @@
+package models
+
+type HTTPMethod struct {
+	Name        string `json:"name"`
+	Safe        bool   `json:"safe"`
+	Idempotent  bool   `json:"idempotent"`
+	BodyAllowed bool   `json:"body_allowed"`
+	Reference   string `json:"reference"`
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+const DefaultMockPort = 8090
+
+type MockConfig struct {
+	FolderID     *int     `json:"folder_id"`
+	Port         int      `json:"port"`
+	Latency      int64    `json:"latency"`
+	MatchHeaders bool     `json:"match_headers"`
+	CORSOrigins  []string `json:"cors_origins,omitempty"`
+}
+
+type MockStatus struct {
+	Running bool        `json:"running"`
+	Address string      `json:"address,omitempty"`
+	Routes  int         `json:"routes"`
+	Config  *MockConfig `json:"config,omitempty"`
+}
+
+type MockLogEntry struct {
+	Time        time.Time `json:"time"`
+	Method      string    `json:"method"`
+	Path        string    `json:"path"`
+	StatusCode  int       `json:"status_code"`
+	RequestID   *int      `json:"request_id"`
+	RequestName string    `json:"request_name,omitempty"`
+	Example     string    `json:"example,omitempty"`
+	Duration    int64     `json:"duration"`
+}
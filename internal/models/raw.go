@@ -0,0 +1,18 @@
+package models
+
+const (
+	RawEncodingText   = "text"
+	RawEncodingBase64 = "base64"
+)
+
+type RawResponse struct {
+	Raw           string    `json:"raw"`
+	Encoding      string    `json:"encoding"`
+	Truncated     bool      `json:"truncated"`
+	BytesSent     int       `json:"bytes_sent"`
+	BytesReceived int       `json:"bytes_received"`
+	Duration      int64     `json:"duration"`
+	RemoteAddr    string    `json:"remote_addr"`
+	Response      *Response `json:"response,omitempty"`
+	ParseError    string    `json:"parse_error,omitempty"`
+}
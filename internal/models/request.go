@@ -5,26 +5,101 @@ import (
 )
 
 type Folder struct {
-	ID        int       `json:"id"`
-	Name      string    `json:"name"`
-	ParentID  *int      `json:"parent_id"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID            int               `json:"id"`
+	Name          string            `json:"name"`
+	Description   string            `json:"description"`
+	ParentID      *int              `json:"parent_id"`
+	Headers       map[string]string `json:"headers"`
+	Auth          *Auth             `json:"auth,omitempty"`
+	BaseURL       string            `json:"base_url"`
+	Variables     map[string]string `json:"variables"`
+	HostOverrides map[string]string `json:"host_overrides"`
+	Position      int               `json:"position"`
+	DeletedAt     *time.Time        `json:"deleted_at,omitempty"`
+	CreatedAt     time.Time         `json:"created_at"`
+	UpdatedAt     time.Time         `json:"updated_at"`
 }
+
+const (
+	RequestTypeHTTP      = "http"
+	RequestTypeWebSocket = "websocket"
+	RequestTypeGraphQL   = "graphql"
+	RequestTypeGRPC      = "grpc"
+)
+
+const (
+	CompressionGzip    = "gzip"
+	CompressionDeflate = "deflate"
+	CompressionZstd    = "zstd"
+)
+
+const (
+	ProtocolHTTP1 = "http1"
+	ProtocolHTTP2 = "h2"
+	ProtocolH2C   = "h2c"
+)
+
 type Request struct {
-	ID        int               `json:"id"`
-	Name      string            `json:"name"`
-	FolderID  *int              `json:"folder_id"`
-	Method    string            `json:"method"`
-	URL       string            `json:"url"`
-	Headers   map[string]string `json:"headers"`
-	Body      string            `json:"body"`
-	CreatedAt time.Time         `json:"created_at"`
-	UpdatedAt time.Time         `json:"updated_at"`
+	ID          int               `json:"id"`
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	FolderID    *int              `json:"folder_id"`
+	Type        string            `json:"type"`
+	Method      string            `json:"method"`
+	URL         string            `json:"url"`
+	Headers     map[string]string `json:"headers"`
+	Body        string            `json:"body"`
+	Compression string            `json:"compression,omitempty"`
+	Protocol    string            `json:"protocol,omitempty"`
+	Auth        *Auth             `json:"auth,omitempty"`
+	GraphQL     *GraphQLBody      `json:"graphql,omitempty"`
+	GRPC        *GRPCRequest      `json:"grpc,omitempty"`
+	Tags        []string          `json:"tags"`
+	Favorite    bool              `json:"favorite"`
+	Examples    []ExampleResponse `json:"examples"`
+	Position    int               `json:"position"`
+	DeletedAt   *time.Time        `json:"deleted_at,omitempty"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
+type EffectiveRequest struct {
+	Request
+	Variables     map[string]string `json:"variables"`
+	HostOverrides map[string]string `json:"host_overrides"`
 }
+
 type Response struct {
-	StatusCode int               `json:"status_code"`
-	Headers    map[string]string `json:"headers"`
-	Body       string            `json:"body"`
-	Duration   int64             `json:"duration"`
+	StatusCode      int               `json:"status_code"`
+	Headers         map[string]string `json:"headers"`
+	Body            string            `json:"body"`
+	Duration        int64             `json:"duration"`
+	RemoteAddr      string            `json:"remote_addr"`
+	Timing          *Timing           `json:"timing,omitempty"`
+	ContentEncoding string            `json:"content_encoding,omitempty"`
+	Charset         string            `json:"charset,omitempty"`
+	Warnings        []string          `json:"warnings,omitempty"`
+	ResponseMetrics
+}
+
+type ResponseMetrics struct {
+	Protocol              string `json:"protocol,omitempty"`
+	TLSVersion            string `json:"tls_version,omitempty"`
+	CipherSuite           string `json:"cipher_suite,omitempty"`
+	RequestHeadersSize    int64  `json:"request_headers_size"`
+	RequestBodySize       int64  `json:"request_body_size"`
+	RequestCompressedSize int64  `json:"request_compressed_size,omitempty"`
+	HeadersSize           int64  `json:"headers_size"`
+	EncodedSize           int64  `json:"encoded_size"`
+	BodySize              int64  `json:"body_size"`
+}
+
+type RequestFilter struct {
+	Tags     []string
+	Favorite bool
+	FolderID *int
+}
+
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
 }
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+type RequestRevision struct {
+	ID        int       `json:"id"`
+	RequestID int       `json:"request_id"`
+	Revision  int       `json:"revision"`
+	Request   Request   `json:"request"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type FieldChange struct {
+	Field string `json:"field"`
+	From  any    `json:"from"`
+	To    any    `json:"to"`
+}
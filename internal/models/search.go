@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+const (
+	SearchTypeRequest = "request"
+	SearchTypeFolder  = "folder"
+	SearchTypeHistory = "history"
+)
+
+type SearchQuery struct {
+	Query      string
+	Method     string
+	FolderID   *int
+	StatusCode int
+	From       *time.Time
+	To         *time.Time
+	Limit      int
+}
+
+type SearchResult struct {
+	Type       string    `json:"type"`
+	ID         int       `json:"id"`
+	Name       string    `json:"name,omitempty"`
+	Method     string    `json:"method,omitempty"`
+	URL        string    `json:"url,omitempty"`
+	FolderID   *int      `json:"folder_id,omitempty"`
+	StatusCode int       `json:"status_code,omitempty"`
+	Snippet    string    `json:"snippet"`
+	Date       time.Time `json:"date"`
+}
@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+const (
+	DefaultTrashRetentionDays   = 30
+	DefaultHistoryRetentionDays = 30
+)
+
+const (
+	ProxyModeSystem = "system"
+	ProxyModeManual = "manual"
+	ProxyModeNone   = "none"
+)
+
+type ProxySettings struct {
+	Mode        string   `json:"mode"`
+	URL         string   `json:"url"`
+	Username    string   `json:"username"`
+	Password    string   `json:"password"`
+	PasswordSet *bool    `json:"password_set,omitempty"`
+	NoProxy     []string `json:"no_proxy"`
+}
+
+type Settings struct {
+	Proxy                ProxySettings     `json:"proxy"`
+	HostOverrides        map[string]string `json:"host_overrides"`
+	TrashRetentionDays   int               `json:"trash_retention_days"`
+	HistoryRetentionDays int               `json:"history_retention_days"`
+}
+
+func (s *Settings) TrashRetention() time.Duration {
+	days := s.TrashRetentionDays
+	if days <= 0 {
+		days = DefaultTrashRetentionDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+func (s *Settings) HistoryRetention() time.Duration {
+	days := s.HistoryRetentionDays
+	if days <= 0 {
+		days = DefaultHistoryRetentionDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
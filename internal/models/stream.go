@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+)
+
+const (
+	StreamEventHeaders   = "headers"
+	StreamEventChunk     = "chunk"
+	StreamEventSSE       = "event"
+	StreamEventDone      = "done"
+	StreamEventError     = "error"
+	StreamEventCancelled = "cancelled"
+)
+
+type StreamEvent struct {
+	Type       string            `json:"type"`
+	StatusCode int               `json:"status_code,omitempty"`
+	Headers    map[string]string `json:"headers,omitempty"`
+	RemoteAddr string            `json:"remote_addr,omitempty"`
+	Event      string            `json:"event,omitempty"`
+	ID         string            `json:"id,omitempty"`
+	Retry      int               `json:"retry,omitempty"`
+	Data       string            `json:"data,omitempty"`
+	Code       string            `json:"code,omitempty"`
+	Bytes      int64             `json:"bytes,omitempty"`
+	Timestamp  time.Time         `json:"timestamp"`
+	Elapsed    int64             `json:"elapsed"`
+}
@@ -0,0 +1,9 @@
+package models
+
+type Timing struct {
+	DNS       int64 `json:"dns"`
+	Connect   int64 `json:"connect"`
+	TLS       int64 `json:"tls"`
+	FirstByte int64 `json:"first_byte"`
+	Total     int64 `json:"total"`
+}
@@ -0,0 +1,6 @@
+package models
+
+type Trash struct {
+	Folders  []Folder  `json:"folders"`
+	Requests []Request `json:"requests"`
+}
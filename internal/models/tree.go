@@ -0,0 +1,17 @@
+package models
+
+type TreeFolder struct {
+	Folder
+	Folders  []TreeFolder `json:"folders"`
+	Requests []Request    `json:"requests"`
+}
+
+type Tree struct {
+	Folders  []TreeFolder `json:"folders"`
+	Requests []Request    `json:"requests"`
+}
+
+type MoveTarget struct {
+	ParentID *int `json:"parent_id"`
+	Position int  `json:"position"`
+}
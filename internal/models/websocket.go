@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+)
+
+const (
+	WebSocketStatusOpen   = "open"
+	WebSocketStatusClosed = "closed"
+	WebSocketStatusFailed = "failed"
+
+	WebSocketDirectionSent     = "sent"
+	WebSocketDirectionReceived = "received"
+
+	WebSocketMessageText   = "text"
+	WebSocketMessageBinary = "binary"
+	WebSocketMessageClose  = "close"
+)
+
+type WebSocketSession struct {
+	ID          int               `json:"id"`
+	RequestID   *int              `json:"request_id"`
+	URL         string            `json:"url"`
+	Headers     map[string]string `json:"headers"`
+	Status      string            `json:"status"`
+	CloseCode   int               `json:"close_code"`
+	CloseReason string            `json:"close_reason"`
+	CreatedAt   time.Time         `json:"created_at"`
+	ClosedAt    *time.Time        `json:"closed_at"`
+}
+
+type WebSocketMessage struct {
+	ID        int       `json:"id"`
+	SessionID int       `json:"session_id"`
+	Direction string    `json:"direction"`
+	Type      string    `json:"type"`
+	Data      string    `json:"data"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,46 @@
+package proxy
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"io"
+
+	"github.com/hc/hc/internal/models"
+	"github.com/klauspost/compress/zstd"
+)
+
+func ValidateCompression(compression string) error {
+	switch compression {
+	case "", models.CompressionGzip, models.CompressionDeflate, models.CompressionZstd:
+		return nil
+	}
+	return errors.New("unsupported compression: " + compression)
+}
+
+func compressBody(body []byte, compression string) ([]byte, error) {
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	switch compression {
+	case models.CompressionGzip:
+		w = gzip.NewWriter(&buf)
+	case models.CompressionDeflate:
+		w = zlib.NewWriter(&buf)
+	case models.CompressionZstd:
+		encoder, err := zstd.NewWriter(&buf)
+		if err != nil {
+			return nil, err
+		}
+		w = encoder
+	default:
+		return nil, errors.New("unsupported compression: " + compression)
+	}
+	if _, err := w.Write(body); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,109 @@
+package proxy
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hc/hc/internal/models"
+)
+
+func TestValidateCompression(t *testing.T) {
+	tests := []struct {
+		compression string
+		wantErr     bool
+	}{
+		{compression: ""},
+		{compression: models.CompressionGzip},
+		{compression: models.CompressionDeflate},
+		{compression: models.CompressionZstd},
+		{compression: "br", wantErr: true},
+		{compression: "lzma", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.compression, func(t *testing.T) {
+			err := ValidateCompression(tt.compression)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCompression(%q) error = %v, wantErr %v", tt.compression, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExecuteRequestCompressesBody(t *testing.T) {
+	payload := strings.Repeat(`{"event": "click"}`, 50)
+
+	for _, compression := range []string{models.CompressionGzip, models.CompressionDeflate, models.CompressionZstd} {
+		t.Run(compression, func(t *testing.T) {
+			var (
+				received        []byte
+				contentEncoding string
+				contentLength   int64
+			)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				contentEncoding = r.Header.Get("Content-Encoding")
+				contentLength = r.ContentLength
+				received, _ = io.ReadAll(r.Body)
+			}))
+			defer server.Close()
+
+			resp, err := NewClient().ExecuteRequest(&models.Request{
+				Method:      "POST",
+				URL:         server.URL,
+				Body:        payload,
+				Compression: compression,
+			})
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if contentEncoding != compression {
+				t.Errorf("Expected Content-Encoding %s, got %q", compression, contentEncoding)
+			}
+			if contentLength != int64(len(received)) {
+				t.Errorf("Expected Content-Length %d, got %d", len(received), contentLength)
+			}
+			decompressed, err := decompress(received, []string{compression})
+			if err != nil {
+				t.Fatalf("Failed to decompress received body: %v", err)
+			}
+			if string(decompressed) != payload {
+				t.Errorf("Expected decompressed body to match payload, got %q", decompressed)
+			}
+			if resp.RequestBodySize != int64(len(payload)) {
+				t.Errorf("Expected request body size %d, got %d", len(payload), resp.RequestBodySize)
+			}
+			if resp.RequestCompressedSize != int64(len(received)) {
+				t.Errorf("Expected request compressed size %d, got %d", len(received), resp.RequestCompressedSize)
+			}
+			if resp.RequestCompressedSize >= resp.RequestBodySize {
+				t.Errorf("Expected compressed size %d to be smaller than %d", resp.RequestCompressedSize, resp.RequestBodySize)
+			}
+		})
+	}
+}
+
+func TestExecuteRequestSkipsCompressionForEmptyBody(t *testing.T) {
+	var contentEncoding string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentEncoding = r.Header.Get("Content-Encoding")
+	}))
+	defer server.Close()
+
+	resp, err := NewClient().ExecuteRequest(&models.Request{
+		Method:      "GET",
+		URL:         server.URL,
+		Compression: models.CompressionGzip,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if contentEncoding != "" {
+		t.Errorf("Expected no Content-Encoding, got %q", contentEncoding)
+	}
+	if resp.RequestBodySize != 0 || resp.RequestCompressedSize != 0 {
+		t.Errorf("Expected zero sizes, got %d and %d", resp.RequestBodySize, resp.RequestCompressedSize)
+	}
+}
@@ -0,0 +1,131 @@
+package proxy
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+	"golang.org/x/net/html/charset"
+)
+
+const (
+	acceptEncoding = "gzip, deflate, br, zstd"
+	maxDecodedSize = 64 << 20
+)
+
+type decodedBody struct {
+	body     []byte
+	encoding string
+	charset  string
+	warnings []string
+}
+
+func decodeResponseBody(raw []byte, header http.Header) decodedBody {
+	result := decodedBody{body: raw}
+	if len(raw) == 0 {
+		return result
+	}
+	if encodings := contentEncodings(header); len(encodings) > 0 {
+		result.encoding = strings.Join(encodings, ", ")
+		decompressed, err := decompress(raw, encodings)
+		if err != nil {
+			result.warnings = append(result.warnings, "failed to decode "+result.encoding+" body: "+err.Error())
+			return result
+		}
+		result.body = decompressed
+	}
+	decoded, name, err := decodeCharset(result.body, header.Get("Content-Type"))
+	if err != nil {
+		result.warnings = append(result.warnings, err.Error())
+		return result
+	}
+	result.body = decoded
+	result.charset = name
+	return result
+}
+
+func contentEncodings(header http.Header) []string {
+	var encodings []string
+	for _, value := range header.Values("Content-Encoding") {
+		for _, encoding := range strings.Split(value, ",") {
+			encoding = strings.ToLower(strings.TrimSpace(encoding))
+			if encoding != "" && encoding != "identity" {
+				encodings = append(encodings, encoding)
+			}
+		}
+	}
+	return encodings
+}
+
+func decompress(body []byte, encodings []string) ([]byte, error) {
+	for i := len(encodings) - 1; i >= 0; i-- {
+		reader, err := decompressReader(bytes.NewReader(body), encodings[i])
+		if err != nil {
+			return nil, err
+		}
+		decoded, err := io.ReadAll(io.LimitReader(reader, maxDecodedSize+1))
+		reader.Close()
+		if err != nil {
+			return nil, err
+		}
+		if len(decoded) > maxDecodedSize {
+			return nil, errors.New("decoded body exceeds size limit")
+		}
+		body = decoded
+	}
+	return body, nil
+}
+
+func decompressReader(r *bytes.Reader, encoding string) (io.ReadCloser, error) {
+	switch encoding {
+	case "gzip", "x-gzip":
+		return gzip.NewReader(r)
+	case "deflate":
+		if reader, err := zlib.NewReader(r); err == nil {
+			return reader, nil
+		}
+		r.Seek(0, io.SeekStart)
+		return flate.NewReader(r), nil
+	case "br":
+		return io.NopCloser(brotli.NewReader(r)), nil
+	case "zstd":
+		decoder, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return decoder.IOReadCloser(), nil
+	default:
+		return nil, errors.New("unsupported content encoding: " + encoding)
+	}
+}
+
+func decodeCharset(body []byte, contentType string) ([]byte, string, error) {
+	mediaType, params, _ := mime.ParseMediaType(contentType)
+	label := params["charset"]
+	if label == "" {
+		if mediaType != "text/html" {
+			return body, "", nil
+		}
+		_, label, _ = charset.DetermineEncoding(body, contentType)
+	}
+	enc, name := charset.Lookup(label)
+	if enc == nil {
+		return body, "", errors.New("unsupported charset: " + label)
+	}
+	if name == "utf-8" {
+		return body, name, nil
+	}
+	decoded, err := enc.NewDecoder().Bytes(body)
+	if err != nil {
+		return body, "", errors.New("failed to decode " + name + " body: " + err.Error())
+	}
+	return decoded, name, nil
+}
@@ -0,0 +1,182 @@
+package proxy
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/hc/hc/internal/models"
+	"github.com/klauspost/compress/zstd"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+)
+
+func compress(t *testing.T, encoding string, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	switch encoding {
+	case "gzip":
+		w = gzip.NewWriter(&buf)
+	case "deflate":
+		w = zlib.NewWriter(&buf)
+	case "raw-deflate":
+		w, _ = flate.NewWriter(&buf, flate.DefaultCompression)
+	case "br":
+		w = brotli.NewWriter(&buf)
+	case "zstd":
+		w, _ = zstd.NewWriter(&buf)
+	default:
+		t.Fatalf("unknown encoding %s", encoding)
+	}
+	w.Write(data)
+	w.Close()
+	return buf.Bytes()
+}
+
+func TestDecodeResponseBodyEncodings(t *testing.T) {
+	payload := []byte(strings.Repeat(`{"message": "hello"}`, 10))
+
+	tests := []struct {
+		name          string
+		body          []byte
+		encoding      string
+		wantEncoding  string
+		wantWarning   bool
+		wantUnchanged bool
+	}{
+		{name: "gzip", body: compress(t, "gzip", payload), encoding: "gzip", wantEncoding: "gzip"},
+		{name: "deflate", body: compress(t, "deflate", payload), encoding: "deflate", wantEncoding: "deflate"},
+		{name: "raw deflate", body: compress(t, "raw-deflate", payload), encoding: "deflate", wantEncoding: "deflate"},
+		{name: "br", body: compress(t, "br", payload), encoding: "br", wantEncoding: "br"},
+		{name: "zstd", body: compress(t, "zstd", payload), encoding: "zstd", wantEncoding: "zstd"},
+		{name: "stacked", body: compress(t, "br", compress(t, "gzip", payload)), encoding: "gzip, br", wantEncoding: "gzip, br"},
+		{name: "identity", body: payload, encoding: "identity"},
+		{name: "unsupported", body: payload, encoding: "compress", wantEncoding: "compress", wantWarning: true, wantUnchanged: true},
+		{name: "corrupt", body: []byte("not gzip"), encoding: "gzip", wantEncoding: "gzip", wantWarning: true, wantUnchanged: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			header.Set("Content-Encoding", tt.encoding)
+			header.Set("Content-Type", "application/json")
+			decoded := decodeResponseBody(tt.body, header)
+			if decoded.encoding != tt.wantEncoding {
+				t.Errorf("Expected encoding %q, got %q", tt.wantEncoding, decoded.encoding)
+			}
+			if (len(decoded.warnings) > 0) != tt.wantWarning {
+				t.Errorf("Unexpected warnings %v", decoded.warnings)
+			}
+			want := payload
+			if tt.wantUnchanged {
+				want = tt.body
+			}
+			if !bytes.Equal(decoded.body, want) {
+				t.Errorf("Expected body %q, got %q", want, decoded.body)
+			}
+		})
+	}
+}
+
+func TestDecodeResponseBodyCharset(t *testing.T) {
+	shiftJIS, _ := japanese.ShiftJIS.NewEncoder().Bytes([]byte("こんにちは"))
+	latin1, _ := charmap.ISO8859_1.NewEncoder().Bytes([]byte("café"))
+	html, _ := charmap.Windows1252.NewEncoder().Bytes([]byte(`<html><head><meta charset="windows-1252"></head><body>naïve</body></html>`))
+
+	tests := []struct {
+		name        string
+		body        []byte
+		contentType string
+		wantBody    string
+		wantCharset string
+		wantWarning bool
+	}{
+		{name: "shift_jis", body: shiftJIS, contentType: "text/plain; charset=Shift_JIS", wantBody: "こんにちは", wantCharset: "shift_jis"},
+		{name: "iso-8859-1", body: latin1, contentType: "text/plain; charset=ISO-8859-1", wantBody: "café", wantCharset: "windows-1252"},
+		{name: "html meta", body: html, contentType: "text/html", wantBody: `<html><head><meta charset="windows-1252"></head><body>naïve</body></html>`, wantCharset: "windows-1252"},
+		{name: "utf-8", body: []byte("café"), contentType: "text/plain; charset=utf-8", wantBody: "café", wantCharset: "utf-8"},
+		{name: "json without charset", body: []byte(`{"a":"é"}`), contentType: "application/json", wantBody: `{"a":"é"}`},
+		{name: "unknown charset", body: []byte("hello"), contentType: "text/plain; charset=x-unknown", wantBody: "hello", wantWarning: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			header.Set("Content-Type", tt.contentType)
+			decoded := decodeResponseBody(tt.body, header)
+			if string(decoded.body) != tt.wantBody {
+				t.Errorf("Expected body %q, got %q", tt.wantBody, decoded.body)
+			}
+			if decoded.charset != tt.wantCharset {
+				t.Errorf("Expected charset %q, got %q", tt.wantCharset, decoded.charset)
+			}
+			if (len(decoded.warnings) > 0) != tt.wantWarning {
+				t.Errorf("Unexpected warnings %v", decoded.warnings)
+			}
+		})
+	}
+}
+
+func TestExecuteRequestDecodesResponse(t *testing.T) {
+	latin1, _ := charmap.ISO8859_1.NewEncoder().Bytes([]byte("café"))
+	compressed := compress(t, "gzip", latin1)
+	var acceptEncoding string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		acceptEncoding = r.Header.Get("Accept-Encoding")
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Header().Set("Content-Type", "text/plain; charset=iso-8859-1")
+		w.Write(compressed)
+	}))
+	defer server.Close()
+
+	resp, err := NewClient().ExecuteRequest(&models.Request{Method: "GET", URL: server.URL})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if acceptEncoding != "gzip, deflate, br, zstd" {
+		t.Errorf("Expected default Accept-Encoding, got %q", acceptEncoding)
+	}
+	if resp.Body != "café" {
+		t.Errorf("Expected body café, got %q", resp.Body)
+	}
+	if resp.ContentEncoding != "gzip" {
+		t.Errorf("Expected content encoding gzip, got %q", resp.ContentEncoding)
+	}
+	if resp.EncodedSize != int64(len(compressed)) {
+		t.Errorf("Expected encoded size %d, got %d", len(compressed), resp.EncodedSize)
+	}
+	if resp.Charset != "windows-1252" {
+		t.Errorf("Expected charset windows-1252, got %q", resp.Charset)
+	}
+	if resp.Headers["Content-Encoding"] != "gzip" {
+		t.Errorf("Expected Content-Encoding header to be kept, got %q", resp.Headers["Content-Encoding"])
+	}
+}
+
+func TestExecuteRequestKeepsAcceptEncoding(t *testing.T) {
+	var acceptEncoding string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		acceptEncoding = r.Header.Get("Accept-Encoding")
+	}))
+	defer server.Close()
+
+	_, err := NewClient().ExecuteRequest(&models.Request{
+		Method:  "GET",
+		URL:     server.URL,
+		Headers: map[string]string{"Accept-Encoding": "identity"},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if acceptEncoding != "identity" {
+		t.Errorf("Expected identity, got %q", acceptEncoding)
+	}
+}
@@ -0,0 +1,122 @@
+package proxy
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	xproxy "golang.org/x/net/proxy"
+)
+
+type DialFunc func(ctx context.Context, addr string) (net.Conn, error)
+
+type contextDialer func(ctx context.Context, network, addr string) (net.Conn, error)
+
+func (d contextDialer) Dial(network, addr string) (net.Conn, error) {
+	return d(context.Background(), network, addr)
+}
+
+func (d contextDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	return d(ctx, network, addr)
+}
+
+func TunnelDialer(transport *http.Transport) DialFunc {
+	return tunnelDialer(transport, "https")
+}
+
+func tunnelDialer(transport *http.Transport, scheme string) DialFunc {
+	dial := contextDialer(transport.DialContext)
+	if transport.DialContext == nil {
+		dial = (&net.Dialer{}).DialContext
+	}
+	return func(ctx context.Context, addr string) (net.Conn, error) {
+		if transport.Proxy == nil {
+			return dial(ctx, "tcp", addr)
+		}
+		proxyURL, err := transport.Proxy(&http.Request{URL: &url.URL{Scheme: scheme, Host: addr}})
+		if err != nil {
+			return nil, err
+		}
+		if proxyURL == nil {
+			return dial(ctx, "tcp", addr)
+		}
+		switch strings.ToLower(proxyURL.Scheme) {
+		case "socks5", "socks5h":
+			socks, err := xproxy.FromURL(proxyURL, dial)
+			if err != nil {
+				return nil, err
+			}
+			return socks.(xproxy.ContextDialer).DialContext(ctx, "tcp", addr)
+		default:
+			return connectTunnel(ctx, dial, proxyURL, addr)
+		}
+	}
+}
+
+func connectTunnel(ctx context.Context, dial contextDialer, proxyURL *url.URL, addr string) (net.Conn, error) {
+	proxyAddr := proxyURL.Host
+	if proxyURL.Port() == "" {
+		port := "80"
+		if strings.EqualFold(proxyURL.Scheme, "https") {
+			port = "443"
+		}
+		proxyAddr = net.JoinHostPort(proxyURL.Hostname(), port)
+	}
+	conn, err := dial(ctx, "tcp", proxyAddr)
+	if err != nil {
+		return nil, err
+	}
+	if strings.EqualFold(proxyURL.Scheme, "https") {
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: proxyURL.Hostname()})
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		conn = tlsConn
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: addr},
+		Host:   addr,
+		Header: make(http.Header),
+	}
+	if user := proxyURL.User; user != nil {
+		password, _ := user.Password()
+		req.Header.Set("Proxy-Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(user.Username()+":"+password)))
+	}
+	if err := req.Write(conn); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, req)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		conn.Close()
+		return nil, errors.New("proxy CONNECT failed: " + resp.Status)
+	}
+	conn.SetDeadline(time.Time{})
+	return &bufferedConn{Conn: conn, reader: reader}, nil
+}
+
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (c *bufferedConn) Read(p []byte) (int, error) {
+	return c.reader.Read(p)
+}
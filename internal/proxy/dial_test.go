@@ -0,0 +1,150 @@
+package proxy
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hc/hc/internal/models"
+)
+
+func startEchoServer(t *testing.T) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				io.Copy(conn, conn)
+			}()
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func startConnectProxy(t *testing.T, upstreamAddr string, gotAuth *string) string {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*gotAuth = r.Header.Get("Proxy-Authorization")
+		if r.Method != http.MethodConnect || r.Host == "denied.example.test:443" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		upstream, err := net.Dial("tcp", upstreamAddr)
+		if err != nil {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		conn, buf, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			upstream.Close()
+			return
+		}
+		go func() {
+			defer upstream.Close()
+			io.Copy(upstream, buf)
+		}()
+		go func() {
+			defer conn.Close()
+			io.Copy(conn, upstream)
+		}()
+	}))
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func TestTunnelDialer(t *testing.T) {
+	echo := startEchoServer(t)
+	var gotAuth string
+	proxyURL := startConnectProxy(t, echo, &gotAuth)
+
+	tests := []struct {
+		name     string
+		settings models.Settings
+		addr     string
+		wantAuth string
+		wantErr  bool
+	}{
+		{
+			name:     "direct",
+			settings: models.Settings{Proxy: models.ProxySettings{Mode: models.ProxyModeNone}},
+			addr:     echo,
+		},
+		{
+			name: "host override",
+			settings: models.Settings{
+				Proxy:         models.ProxySettings{Mode: models.ProxyModeNone},
+				HostOverrides: map[string]string{"grpc.example.test:50051": echo},
+			},
+			addr: "grpc.example.test:50051",
+		},
+		{
+			name:     "connect proxy",
+			settings: models.Settings{Proxy: models.ProxySettings{Mode: models.ProxyModeManual, URL: proxyURL, Username: "user", Password: "secret"}},
+			addr:     "grpc.example.test:50051",
+			wantAuth: "Basic dXNlcjpzZWNyZXQ=",
+		},
+		{
+			name: "no proxy",
+			settings: models.Settings{
+				Proxy:         models.ProxySettings{Mode: models.ProxyModeManual, URL: "http://127.0.0.1:1", NoProxy: []string{"grpc.example.test"}},
+				HostOverrides: map[string]string{"grpc.example.test": echo},
+			},
+			addr: "grpc.example.test:50051",
+		},
+		{
+			name:     "proxy rejects",
+			settings: models.Settings{Proxy: models.ProxySettings{Mode: models.ProxyModeManual, URL: proxyURL}},
+			addr:     "denied.example.test:443",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotAuth = ""
+			transport, err := newTransport(&tt.settings)
+			if err != nil {
+				t.Fatalf("newTransport() error = %v", err)
+			}
+			conn, err := TunnelDialer(transport)(t.Context(), tt.addr)
+			if tt.wantErr {
+				if err == nil {
+					conn.Close()
+					t.Fatal("Expected an error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("TunnelDialer() error = %v", err)
+			}
+			defer conn.Close()
+			if _, err := conn.Write([]byte("ping\n")); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			line, err := bufio.NewReader(conn).ReadString('\n')
+			if err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+			if strings.TrimSpace(line) != "ping" {
+				t.Errorf("Expected echo ping, got %q", line)
+			}
+			if gotAuth != tt.wantAuth {
+				t.Errorf("Expected Proxy-Authorization %q, got %q", tt.wantAuth, gotAuth)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package proxy
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+
+	"github.com/hc/hc/internal/models"
+)
+
+const (
+	ErrorCodeCancelled           = "cancelled"
+	ErrorCodeTimeout             = "timeout"
+	ErrorCodeDNSNotFound         = "dns_not_found"
+	ErrorCodeDNSFailure          = "dns_failure"
+	ErrorCodeConnectionRefused   = "connection_refused"
+	ErrorCodeConnectionReset     = "connection_reset"
+	ErrorCodeConnectionClosed    = "connection_closed"
+	ErrorCodeHostUnreachable     = "host_unreachable"
+	ErrorCodeNetworkUnreachable  = "network_unreachable"
+	ErrorCodeSocketNotFound      = "socket_not_found"
+	ErrorCodeCertificateUnknown  = "certificate_unknown_authority"
+	ErrorCodeCertificateHostname = "certificate_hostname_mismatch"
+	ErrorCodeCertificateExpired  = "certificate_expired"
+	ErrorCodeCertificateInvalid  = "certificate_invalid"
+	ErrorCodeTLSNotTLS           = "tls_not_tls"
+	ErrorCodeTLSHandshake        = "tls_handshake_failed"
+	ErrorCodeRequestFailed       = "request_failed"
+)
+
+type RequestError struct {
+	Code        string
+	Explanation string
+	Timing      *models.Timing
+	Err         error
+}
+
+func (e *RequestError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RequestError) Unwrap() error {
+	return e.Err
+}
+
+func newRequestError(err error, timing *models.Timing) *RequestError {
+	code, explanation := ClassifyError(err)
+	return &RequestError{
+		Code:        code,
+		Explanation: explanation,
+		Timing:      timing,
+		Err:         err,
+	}
+}
+
+func ClassifyError(err error) (code, explanation string) {
+	var (
+		dnsErr         *net.DNSError
+		unknownAuthErr x509.UnknownAuthorityError
+		hostnameErr    x509.HostnameError
+		invalidCertErr x509.CertificateInvalidError
+		recordErr      tls.RecordHeaderError
+		alertErr       tls.AlertError
+		netErr         net.Error
+	)
+	switch {
+	case errors.Is(err, context.Canceled):
+		return ErrorCodeCancelled, "The request was cancelled"
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrorCodeTimeout, "The server did not respond before the timeout"
+	case errors.As(err, &dnsErr):
+		if dnsErr.IsNotFound {
+			return ErrorCodeDNSNotFound, "The host name could not be resolved; check the URL for typos"
+		}
+		if dnsErr.IsTimeout {
+			return ErrorCodeTimeout, "The DNS lookup timed out"
+		}
+		return ErrorCodeDNSFailure, "The DNS lookup failed; check your network or DNS server"
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return ErrorCodeConnectionRefused, "The connection was refused; nothing is listening on that host and port"
+	case errors.Is(err, syscall.ECONNRESET):
+		return ErrorCodeConnectionReset, "The connection was reset by the server or a network device"
+	case errors.Is(err, syscall.EHOSTUNREACH):
+		return ErrorCodeHostUnreachable, "The host is unreachable from this machine"
+	case errors.Is(err, syscall.ENETUNREACH):
+		return ErrorCodeNetworkUnreachable, "The network is unreachable from this machine"
+	case errors.Is(err, syscall.ENOENT):
+		return ErrorCodeSocketNotFound, "The Unix socket does not exist"
+	case errors.As(err, &unknownAuthErr):
+		return ErrorCodeCertificateUnknown, "The server certificate is signed by an unknown authority"
+	case errors.As(err, &hostnameErr):
+		return ErrorCodeCertificateHostname, "The server certificate is not valid for this host name"
+	case errors.As(err, &invalidCertErr):
+		if invalidCertErr.Reason == x509.Expired {
+			return ErrorCodeCertificateExpired, "The server certificate has expired or is not yet valid"
+		}
+		return ErrorCodeCertificateInvalid, "The server certificate is invalid"
+	case errors.Is(err, http.ErrSchemeMismatch), errors.As(err, &recordErr):
+		return ErrorCodeTLSNotTLS, "The server did not answer with TLS; it may only speak plain HTTP"
+	case errors.As(err, &alertErr):
+		return ErrorCodeTLSHandshake, "The TLS handshake was rejected by the server"
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return ErrorCodeTimeout, "The server did not respond before the timeout"
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return ErrorCodeConnectionClosed, "The server closed the connection without sending a complete response"
+	}
+	return ErrorCodeRequestFailed, "The request could not be completed"
+}
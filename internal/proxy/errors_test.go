@@ -0,0 +1,131 @@
+package proxy
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/hc/hc/internal/models"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{name: "Cancelled", err: context.Canceled, want: ErrorCodeCancelled},
+		{name: "Deadline exceeded", err: &url.Error{Op: "Get", URL: "http://x", Err: context.DeadlineExceeded}, want: ErrorCodeTimeout},
+		{name: "DNS not found", err: &net.DNSError{Err: "no such host", Name: "nope.test", IsNotFound: true}, want: ErrorCodeDNSNotFound},
+		{name: "DNS timeout", err: &net.DNSError{Err: "i/o timeout", Name: "slow.test", IsTimeout: true}, want: ErrorCodeTimeout},
+		{name: "DNS server failure", err: &net.DNSError{Err: "server misbehaving", Name: "x.test"}, want: ErrorCodeDNSFailure},
+		{name: "Connection refused", err: &net.OpError{Op: "dial", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}, want: ErrorCodeConnectionRefused},
+		{name: "Connection reset", err: &net.OpError{Op: "read", Err: os.NewSyscallError("read", syscall.ECONNRESET)}, want: ErrorCodeConnectionReset},
+		{name: "Host unreachable", err: &net.OpError{Op: "dial", Err: os.NewSyscallError("connect", syscall.EHOSTUNREACH)}, want: ErrorCodeHostUnreachable},
+		{name: "Missing unix socket", err: &net.OpError{Op: "dial", Err: os.NewSyscallError("connect", syscall.ENOENT)}, want: ErrorCodeSocketNotFound},
+		{name: "Unknown authority", err: fmt.Errorf("tls: %w", x509.UnknownAuthorityError{}), want: ErrorCodeCertificateUnknown},
+		{name: "Hostname mismatch", err: fmt.Errorf("tls: %w", x509.HostnameError{Certificate: &x509.Certificate{}, Host: "a.test"}), want: ErrorCodeCertificateHostname},
+		{name: "Expired certificate", err: x509.CertificateInvalidError{Reason: x509.Expired}, want: ErrorCodeCertificateExpired},
+		{name: "Invalid certificate", err: x509.CertificateInvalidError{Reason: x509.NotAuthorizedToSign}, want: ErrorCodeCertificateInvalid},
+		{name: "Unexpected EOF", err: &url.Error{Op: "Get", URL: "http://x", Err: io.ErrUnexpectedEOF}, want: ErrorCodeConnectionClosed},
+		{name: "Unknown error", err: errors.New("something else"), want: ErrorCodeRequestFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, explanation := ClassifyError(tt.err)
+			if code != tt.want {
+				t.Errorf("ClassifyError() code = %s, want %s", code, tt.want)
+			}
+			if explanation == "" {
+				t.Error("Expected a human readable explanation")
+			}
+		})
+	}
+}
+
+func TestExecuteRequestErrorCodes(t *testing.T) {
+	// Find a port with nothing listening on it
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	closedAddr := listener.Addr().String()
+	listener.Close()
+
+	plainServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer plainServer.Close()
+
+	tlsServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer tlsServer.Close()
+
+	hangupServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, _, _ := w.(http.Hijacker).Hijack()
+		conn.Close()
+	}))
+	defer hangupServer.Close()
+
+	tests := []struct {
+		name string
+		url  string
+		want string
+	}{
+		{name: "Connection refused", url: "http://" + closedAddr, want: ErrorCodeConnectionRefused},
+		{name: "Plain HTTP server over TLS", url: "https://" + plainServer.Listener.Addr().String(), want: ErrorCodeTLSNotTLS},
+		{name: "Untrusted certificate", url: tlsServer.URL, want: ErrorCodeCertificateUnknown},
+		{name: "Server hangs up", url: hangupServer.URL, want: ErrorCodeConnectionClosed},
+	}
+
+	client := NewClient()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.ExecuteRequest(&models.Request{Method: "GET", URL: tt.url})
+			var reqErr *RequestError
+			if !errors.As(err, &reqErr) {
+				t.Fatalf("Expected *RequestError, got %T: %v", err, err)
+			}
+			if reqErr.Code != tt.want {
+				t.Errorf("Expected code %s, got %s (%v)", tt.want, reqErr.Code, err)
+			}
+			if reqErr.Timing == nil {
+				t.Error("Expected partial timing for failed request")
+			}
+		})
+	}
+}
+
+func TestExecuteRequestTiming(t *testing.T) {
+	delay := 50 * time.Millisecond
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(delay)
+		w.Write([]byte("ok"))
+	}))
+	defer testServer.Close()
+
+	client := NewClient()
+	client.transport.TLSClientConfig = testServer.Client().Transport.(*http.Transport).TLSClientConfig
+	resp, err := client.ExecuteRequest(&models.Request{Method: "GET", URL: testServer.URL})
+	if err != nil {
+		t.Fatalf("ExecuteRequest() error = %v", err)
+	}
+
+	if resp.Timing == nil {
+		t.Fatal("Expected timing in response")
+	}
+	if resp.Timing.FirstByte < delay.Milliseconds() {
+		t.Errorf("Expected first byte >= %dms, got %dms", delay.Milliseconds(), resp.Timing.FirstByte)
+	}
+	if resp.Timing.Total < resp.Timing.FirstByte {
+		t.Errorf("Expected total >= first byte, got %+v", resp.Timing)
+	}
+}
@@ -0,0 +1,86 @@
+package proxy
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/hc/hc/internal/models"
+)
+
+const graphQLAccept = "application/graphql-response+json, application/json"
+
+type graphQLPayload struct {
+	Query         string          `json:"query"`
+	Variables     json.RawMessage `json:"variables,omitempty"`
+	OperationName string          `json:"operationName,omitempty"`
+}
+
+func ValidateGraphQL(body *models.GraphQLBody) error {
+	if strings.TrimSpace(body.Query) == "" {
+		return errors.New("GraphQL query is required")
+	}
+	if _, err := graphQLVariables(body.Variables); err != nil {
+		return err
+	}
+	return nil
+}
+
+func graphQLVariables(variables string) (json.RawMessage, error) {
+	if strings.TrimSpace(variables) == "" {
+		return nil, nil
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal([]byte(variables), &decoded); err != nil {
+		return nil, errors.New("GraphQL variables must be a JSON object")
+	}
+	return json.RawMessage(variables), nil
+}
+
+func encodeGraphQL(req *models.Request) (*models.Request, error) {
+	if err := ValidateGraphQL(req.GraphQL); err != nil {
+		return nil, err
+	}
+	variables, _ := graphQLVariables(req.GraphQL.Variables)
+	encoded := *req
+	encoded.Headers = make(map[string]string, len(req.Headers)+2)
+	for key, value := range req.Headers {
+		encoded.Headers[http.CanonicalHeaderKey(key)] = value
+	}
+	if encoded.Headers["Accept"] == "" {
+		encoded.Headers["Accept"] = graphQLAccept
+	}
+	if strings.EqualFold(req.Method, http.MethodGet) {
+		targetURL, err := url.Parse(req.URL)
+		if err != nil {
+			return nil, err
+		}
+		query := targetURL.Query()
+		query.Set("query", req.GraphQL.Query)
+		if variables != nil {
+			query.Set("variables", string(variables))
+		}
+		if req.GraphQL.OperationName != "" {
+			query.Set("operationName", req.GraphQL.OperationName)
+		}
+		targetURL.RawQuery = query.Encode()
+		encoded.URL = targetURL.String()
+		encoded.Body = ""
+		return &encoded, nil
+	}
+	body, err := json.Marshal(graphQLPayload{
+		Query:         req.GraphQL.Query,
+		Variables:     variables,
+		OperationName: req.GraphQL.OperationName,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if encoded.Headers["Content-Type"] == "" {
+		encoded.Headers["Content-Type"] = "application/json"
+	}
+	encoded.Body = string(body)
+	return &encoded, nil
+}
@@ -0,0 +1,138 @@
+package proxy
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hc/hc/internal/models"
+)
+
+func TestValidateGraphQL(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    models.GraphQLBody
+		wantErr bool
+	}{
+		{name: "Query only", body: models.GraphQLBody{Query: "{ me { id } }"}},
+		{name: "With variables", body: models.GraphQLBody{Query: "query($id: ID!) { user(id: $id) { id } }", Variables: `{"id": "1"}`}},
+		{name: "Blank variables", body: models.GraphQLBody{Query: "{ me { id } }", Variables: "  "}},
+		{name: "Empty query", body: models.GraphQLBody{Query: "  "}, wantErr: true},
+		{name: "Variables not an object", body: models.GraphQLBody{Query: "{ me { id } }", Variables: `[1, 2]`}, wantErr: true},
+		{name: "Invalid variables JSON", body: models.GraphQLBody{Query: "{ me { id } }", Variables: `{"id":`}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateGraphQL(&tt.body)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateGraphQL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExecuteGraphQLRequest(t *testing.T) {
+	var (
+		gotMethod      string
+		gotQuery       map[string]string
+		gotPayload     map[string]any
+		gotContentType string
+		gotAccept      string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod = r.Method
+		gotContentType = r.Header.Get("Content-Type")
+		gotAccept = r.Header.Get("Accept")
+		gotQuery = map[string]string{}
+		for key := range r.URL.Query() {
+			gotQuery[key] = r.URL.Query().Get(key)
+		}
+		gotPayload = nil
+		body, _ := io.ReadAll(r.Body)
+		if len(body) > 0 {
+			json.Unmarshal(body, &gotPayload)
+		}
+		w.Write([]byte(`{"data": {}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient()
+	graphQL := &models.GraphQLBody{
+		Query:         "query GetUser($id: ID!) { user(id: $id) { id } }",
+		Variables:     `{"id": "42"}`,
+		OperationName: "GetUser",
+	}
+
+	t.Run("POST", func(t *testing.T) {
+		_, err := client.ExecuteRequest(&models.Request{Method: "POST", URL: server.URL, GraphQL: graphQL})
+		if err != nil {
+			t.Fatalf("ExecuteRequest() error = %v", err)
+		}
+		if gotMethod != "POST" || gotContentType != "application/json" {
+			t.Errorf("Expected POST application/json, got %s %s", gotMethod, gotContentType)
+		}
+		if gotAccept != graphQLAccept {
+			t.Errorf("Expected Accept %q, got %q", graphQLAccept, gotAccept)
+		}
+		if gotPayload["query"] != graphQL.Query || gotPayload["operationName"] != "GetUser" {
+			t.Errorf("Unexpected payload %v", gotPayload)
+		}
+		variables, ok := gotPayload["variables"].(map[string]any)
+		if !ok || variables["id"] != "42" {
+			t.Errorf("Expected variables to be sent as an object, got %v", gotPayload["variables"])
+		}
+	})
+
+	t.Run("GET", func(t *testing.T) {
+		_, err := client.ExecuteRequest(&models.Request{Method: "GET", URL: server.URL + "/graphql?v=1", GraphQL: graphQL})
+		if err != nil {
+			t.Fatalf("ExecuteRequest() error = %v", err)
+		}
+		if gotPayload != nil {
+			t.Errorf("Expected no body for GET, got %v", gotPayload)
+		}
+		want := map[string]string{
+			"v":             "1",
+			"query":         graphQL.Query,
+			"variables":     graphQL.Variables,
+			"operationName": "GetUser",
+		}
+		for key, value := range want {
+			if gotQuery[key] != value {
+				t.Errorf("Expected query param %s=%q, got %q", key, value, gotQuery[key])
+			}
+		}
+	})
+
+	t.Run("Custom headers are kept", func(t *testing.T) {
+		_, err := client.ExecuteRequest(&models.Request{
+			Method:  "POST",
+			URL:     server.URL,
+			Headers: map[string]string{"accept": "application/json"},
+			GraphQL: &models.GraphQLBody{Query: "{ me { id } }"},
+		})
+		if err != nil {
+			t.Fatalf("ExecuteRequest() error = %v", err)
+		}
+		if gotAccept != "application/json" {
+			t.Errorf("Expected custom Accept header, got %q", gotAccept)
+		}
+		if _, ok := gotPayload["variables"]; ok {
+			t.Errorf("Expected variables to be omitted, got %v", gotPayload)
+		}
+	})
+
+	t.Run("Invalid variables", func(t *testing.T) {
+		_, err := client.ExecuteRequest(&models.Request{
+			Method:  "POST",
+			URL:     server.URL,
+			GraphQL: &models.GraphQLBody{Query: "{ me { id } }", Variables: "nope"},
+		})
+		if err == nil {
+			t.Error("Expected error for invalid variables")
+		}
+	})
+}
@@ -0,0 +1,139 @@
+package proxy
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/hc/hc/internal/models"
+)
+
+var variablePattern = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_.-]+)\s*\}\}`)
+
+func ValidateAuth(auth *models.Auth) error {
+	if auth == nil {
+		return nil
+	}
+	switch auth.Type {
+	case models.AuthTypeNone:
+		return nil
+	case models.AuthTypeBasic:
+		if auth.Username == "" {
+			return errors.New("basic auth requires a username")
+		}
+		return nil
+	case models.AuthTypeBearer:
+		if auth.Token == "" {
+			return errors.New("bearer auth requires a token")
+		}
+		return nil
+	case models.AuthTypeAPIKey:
+		if auth.Key == "" {
+			return errors.New("API key auth requires a key")
+		}
+		if auth.In != "" && auth.In != models.AuthInHeader && auth.In != models.AuthInQuery {
+			return errors.New("API key location must be header or query")
+		}
+		return nil
+	}
+	return errors.New("unsupported auth type: " + auth.Type)
+}
+
+func ResolveRequest(req *models.Request, folders []models.Folder) *models.EffectiveRequest {
+	resolved := *req
+	resolved.Headers = make(map[string]string)
+	variables := make(map[string]string)
+	hostOverrides := make(map[string]string)
+	var baseURL string
+	var auth *models.Auth
+	for _, folder := range folders {
+		for key, value := range folder.Headers {
+			resolved.Headers[http.CanonicalHeaderKey(key)] = value
+		}
+		for key, value := range folder.Variables {
+			variables[key] = value
+		}
+		for host, address := range folder.HostOverrides {
+			hostOverrides[host] = address
+		}
+		if folder.BaseURL != "" {
+			baseURL = folder.BaseURL
+		}
+		if folder.Auth != nil {
+			auth = folder.Auth
+		}
+	}
+	for key, value := range req.Headers {
+		resolved.Headers[http.CanonicalHeaderKey(key)] = value
+	}
+	if req.Auth != nil {
+		auth = req.Auth
+	}
+	if auth != nil {
+		copied := *auth
+		copied.Username = substitute(copied.Username, variables)
+		copied.Password = substitute(copied.Password, variables)
+		copied.Token = substitute(copied.Token, variables)
+		copied.Value = substitute(copied.Value, variables)
+		resolved.Auth = &copied
+	}
+	resolved.URL = substitute(joinBaseURL(baseURL, req.URL), variables)
+	for key, value := range resolved.Headers {
+		resolved.Headers[key] = substitute(value, variables)
+	}
+	resolved.Body = substitute(req.Body, variables)
+	if req.GraphQL != nil {
+		graphQL := *req.GraphQL
+		graphQL.Query = substitute(graphQL.Query, variables)
+		graphQL.Variables = substitute(graphQL.Variables, variables)
+		resolved.GraphQL = &graphQL
+	}
+	for host, address := range hostOverrides {
+		hostOverrides[host] = substitute(address, variables)
+	}
+	return &models.EffectiveRequest{Request: resolved, Variables: variables, HostOverrides: hostOverrides}
+}
+
+func joinBaseURL(baseURL, url string) string {
+	if baseURL == "" || strings.Contains(url, "://") || strings.HasPrefix(url, "{{") {
+		return url
+	}
+	if url == "" {
+		return baseURL
+	}
+	return strings.TrimRight(baseURL, "/") + "/" + strings.TrimLeft(url, "/")
+}
+
+func substitute(value string, variables map[string]string) string {
+	if len(variables) == 0 || !strings.Contains(value, "{{") {
+		return value
+	}
+	return variablePattern.ReplaceAllStringFunc(value, func(match string) string {
+		name := variablePattern.FindStringSubmatch(match)[1]
+		if replacement, ok := variables[name]; ok {
+			return replacement
+		}
+		return match
+	})
+}
+
+func applyAuth(req *http.Request, auth *models.Auth) {
+	if auth == nil {
+		return
+	}
+	switch auth.Type {
+	case models.AuthTypeBasic:
+		req.SetBasicAuth(auth.Username, auth.Password)
+	case models.AuthTypeBearer:
+		req.Header.Set("Authorization", "Bearer "+auth.Token)
+	case models.AuthTypeAPIKey:
+		if auth.In == models.AuthInQuery {
+			query := req.URL.Query()
+			query.Set(auth.Key, auth.Value)
+			req.URL.RawQuery = query.Encode()
+			return
+		}
+		req.Header.Set(auth.Key, auth.Value)
+	}
+}
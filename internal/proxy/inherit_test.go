@@ -0,0 +1,206 @@
+package proxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hc/hc/internal/models"
+)
+
+func TestValidateAuth(t *testing.T) {
+	tests := []struct {
+		name    string
+		auth    *models.Auth
+		wantErr bool
+	}{
+		{name: "nil", auth: nil},
+		{name: "none", auth: &models.Auth{Type: models.AuthTypeNone}},
+		{name: "basic", auth: &models.Auth{Type: models.AuthTypeBasic, Username: "user"}},
+		{name: "basic without username", auth: &models.Auth{Type: models.AuthTypeBasic}, wantErr: true},
+		{name: "bearer", auth: &models.Auth{Type: models.AuthTypeBearer, Token: "abc"}},
+		{name: "bearer without token", auth: &models.Auth{Type: models.AuthTypeBearer}, wantErr: true},
+		{name: "api key", auth: &models.Auth{Type: models.AuthTypeAPIKey, Key: "X-Api-Key", Value: "v"}},
+		{name: "api key in query", auth: &models.Auth{Type: models.AuthTypeAPIKey, Key: "key", In: models.AuthInQuery}},
+		{name: "api key bad location", auth: &models.Auth{Type: models.AuthTypeAPIKey, Key: "key", In: "cookie"}, wantErr: true},
+		{name: "unknown", auth: &models.Auth{Type: "digest"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateAuth(tt.auth)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateAuth() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestResolveRequest(t *testing.T) {
+	folders := []models.Folder{
+		{
+			Name:          "Root",
+			Headers:       map[string]string{"accept": "application/json", "X-Team": "core"},
+			Auth:          &models.Auth{Type: models.AuthTypeBearer, Token: "{{token}}"},
+			BaseURL:       "https://{{host}}/v1/",
+			Variables:     map[string]string{"host": "api.example.com", "token": "root-token", "id": "1"},
+			HostOverrides: map[string]string{"api.example.com": "10.0.0.1", "auth.example.com": "10.0.0.2"},
+		},
+		{
+			Name:          "Service",
+			Headers:       map[string]string{"X-Team": "payments"},
+			Variables:     map[string]string{"token": "service-token", "staging": "127.0.0.1"},
+			HostOverrides: map[string]string{"api.example.com": "{{staging}}:8443"},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		request     models.Request
+		wantURL     string
+		wantHeaders map[string]string
+		wantAuth    *models.Auth
+		wantBody    string
+	}{
+		{
+			name: "inherits folder settings",
+			request: models.Request{
+				Method: "GET",
+				URL:    "/users/{{id}}",
+				Body:   `{"token": "{{token}}", "missing": "{{unknown}}"}`,
+			},
+			wantURL:     "https://api.example.com/v1/users/1",
+			wantHeaders: map[string]string{"Accept": "application/json", "X-Team": "payments"},
+			wantAuth:    &models.Auth{Type: models.AuthTypeBearer, Token: "service-token"},
+			wantBody:    `{"token": "service-token", "missing": "{{unknown}}"}`,
+		},
+		{
+			name: "request overrides",
+			request: models.Request{
+				Method:  "GET",
+				URL:     "http://localhost:8080/health",
+				Headers: map[string]string{"ACCEPT": "text/plain"},
+				Auth:    &models.Auth{Type: models.AuthTypeNone},
+			},
+			wantURL:     "http://localhost:8080/health",
+			wantHeaders: map[string]string{"Accept": "text/plain", "X-Team": "payments"},
+			wantAuth:    &models.Auth{Type: models.AuthTypeNone},
+		},
+		{
+			name:        "empty URL uses base URL",
+			request:     models.Request{Method: "GET"},
+			wantURL:     "https://api.example.com/v1/",
+			wantHeaders: map[string]string{"Accept": "application/json", "X-Team": "payments"},
+			wantAuth:    &models.Auth{Type: models.AuthTypeBearer, Token: "service-token"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			effective := ResolveRequest(&tt.request, folders)
+			if effective.URL != tt.wantURL {
+				t.Errorf("Expected URL %s, got %s", tt.wantURL, effective.URL)
+			}
+			if len(effective.Headers) != len(tt.wantHeaders) {
+				t.Errorf("Expected headers %v, got %v", tt.wantHeaders, effective.Headers)
+			}
+			for key, value := range tt.wantHeaders {
+				if effective.Headers[key] != value {
+					t.Errorf("Expected header %s=%s, got %s", key, value, effective.Headers[key])
+				}
+			}
+			if *effective.Auth != *tt.wantAuth {
+				t.Errorf("Expected auth %+v, got %+v", tt.wantAuth, effective.Auth)
+			}
+			if effective.Body != tt.wantBody {
+				t.Errorf("Expected body %s, got %s", tt.wantBody, effective.Body)
+			}
+			if effective.Variables["token"] != "service-token" {
+				t.Errorf("Expected merged variables, got %v", effective.Variables)
+			}
+			if effective.HostOverrides["api.example.com"] != "127.0.0.1:8443" || effective.HostOverrides["auth.example.com"] != "10.0.0.2" {
+				t.Errorf("Expected merged host overrides, got %v", effective.HostOverrides)
+			}
+		})
+	}
+
+	if folders[0].Auth.Token != "{{token}}" {
+		t.Errorf("Expected folder auth to be left untouched, got %s", folders[0].Auth.Token)
+	}
+
+	graphQL := &models.GraphQLBody{Query: `query { user(id: {{id}}) { name } }`, Variables: `{"token": "{{token}}"}`}
+	effective := ResolveRequest(&models.Request{Type: models.RequestTypeGraphQL, GraphQL: graphQL}, folders)
+	if effective.GraphQL.Query != `query { user(id: 1) { name } }` || effective.GraphQL.Variables != `{"token": "service-token"}` {
+		t.Errorf("Expected GraphQL query and variables to be substituted, got %+v", effective.GraphQL)
+	}
+	if graphQL.Query != `query { user(id: {{id}}) { name } }` {
+		t.Errorf("Expected request GraphQL body to be left untouched, got %s", graphQL.Query)
+	}
+}
+
+func TestExecuteRequestAppliesAuth(t *testing.T) {
+	var gotRequest *http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotRequest = r
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name  string
+		auth  *models.Auth
+		check func(r *http.Request) bool
+	}{
+		{
+			name: "basic",
+			auth: &models.Auth{Type: models.AuthTypeBasic, Username: "user", Password: "pass"},
+			check: func(r *http.Request) bool {
+				user, pass, ok := r.BasicAuth()
+				return ok && user == "user" && pass == "pass"
+			},
+		},
+		{
+			name: "bearer",
+			auth: &models.Auth{Type: models.AuthTypeBearer, Token: "abc"},
+			check: func(r *http.Request) bool {
+				return r.Header.Get("Authorization") == "Bearer abc"
+			},
+		},
+		{
+			name: "api key header",
+			auth: &models.Auth{Type: models.AuthTypeAPIKey, Key: "X-Api-Key", Value: "secret"},
+			check: func(r *http.Request) bool {
+				return r.Header.Get("X-Api-Key") == "secret"
+			},
+		},
+		{
+			name: "api key query",
+			auth: &models.Auth{Type: models.AuthTypeAPIKey, Key: "api_key", Value: "secret", In: models.AuthInQuery},
+			check: func(r *http.Request) bool {
+				return r.URL.Query().Get("api_key") == "secret" && r.URL.Query().Get("page") == "2"
+			},
+		},
+		{
+			name: "none",
+			auth: &models.Auth{Type: models.AuthTypeNone},
+			check: func(r *http.Request) bool {
+				return r.Header.Get("Authorization") == ""
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewClient().ExecuteRequest(&models.Request{
+				Method: "GET",
+				URL:    server.URL + "/?page=2",
+				Auth:   tt.auth,
+			})
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if !tt.check(gotRequest) {
+				t.Errorf("Auth was not applied as expected: headers %v, query %s", gotRequest.Header, gotRequest.URL.RawQuery)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package proxy
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/hc/hc/internal/models"
+)
+
+var KnownMethods = []models.HTTPMethod{
+	{Name: "GET", Safe: true, Idempotent: true, Reference: "RFC 9110"},
+	{Name: "HEAD", Safe: true, Idempotent: true, Reference: "RFC 9110"},
+	{Name: "POST", BodyAllowed: true, Reference: "RFC 9110"},
+	{Name: "PUT", Idempotent: true, BodyAllowed: true, Reference: "RFC 9110"},
+	{Name: "DELETE", Idempotent: true, Reference: "RFC 9110"},
+	{Name: "CONNECT", Reference: "RFC 9110"},
+	{Name: "OPTIONS", Safe: true, Idempotent: true, BodyAllowed: true, Reference: "RFC 9110"},
+	{Name: "TRACE", Safe: true, Idempotent: true, Reference: "RFC 9110"},
+	{Name: "PATCH", BodyAllowed: true, Reference: "RFC 5789"},
+	{Name: "QUERY", Safe: true, Idempotent: true, BodyAllowed: true, Reference: "draft-ietf-httpbis-safe-method-w-body"},
+	{Name: "PROPFIND", Safe: true, Idempotent: true, BodyAllowed: true, Reference: "RFC 4918"},
+	{Name: "PROPPATCH", Idempotent: true, BodyAllowed: true, Reference: "RFC 4918"},
+	{Name: "MKCOL", Idempotent: true, BodyAllowed: true, Reference: "RFC 4918"},
+	{Name: "COPY", Idempotent: true, BodyAllowed: true, Reference: "RFC 4918"},
+	{Name: "MOVE", Idempotent: true, BodyAllowed: true, Reference: "RFC 4918"},
+	{Name: "LOCK", BodyAllowed: true, Reference: "RFC 4918"},
+	{Name: "UNLOCK", Idempotent: true, Reference: "RFC 4918"},
+	{Name: "REPORT", Safe: true, Idempotent: true, BodyAllowed: true, Reference: "RFC 3253"},
+	{Name: "VERSION-CONTROL", Idempotent: true, BodyAllowed: true, Reference: "RFC 3253"},
+	{Name: "CHECKOUT", Idempotent: true, BodyAllowed: true, Reference: "RFC 3253"},
+	{Name: "CHECKIN", Idempotent: true, BodyAllowed: true, Reference: "RFC 3253"},
+	{Name: "UNCHECKOUT", Idempotent: true, BodyAllowed: true, Reference: "RFC 3253"},
+	{Name: "MKWORKSPACE", Idempotent: true, BodyAllowed: true, Reference: "RFC 3253"},
+	{Name: "UPDATE", Idempotent: true, BodyAllowed: true, Reference: "RFC 3253"},
+	{Name: "LABEL", Idempotent: true, BodyAllowed: true, Reference: "RFC 3253"},
+	{Name: "MERGE", Idempotent: true, BodyAllowed: true, Reference: "RFC 3253"},
+	{Name: "BASELINE-CONTROL", Idempotent: true, BodyAllowed: true, Reference: "RFC 3253"},
+	{Name: "MKACTIVITY", Idempotent: true, BodyAllowed: true, Reference: "RFC 3253"},
+	{Name: "ORDERPATCH", Idempotent: true, BodyAllowed: true, Reference: "RFC 3648"},
+	{Name: "ACL", Idempotent: true, BodyAllowed: true, Reference: "RFC 3744"},
+	{Name: "MKCALENDAR", Idempotent: true, BodyAllowed: true, Reference: "RFC 4791"},
+	{Name: "MKREDIRECTREF", Idempotent: true, BodyAllowed: true, Reference: "RFC 4437"},
+	{Name: "UPDATEREDIRECTREF", Idempotent: true, BodyAllowed: true, Reference: "RFC 4437"},
+	{Name: "SEARCH", Safe: true, Idempotent: true, BodyAllowed: true, Reference: "RFC 5323"},
+	{Name: "BIND", Idempotent: true, BodyAllowed: true, Reference: "RFC 5842"},
+	{Name: "UNBIND", Idempotent: true, BodyAllowed: true, Reference: "RFC 5842"},
+	{Name: "REBIND", Idempotent: true, BodyAllowed: true, Reference: "RFC 5842"},
+	{Name: "LINK", Idempotent: true, BodyAllowed: true, Reference: "RFC 2068"},
+	{Name: "UNLINK", Idempotent: true, BodyAllowed: true, Reference: "RFC 2068"},
+	{Name: "PURGE", Idempotent: true, Reference: "non-standard (Varnish, Squid, Fastly)"},
+}
+
+func ValidateMethod(method string) error {
+	if !isToken(method) {
+		return errors.New("invalid HTTP method: " + method)
+	}
+	return nil
+}
+
+func LookupMethod(method string) (models.HTTPMethod, bool) {
+	for _, known := range KnownMethods {
+		if known.Name == method {
+			return known, true
+		}
+	}
+	return models.HTTPMethod{}, false
+}
+
+func MethodWarnings(method string, hasBody bool) []string {
+	var warnings []string
+	known, ok := LookupMethod(method)
+	if !ok {
+		if upper, found := LookupMethod(strings.ToUpper(method)); found {
+			return append(warnings, "method names are case-sensitive: "+method+" is sent as-is and is not the same as "+upper.Name)
+		}
+		return append(warnings, method+" is not a registered method: servers and intermediaries may reject it, and it is treated as neither safe nor idempotent")
+	}
+	if hasBody && !known.BodyAllowed {
+		warnings = append(warnings, known.Name+" requests have no defined body semantics: servers may ignore or reject the body")
+	}
+	return warnings
+}
+
+func isToken(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if !isTokenChar(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func isTokenChar(r rune) bool {
+	switch {
+	case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		return true
+	}
+	return strings.ContainsRune("!#$%&'*+-.^_`|~", r)
+}
@@ -0,0 +1,130 @@
+package proxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hc/hc/internal/models"
+)
+
+func TestKnownMethods(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, method := range KnownMethods {
+		if seen[method.Name] {
+			t.Errorf("Duplicate method %s", method.Name)
+		}
+		seen[method.Name] = true
+		if err := ValidateMethod(method.Name); err != nil {
+			t.Errorf("Known method %s is not a valid token: %v", method.Name, err)
+		}
+		if method.Safe && !method.Idempotent {
+			t.Errorf("Safe method %s must be idempotent", method.Name)
+		}
+	}
+}
+
+func TestLookupMethod(t *testing.T) {
+	tests := []struct {
+		method         string
+		wantFound      bool
+		wantSafe       bool
+		wantIdempotent bool
+		wantBody       bool
+	}{
+		{method: "GET", wantFound: true, wantSafe: true, wantIdempotent: true},
+		{method: "POST", wantFound: true, wantBody: true},
+		{method: "PUT", wantFound: true, wantIdempotent: true, wantBody: true},
+		{method: "PROPFIND", wantFound: true, wantSafe: true, wantIdempotent: true, wantBody: true},
+		{method: "MKCOL", wantFound: true, wantIdempotent: true, wantBody: true},
+		{method: "LOCK", wantFound: true, wantBody: true},
+		{method: "PURGE", wantFound: true, wantIdempotent: true},
+		{method: "get"},
+		{method: "FROBNICATE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			method, found := LookupMethod(tt.method)
+			if found != tt.wantFound {
+				t.Fatalf("LookupMethod() found = %v, want %v", found, tt.wantFound)
+			}
+			if method.Safe != tt.wantSafe || method.Idempotent != tt.wantIdempotent || method.BodyAllowed != tt.wantBody {
+				t.Errorf("LookupMethod() = %+v", method)
+			}
+		})
+	}
+}
+
+func TestMethodWarnings(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		hasBody  bool
+		contains string
+	}{
+		{name: "Known method", method: "POST", hasBody: true},
+		{name: "GET without body", method: "GET"},
+		{name: "GET with body", method: "GET", hasBody: true, contains: "no defined body semantics"},
+		{name: "Custom method", method: "FROBNICATE", contains: "not a registered method"},
+		{name: "Lowercase method", method: "get", contains: "case-sensitive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warnings := MethodWarnings(tt.method, tt.hasBody)
+			if tt.contains == "" {
+				if len(warnings) != 0 {
+					t.Errorf("Expected no warnings, got %v", warnings)
+				}
+				return
+			}
+			if len(warnings) != 1 || !strings.Contains(warnings[0], tt.contains) {
+				t.Errorf("Expected warning containing %q, got %v", tt.contains, warnings)
+			}
+		})
+	}
+}
+
+func TestExecuteRequestCustomMethods(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Method", r.Method)
+		w.WriteHeader(http.StatusMultiStatus)
+	}))
+	defer server.Close()
+
+	client := NewClient()
+	tests := []struct {
+		method      string
+		wantWarning bool
+	}{
+		{method: "PROPFIND"},
+		{method: "MKCOL"},
+		{method: "REPORT"},
+		{method: "PURGE", wantWarning: true},
+		{method: "BAN", wantWarning: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			resp, err := client.ExecuteRequest(&models.Request{
+				Method: tt.method,
+				URL:    server.URL,
+				Body:   `<?xml version="1.0"?><propfind xmlns="DAV:"/>`,
+				Headers: map[string]string{
+					"Content-Type": "application/xml",
+				},
+			})
+			if err != nil {
+				t.Fatalf("ExecuteRequest() error = %v", err)
+			}
+			if resp.Headers["X-Method"] != tt.method {
+				t.Errorf("Expected server to receive %s, got %s", tt.method, resp.Headers["X-Method"])
+			}
+			if (len(resp.Warnings) > 0) != tt.wantWarning {
+				t.Errorf("Unexpected warnings for %s: %v", tt.method, resp.Warnings)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package proxy
+
+import (
+	"crypto/tls"
+	"net/http"
+	"strconv"
+
+	"github.com/hc/hc/internal/models"
+)
+
+func (p *preparedRequest) metrics(resp *http.Response, body, decoded []byte) models.ResponseMetrics {
+	metrics := models.ResponseMetrics{
+		Protocol:              protocolName(resp),
+		RequestHeadersSize:    p.headersSize.Load() + 2,
+		RequestBodySize:       p.bodySize,
+		RequestCompressedSize: p.compressedSize,
+		HeadersSize:           responseHeadersSize(resp),
+		EncodedSize:           int64(len(body)),
+		BodySize:              int64(len(decoded)),
+	}
+	if resp.ProtoMajor == 1 {
+		metrics.RequestHeadersSize += int64(len(p.httpReq.Method) + len(" ") + len(p.httpReq.URL.RequestURI()) + len(" HTTP/1.1\r\n"))
+	}
+	if resp.TLS != nil {
+		metrics.TLSVersion, metrics.CipherSuite = tlsDetails(resp.TLS)
+	}
+	return metrics
+}
+
+func headerFieldSize(key string, values []string) int64 {
+	var size int64
+	for _, value := range values {
+		size += int64(len(key) + len(": ") + len(value) + len("\r\n"))
+	}
+	return size
+}
+
+func responseHeadersSize(resp *http.Response) int64 {
+	size := int64(len(resp.Proto) + len(" ") + len(resp.Status) + len("\r\n") + len("\r\n"))
+	for key, values := range resp.Header {
+		size += headerFieldSize(key, values)
+	}
+	return size
+}
+
+func protocolName(resp *http.Response) string {
+	if resp.ProtoMajor == 2 || resp.ProtoMajor == 3 {
+		return "HTTP/" + strconv.Itoa(resp.ProtoMajor)
+	}
+	return resp.Proto
+}
+
+func tlsDetails(state *tls.ConnectionState) (string, string) {
+	return tls.VersionName(state.Version), tls.CipherSuiteName(state.CipherSuite)
+}
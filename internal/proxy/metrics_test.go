@@ -0,0 +1,99 @@
+package proxy
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hc/hc/internal/models"
+)
+
+func TestExecuteRequestMetricsHTTP1(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Test", "value")
+		w.Write([]byte("hello world"))
+	}))
+	defer server.Close()
+
+	resp, err := NewClient().ExecuteRequest(&models.Request{
+		Method:  "POST",
+		URL:     server.URL + "/path?q=1",
+		Headers: map[string]string{"X-Custom": "abc"},
+		Body:    `{"a":1}`,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if resp.Protocol != "HTTP/1.1" {
+		t.Errorf("Expected protocol HTTP/1.1, got %q", resp.Protocol)
+	}
+	if resp.TLSVersion != "" || resp.CipherSuite != "" {
+		t.Errorf("Expected no TLS details, got %q and %q", resp.TLSVersion, resp.CipherSuite)
+	}
+	if resp.RequestBodySize != 7 {
+		t.Errorf("Expected request body size 7, got %d", resp.RequestBodySize)
+	}
+	minRequestHeaders := int64(len("POST /path?q=1 HTTP/1.1\r\n") + len("X-Custom: abc\r\n") + len("\r\n"))
+	if resp.RequestHeadersSize < minRequestHeaders {
+		t.Errorf("Expected request headers size of at least %d, got %d", minRequestHeaders, resp.RequestHeadersSize)
+	}
+	minResponseHeaders := int64(len("HTTP/1.1 200 OK\r\n") + len("X-Test: value\r\n") + len("\r\n"))
+	if resp.HeadersSize < minResponseHeaders {
+		t.Errorf("Expected response headers size of at least %d, got %d", minResponseHeaders, resp.HeadersSize)
+	}
+	if resp.EncodedSize != 11 || resp.BodySize != 11 {
+		t.Errorf("Expected body sizes 11, got %d and %d", resp.EncodedSize, resp.BodySize)
+	}
+}
+
+func TestExecuteRequestMetricsCompressedBody(t *testing.T) {
+	payload := []byte("compressible compressible compressible compressible")
+	compressed := compress(t, "gzip", payload)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Write(compressed)
+	}))
+	defer server.Close()
+
+	resp, err := NewClient().ExecuteRequest(&models.Request{Method: "GET", URL: server.URL})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if resp.EncodedSize != int64(len(compressed)) {
+		t.Errorf("Expected wire body size %d, got %d", len(compressed), resp.EncodedSize)
+	}
+	if resp.BodySize != int64(len(payload)) {
+		t.Errorf("Expected decoded body size %d, got %d", len(payload), resp.BodySize)
+	}
+}
+
+func TestExecuteRequestMetricsHTTP2TLS(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+
+	client := NewClient()
+	client.transport.TLSClientConfig = &tls.Config{
+		RootCAs: server.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs,
+	}
+	resp, err := client.ExecuteRequest(&models.Request{Method: "GET", URL: server.URL})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if resp.Protocol != "HTTP/2" {
+		t.Errorf("Expected protocol HTTP/2, got %q", resp.Protocol)
+	}
+	if resp.TLSVersion != "TLS 1.3" {
+		t.Errorf("Expected TLS 1.3, got %q", resp.TLSVersion)
+	}
+	if resp.CipherSuite == "" {
+		t.Error("Expected cipher suite to be set")
+	}
+	if resp.RequestHeadersSize == 0 || resp.HeadersSize == 0 {
+		t.Errorf("Expected header sizes to be set, got %d and %d", resp.RequestHeadersSize, resp.HeadersSize)
+	}
+}
@@ -0,0 +1,47 @@
+package proxy
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/hc/hc/internal/models"
+)
+
+func ValidateProtocol(protocol, url string) error {
+	switch protocol {
+	case "", models.ProtocolHTTP1:
+		return nil
+	case models.ProtocolHTTP2:
+		if url != "" && !strings.HasPrefix(url, "https://") {
+			return errors.New("h2 requires an https:// URL, use h2c for cleartext HTTP/2")
+		}
+		return nil
+	case models.ProtocolH2C:
+		if url != "" && strings.HasPrefix(url, "https://") {
+			return errors.New("h2c requires an http:// or unix:// URL, use h2 for HTTP/2 over TLS")
+		}
+		return nil
+	}
+	return errors.New("unsupported protocol: " + protocol)
+}
+
+func applyProtocol(transport *http.Transport, protocol string) {
+	p := &http.Protocols{}
+	switch protocol {
+	case models.ProtocolHTTP1:
+		p.SetHTTP1(true)
+		if transport.TLSClientConfig != nil {
+			transport.TLSClientConfig = transport.TLSClientConfig.Clone()
+			transport.TLSClientConfig.NextProtos = slices.DeleteFunc(slices.Clone(transport.TLSClientConfig.NextProtos), func(proto string) bool {
+				return proto == "h2"
+			})
+		}
+	case models.ProtocolHTTP2:
+		p.SetHTTP2(true)
+	case models.ProtocolH2C:
+		p.SetUnencryptedHTTP2(true)
+	}
+	transport.Protocols = p
+}
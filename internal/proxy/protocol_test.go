@@ -0,0 +1,140 @@
+package proxy
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hc/hc/internal/models"
+)
+
+func TestValidateProtocol(t *testing.T) {
+	tests := []struct {
+		name     string
+		protocol string
+		url      string
+		wantErr  bool
+	}{
+		{name: "auto", protocol: "", url: "http://example.com"},
+		{name: "http1", protocol: models.ProtocolHTTP1, url: "https://example.com"},
+		{name: "h2 over https", protocol: models.ProtocolHTTP2, url: "https://example.com"},
+		{name: "h2 over http", protocol: models.ProtocolHTTP2, url: "http://example.com", wantErr: true},
+		{name: "h2c over http", protocol: models.ProtocolH2C, url: "http://example.com"},
+		{name: "h2c over unix", protocol: models.ProtocolH2C, url: "unix:///tmp/app.sock"},
+		{name: "h2c over https", protocol: models.ProtocolH2C, url: "https://example.com", wantErr: true},
+		{name: "without url", protocol: models.ProtocolHTTP2},
+		{name: "unknown", protocol: "h3", url: "https://example.com", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateProtocol(tt.protocol, tt.url)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateProtocol(%q, %q) error = %v, wantErr %v", tt.protocol, tt.url, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExecuteRequestProtocolOverTLS(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Proto))
+	}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+
+	tests := []struct {
+		protocol     string
+		wantProtocol string
+	}{
+		{protocol: "", wantProtocol: "HTTP/2"},
+		{protocol: models.ProtocolHTTP1, wantProtocol: "HTTP/1.1"},
+		{protocol: models.ProtocolHTTP2, wantProtocol: "HTTP/2"},
+	}
+
+	client := NewClient()
+	client.transport.TLSClientConfig = &tls.Config{
+		RootCAs: server.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs,
+	}
+	for _, tt := range tests {
+		t.Run("protocol "+tt.protocol, func(t *testing.T) {
+			resp, err := client.ExecuteRequest(&models.Request{
+				Method:   "GET",
+				URL:      server.URL,
+				Protocol: tt.protocol,
+			})
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if resp.Protocol != tt.wantProtocol {
+				t.Errorf("Expected protocol %s, got %s", tt.wantProtocol, resp.Protocol)
+			}
+		})
+	}
+}
+
+func TestExecuteRequestH2C(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Proto))
+	}))
+	server.Config.Protocols = &http.Protocols{}
+	server.Config.Protocols.SetHTTP1(true)
+	server.Config.Protocols.SetUnencryptedHTTP2(true)
+	server.Start()
+	defer server.Close()
+
+	tests := []struct {
+		protocol        string
+		wantProtocol    string
+		wantServerProto string
+	}{
+		{protocol: "", wantProtocol: "HTTP/1.1", wantServerProto: "HTTP/1.1"},
+		{protocol: models.ProtocolH2C, wantProtocol: "HTTP/2", wantServerProto: "HTTP/2.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run("protocol "+tt.protocol, func(t *testing.T) {
+			resp, err := NewClient().ExecuteRequest(&models.Request{
+				Method:   "GET",
+				URL:      server.URL,
+				Protocol: tt.protocol,
+			})
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if resp.Protocol != tt.wantProtocol {
+				t.Errorf("Expected protocol %s, got %s", tt.wantProtocol, resp.Protocol)
+			}
+			if resp.Body != tt.wantServerProto {
+				t.Errorf("Expected server to see %s, got %s", tt.wantServerProto, resp.Body)
+			}
+		})
+	}
+}
+
+func TestExecuteRequestH2COnHTTP1Server(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	_, err := NewClient().ExecuteRequest(&models.Request{
+		Method:   "GET",
+		URL:      server.URL,
+		Protocol: models.ProtocolH2C,
+	})
+	if err == nil {
+		t.Error("Expected error when server does not speak h2c")
+	}
+}
+
+func TestExecuteRequestInvalidProtocol(t *testing.T) {
+	_, err := NewClient().ExecuteRequest(&models.Request{
+		Method:   "GET",
+		URL:      "http://example.com",
+		Protocol: models.ProtocolHTTP2,
+	})
+	if err == nil {
+		t.Error("Expected error for h2 over http")
+	}
+}
@@ -2,91 +2,304 @@ package proxy
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"io"
+	"log/slog"
 	"net/http"
-	"slices"
+	"net/http/httptrace"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/hc/hc/internal/logger"
 	"github.com/hc/hc/internal/models"
 )
 
 type Client struct {
-	httpClient *http.Client
+	httpClient  *http.Client
+	mu          sync.RWMutex
+	settings    models.Settings
+	transport   *http.Transport
+	idleTimeout time.Duration
+}
+
+type executeOptions struct {
+	proxy         *models.ProxySettings
+	hostOverrides map[string]string
+	unixSocket    string
+	protocol      string
 }
 
 func NewClient() *Client {
+	transport, err := newTransport(&models.Settings{})
+	if err != nil {
+		logger.Get().Warn("Failed to configure default transport, ignoring system proxy", slog.String("error", err.Error()))
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+		transport.DisableCompression = true
+	}
 	return &Client{
 		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout:   30 * time.Second,
+			Transport: transport,
 		},
+		transport:   transport,
+		idleTimeout: 30 * time.Second,
+	}
+}
+
+func newTransport(settings *models.Settings) (*http.Transport, error) {
+	proxyFunc, err := ProxyFunc(&settings.Proxy)
+	if err != nil {
+		return nil, err
+	}
+	if err := ValidateHostOverrides(settings.HostOverrides); err != nil {
+		return nil, err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = proxyFunc
+	transport.DisableCompression = true
+	transport.DialContext = overrideDialContext(settings.HostOverrides)
+	return transport, nil
+}
+
+func (c *Client) ApplySettings(settings *models.Settings) error {
+	transport, err := newTransport(settings)
+	if err != nil {
+		return err
 	}
+	c.mu.Lock()
+	old := c.transport
+	c.settings = *settings
+	c.transport = transport
+	c.mu.Unlock()
+	old.CloseIdleConnections()
+	return nil
+}
+
+func (c *Client) Transport() *http.Transport {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.transport
 }
 
 func (c *Client) ExecuteRequest(req *models.Request) (*models.Response, error) {
-	httpReq, err := http.NewRequest(req.Method, req.URL, strings.NewReader(req.Body))
+	return c.ExecuteRequestContext(context.Background(), req)
+}
+
+func (c *Client) ExecuteRequestContext(ctx context.Context, req *models.Request) (*models.Response, error) {
+	return c.execute(ctx, req, &executeOptions{})
+}
+
+func (c *Client) ExecuteEffectiveContext(ctx context.Context, req *models.EffectiveRequest) (*models.Response, error) {
+	return c.execute(ctx, &req.Request, &executeOptions{hostOverrides: req.HostOverrides})
+}
+
+type preparedRequest struct {
+	httpReq        *http.Request
+	httpClient     *http.Client
+	release        func()
+	remoteAddr     string
+	timing         timingRecorder
+	bodySize       int64
+	compressedSize int64
+	headersSize    atomic.Int64
+}
+
+func (c *Client) prepare(ctx context.Context, req *models.Request, opts *executeOptions) (*preparedRequest, error) {
+	if req.GraphQL != nil {
+		encoded, err := encodeGraphQL(req)
+		if err != nil {
+			return nil, err
+		}
+		req = encoded
+	}
+	targetURL := req.URL
+	if IsUnixURL(req.URL) {
+		socketPath, requestPath, err := ParseUnixURL(req.URL)
+		if err != nil {
+			return nil, err
+		}
+		targetURL = "http://localhost" + requestPath
+		opts.unixSocket = socketPath
+	}
+	if err := ValidateProtocol(req.Protocol, targetURL); err != nil {
+		return nil, err
+	}
+	opts.protocol = req.Protocol
+	body := []byte(req.Body)
+	if req.Compression != "" && len(body) > 0 {
+		compressed, err := compressBody(body, req.Compression)
+		if err != nil {
+			return nil, err
+		}
+		body = compressed
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, req.Method, targetURL, bytes.NewReader(body))
 	if err != nil {
 		return nil, err
 	}
 	for key, value := range req.Headers {
 		httpReq.Header.Set(key, value)
 	}
+	applyAuth(httpReq, req.Auth)
+	if req.Compression != "" && len(body) > 0 {
+		httpReq.Header.Set("Content-Encoding", req.Compression)
+	}
 	if req.Body != "" && httpReq.Header.Get("Content-Type") == "" {
 		httpReq.Header.Set("Content-Type", "application/json")
 	}
-	start := time.Now()
-	resp, err := c.httpClient.Do(httpReq)
+	httpClient, release, err := c.clientFor(opts)
+	if err != nil {
+		return nil, err
+	}
+	p := &preparedRequest{
+		httpClient: httpClient,
+		release:    release,
+		bodySize:   int64(len(req.Body)),
+	}
+	if req.Compression != "" && len(body) > 0 {
+		p.compressedSize = int64(len(body))
+	}
+	p.httpReq = httpReq.WithContext(httptrace.WithClientTrace(ctx, p.timing.trace(&httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			p.remoteAddr = info.Conn.RemoteAddr().String()
+		},
+		WroteHeaderField: func(key string, values []string) {
+			p.headersSize.Add(headerFieldSize(key, values))
+		},
+	})))
+	return p, nil
+}
+
+func (c *Client) execute(ctx context.Context, req *models.Request, opts *executeOptions) (*models.Response, error) {
+	p, err := c.prepare(ctx, req, opts)
 	if err != nil {
 		return nil, err
 	}
+	defer p.release()
+	if p.httpReq.Header.Get("Accept-Encoding") == "" {
+		p.httpReq.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	start := time.Now()
+	p.timing.begin()
+	resp, err := p.httpClient.Do(p.httpReq)
+	if err != nil {
+		return nil, newRequestError(err, p.timing.result())
+	}
 	defer resp.Body.Close()
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, err
+		return nil, newRequestError(err, p.timing.result())
 	}
+	decoded := decodeResponseBody(body, resp.Header)
 	return &models.Response{
-		StatusCode: resp.StatusCode,
-		Headers:    CopyHeaders(resp.Header),
-		Body:       string(body),
-		Duration:   time.Since(start).Milliseconds(),
+		StatusCode:      resp.StatusCode,
+		Headers:         CopyHeaders(resp.Header),
+		Body:            string(decoded.body),
+		Duration:        time.Since(start).Milliseconds(),
+		RemoteAddr:      p.remoteAddr,
+		Timing:          p.timing.result(),
+		ContentEncoding: decoded.encoding,
+		Charset:         decoded.charset,
+		Warnings:        append(MethodWarnings(p.httpReq.Method, p.httpReq.ContentLength > 0), decoded.warnings...),
+		ResponseMetrics: p.metrics(resp, body, decoded.body),
 	}, nil
 }
 
+func (c *Client) clientFor(opts *executeOptions) (*http.Client, func(), error) {
+	c.mu.RLock()
+	transport := c.transport
+	settings := c.settings
+	c.mu.RUnlock()
+	httpClient := *c.httpClient
+	httpClient.Transport = transport
+	switch {
+	case opts.proxy != nil || len(opts.hostOverrides) > 0 || opts.unixSocket != "":
+		if opts.proxy != nil {
+			settings.Proxy = *opts.proxy
+		}
+		settings.HostOverrides = mergeHostOverrides(settings.HostOverrides, opts.hostOverrides)
+		var err error
+		transport, err = newTransport(&settings)
+		if err != nil {
+			return nil, nil, err
+		}
+		if opts.unixSocket != "" {
+			transport.Proxy = nil
+			transport.DialContext = unixDialContext(opts.unixSocket)
+		}
+	case opts.protocol != "":
+		transport = transport.Clone()
+	default:
+		return &httpClient, func() {}, nil
+	}
+	if opts.protocol != "" {
+		applyProtocol(transport, opts.protocol)
+	}
+	httpClient.Transport = transport
+	return &httpClient, transport.CloseIdleConnections, nil
+}
+
 type ProxyRequest struct {
-	Method  string            `json:"method"`
-	URL     string            `json:"url"`
-	Headers map[string]string `json:"headers"`
-	Body    string            `json:"body"`
+	ExecutionID string                `json:"execution_id,omitempty"`
+	RequestID   *int                  `json:"request_id,omitempty"`
+	FolderID    *int                  `json:"folder_id,omitempty"`
+	Method      string                `json:"method"`
+	URL         string                `json:"url"`
+	Headers     map[string]string     `json:"headers"`
+	Body        string                `json:"body"`
+	Compression string                `json:"compression,omitempty"`
+	Protocol    string                `json:"protocol,omitempty"`
+	Auth        *models.Auth          `json:"auth,omitempty"`
+	GraphQL     *models.GraphQLBody   `json:"graphql,omitempty"`
+	Proxy       *models.ProxySettings `json:"proxy,omitempty"`
+	Resolve     map[string]string     `json:"resolve,omitempty"`
 }
 
 func (c *Client) ProxyRequest(proxyReq *ProxyRequest) (*models.Response, error) {
-	return c.ExecuteRequest(&models.Request{
-		Method:  proxyReq.Method,
-		URL:     proxyReq.URL,
-		Headers: proxyReq.Headers,
-		Body:    proxyReq.Body,
-	})
+	return c.ProxyRequestContext(context.Background(), proxyReq)
+}
+
+func (c *Client) ProxyRequestContext(ctx context.Context, proxyReq *ProxyRequest) (*models.Response, error) {
+	return c.execute(ctx, proxyReq.request(), proxyReq.options())
+}
+
+func (p *ProxyRequest) request() *models.Request {
+	return &models.Request{
+		Method:      p.Method,
+		URL:         p.URL,
+		Headers:     p.Headers,
+		Body:        p.Body,
+		Compression: p.Compression,
+		Protocol:    p.Protocol,
+		Auth:        p.Auth,
+		GraphQL:     p.GraphQL,
+	}
+}
+
+func (p *ProxyRequest) options() *executeOptions {
+	return &executeOptions{
+		proxy:         p.Proxy,
+		hostOverrides: p.Resolve,
+	}
 }
 
 func ValidateURL(url string) error {
 	if url == "" {
 		return errors.New("URL is required")
 	}
+	if IsUnixURL(url) {
+		_, _, err := ParseUnixURL(url)
+		return err
+	}
 	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
-		return errors.New("URL must start with http:// or https://")
+		return errors.New("URL must start with http://, https:// or unix://")
 	}
 	return nil
 }
 
-func ValidateMethod(method string) error {
-	validMethods := []string{"GET", "POST", "PUT", "DELETE", "PATCH", "HEAD", "OPTIONS"}
-	if slices.Contains(validMethods, strings.ToUpper(method)) {
-		return nil
-	}
-	return errors.New("invalid HTTP method: " + method)
-}
-
 func CopyHeaders(src http.Header) map[string]string {
 	headers := make(map[string]string)
 	for key, values := range src {
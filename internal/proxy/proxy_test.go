@@ -1,7 +1,9 @@
 package proxy
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -39,6 +41,22 @@ func TestValidateURL(t *testing.T) {
 			url:     "https://example.com/search?q=test&page=1",
 			wantErr: false,
 		},
+		{
+			name:    "Valid unix socket URL",
+			url:     "unix:///var/run/docker.sock:/v1.43/containers/json",
+			wantErr: false,
+		},
+		{
+			name:    "Unix socket URL without path",
+			url:     "unix:///var/run/docker.sock",
+			wantErr: false,
+		},
+		{
+			name:    "Unix socket URL without socket path",
+			url:     "unix://:/containers/json",
+			wantErr: true,
+			errMsg:  "unix socket path is required",
+		},
 		{
 			name:    "Empty URL",
 			url:     "",
@@ -49,25 +67,25 @@ func TestValidateURL(t *testing.T) {
 			name:    "Invalid URL format",
 			url:     "not-a-url",
 			wantErr: true,
-			errMsg:  "URL must start with http:// or https://",
+			errMsg:  "URL must start with http://, https:// or unix://",
 		},
 		{
 			name:    "URL without scheme",
 			url:     "example.com",
 			wantErr: true,
-			errMsg:  "URL must start with http:// or https://",
+			errMsg:  "URL must start with http://, https:// or unix://",
 		},
 		{
 			name:    "FTP URL",
 			url:     "ftp://example.com",
 			wantErr: true,
-			errMsg:  "URL must start with http:// or https://",
+			errMsg:  "URL must start with http://, https:// or unix://",
 		},
 		{
 			name:    "File URL",
 			url:     "file:///etc/passwd",
 			wantErr: true,
-			errMsg:  "URL must start with http:// or https://",
+			errMsg:  "URL must start with http://, https:// or unix://",
 		},
 	}
 
@@ -134,10 +152,37 @@ func TestValidateMethod(t *testing.T) {
 			errMsg:  "invalid HTTP method: ",
 		},
 		{
-			name:    "Invalid method",
+			name:    "WebDAV method",
+			method:  "PROPFIND",
+			wantErr: false,
+		},
+		{
+			name:    "Custom method",
 			method:  "INVALID",
+			wantErr: false,
+		},
+		{
+			name:    "Custom method with token characters",
+			method:  "X-CACHE.BAN!",
+			wantErr: false,
+		},
+		{
+			name:    "Method with space",
+			method:  "GET /",
+			wantErr: true,
+			errMsg:  "invalid HTTP method: GET /",
+		},
+		{
+			name:    "Method with separator",
+			method:  "BAD{METHOD}",
+			wantErr: true,
+			errMsg:  "invalid HTTP method: BAD{METHOD}",
+		},
+		{
+			name:    "Non-ASCII method",
+			method:  "GÉT",
 			wantErr: true,
-			errMsg:  "invalid HTTP method: INVALID",
+			errMsg:  "invalid HTTP method: GÉT",
 		},
 		{
 			name:    "Lowercase method (should be valid)",
@@ -416,3 +461,28 @@ func TestProxyRequestDuration(t *testing.T) {
 		t.Errorf("Expected duration <= %dms, got %dms", maxDuration, resp.Duration)
 	}
 }
+
+func TestExecuteRequestContextCancel(t *testing.T) {
+	// Test that a cancelled context aborts a hung request
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer testServer.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	client := NewClient()
+	start := time.Now()
+	_, err := client.ExecuteRequestContext(ctx, &models.Request{
+		Method: "GET",
+		URL:    testServer.URL,
+	})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Errorf("Expected request to be aborted promptly, took %v", time.Since(start))
+	}
+}
@@ -0,0 +1,208 @@
+package proxy
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/hc/hc/internal/models"
+)
+
+const (
+	maxRawResponseSize = 10 << 20
+	rawDrainTimeout    = 2 * time.Second
+)
+
+type RawRequest struct {
+	ExecutionID          string `json:"execution_id,omitempty"`
+	Target               string `json:"target"`
+	Raw                  string `json:"raw"`
+	NormalizeLineEndings bool   `json:"normalize_line_endings"`
+	Insecure             bool   `json:"insecure"`
+}
+
+func ValidateRawRequest(rawReq *RawRequest) error {
+	if err := ValidateURL(rawReq.Target); err != nil {
+		return err
+	}
+	if rawReq.Raw == "" {
+		return errors.New("raw request is required")
+	}
+	return nil
+}
+
+func NormalizeLineEndings(raw string) string {
+	head, body, found := strings.Cut(strings.ReplaceAll(raw, "\r\n", "\n"), "\n\n")
+	head = strings.ReplaceAll(head, "\n", "\r\n")
+	if !found {
+		return head + "\r\n\r\n"
+	}
+	return head + "\r\n\r\n" + body
+}
+
+func (c *Client) SendRaw(ctx context.Context, rawReq *RawRequest) (*models.RawResponse, error) {
+	if err := ValidateRawRequest(rawReq); err != nil {
+		return nil, err
+	}
+	payload := rawReq.Raw
+	if rawReq.NormalizeLineEndings {
+		payload = NormalizeLineEndings(payload)
+	}
+	ctx, cancel := context.WithTimeout(ctx, c.httpClient.Timeout)
+	defer cancel()
+	start := time.Now()
+	conn, err := c.dialRaw(ctx, rawReq)
+	if err != nil {
+		return nil, newRequestError(err, nil)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	stop := context.AfterFunc(ctx, func() {
+		conn.SetDeadline(time.Now())
+	})
+	defer stop()
+	sent, err := io.WriteString(conn, payload)
+	if err != nil {
+		return nil, newRequestError(contextError(ctx, err), nil)
+	}
+	var captured bytes.Buffer
+	limited := &io.LimitedReader{R: conn, N: maxRawResponseSize + 1}
+	reader := bufio.NewReader(io.TeeReader(limited, &captured))
+	result := &models.RawResponse{
+		BytesSent:  sent,
+		RemoteAddr: conn.RemoteAddr().String(),
+	}
+	resp, parseErr := readRawResponse(reader, rawRequestMethod(payload))
+	if parseErr != nil && ctx.Err() == nil {
+		conn.SetReadDeadline(time.Now().Add(rawDrainTimeout))
+		io.Copy(io.Discard, reader)
+	}
+	if errors.Is(ctx.Err(), context.Canceled) {
+		return nil, newRequestError(ctx.Err(), nil)
+	}
+	if parseErr != nil {
+		result.ParseError = parseErr.Error()
+	} else {
+		result.Response = resp
+	}
+	if captured.Len() == 0 {
+		if parseErr == nil {
+			parseErr = io.EOF
+		}
+		return nil, newRequestError(contextError(ctx, parseErr), nil)
+	}
+	raw := captured.Bytes()
+	if len(raw) > maxRawResponseSize {
+		raw = raw[:maxRawResponseSize]
+		result.Truncated = true
+	}
+	result.BytesReceived = len(raw)
+	result.Raw, result.Encoding = encodeRaw(raw)
+	result.Duration = time.Since(start).Milliseconds()
+	if result.Response != nil {
+		result.Response.Duration = result.Duration
+		result.Response.RemoteAddr = result.RemoteAddr
+	}
+	return result, nil
+}
+
+func (c *Client) dialRaw(ctx context.Context, rawReq *RawRequest) (net.Conn, error) {
+	if IsUnixURL(rawReq.Target) {
+		socketPath, _, err := ParseUnixURL(rawReq.Target)
+		if err != nil {
+			return nil, err
+		}
+		return unixDialContext(socketPath)(ctx, "unix", "")
+	}
+	target, err := url.Parse(rawReq.Target)
+	if err != nil {
+		return nil, err
+	}
+	port := target.Port()
+	if port == "" {
+		port = "80"
+		if target.Scheme == "https" {
+			port = "443"
+		}
+	}
+	conn, err := tunnelDialer(c.Transport(), target.Scheme)(ctx, net.JoinHostPort(target.Hostname(), port))
+	if err != nil {
+		return nil, err
+	}
+	if target.Scheme != "https" {
+		return conn, nil
+	}
+	tlsConn := tls.Client(conn, &tls.Config{
+		ServerName:         target.Hostname(),
+		InsecureSkipVerify: rawReq.Insecure,
+		NextProtos:         []string{"http/1.1"},
+	})
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return tlsConn, nil
+}
+
+func readRawResponse(reader *bufio.Reader, method string) (*models.Response, error) {
+	for {
+		resp, err := http.ReadResponse(reader, &http.Request{Method: method})
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode >= 100 && resp.StatusCode < 200 && resp.StatusCode != http.StatusSwitchingProtocols {
+			continue
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		decoded := decodeResponseBody(body, resp.Header)
+		return &models.Response{
+			StatusCode:      resp.StatusCode,
+			Headers:         CopyHeaders(resp.Header),
+			Body:            string(decoded.body),
+			ContentEncoding: decoded.encoding,
+			Charset:         decoded.charset,
+			Warnings:        decoded.warnings,
+			ResponseMetrics: models.ResponseMetrics{
+				Protocol:    protocolName(resp),
+				HeadersSize: responseHeadersSize(resp),
+				EncodedSize: int64(len(body)),
+				BodySize:    int64(len(decoded.body)),
+			},
+		}, nil
+	}
+}
+
+func rawRequestMethod(raw string) string {
+	method, _, _ := strings.Cut(raw, " ")
+	return strings.ToUpper(strings.TrimSpace(method))
+}
+
+func encodeRaw(raw []byte) (string, string) {
+	if utf8.Valid(raw) {
+		return string(raw), models.RawEncodingText
+	}
+	return base64.StdEncoding.EncodeToString(raw), models.RawEncodingBase64
+}
+
+func contextError(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	return err
+}
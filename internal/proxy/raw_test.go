@@ -0,0 +1,250 @@
+package proxy
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hc/hc/internal/models"
+)
+
+func startRawServer(t *testing.T, handle func(conn net.Conn, request string)) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				reader := bufio.NewReader(conn)
+				var request strings.Builder
+				for {
+					line, err := reader.ReadString('\n')
+					request.WriteString(line)
+					if err != nil || line == "\r\n" || line == "\n" {
+						break
+					}
+				}
+				handle(conn, request.String())
+			}()
+		}
+	}()
+	return "http://" + listener.Addr().String()
+}
+
+func TestNormalizeLineEndings(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want string
+	}{
+		{
+			name: "LF headers",
+			raw:  "GET / HTTP/1.1\nHost: example.com\n\n",
+			want: "GET / HTTP/1.1\r\nHost: example.com\r\n\r\n",
+		},
+		{
+			name: "Missing blank line",
+			raw:  "GET / HTTP/1.1\nHost: example.com",
+			want: "GET / HTTP/1.1\r\nHost: example.com\r\n\r\n",
+		},
+		{
+			name: "Body is left untouched",
+			raw:  "POST / HTTP/1.1\nContent-Length: 4\n\na\nb\n",
+			want: "POST / HTTP/1.1\r\nContent-Length: 4\r\n\r\na\nb\n",
+		},
+		{
+			name: "Already CRLF",
+			raw:  "GET / HTTP/1.1\r\nHost: example.com\r\n\r\n",
+			want: "GET / HTTP/1.1\r\nHost: example.com\r\n\r\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeLineEndings(tt.raw); got != tt.want {
+				t.Errorf("NormalizeLineEndings() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateRawRequest(t *testing.T) {
+	if err := ValidateRawRequest(&RawRequest{Target: "ftp://example.com", Raw: "GET / HTTP/1.1\r\n\r\n"}); err == nil {
+		t.Error("Expected error for unsupported target")
+	}
+	if err := ValidateRawRequest(&RawRequest{Target: "http://example.com"}); err == nil {
+		t.Error("Expected error for empty raw request")
+	}
+}
+
+func TestSendRawThroughProxy(t *testing.T) {
+	target := startRawServer(t, func(conn net.Conn, _ string) {
+		conn.Write([]byte("HTTP/1.1 204 No Content\r\n\r\n"))
+	})
+	var gotAuth string
+	proxyURL := startConnectProxy(t, strings.TrimPrefix(target, "http://"), &gotAuth)
+	client := NewClient()
+	if err := client.ApplySettings(&models.Settings{Proxy: models.ProxySettings{Mode: models.ProxyModeManual, URL: proxyURL, Username: "user", Password: "secret"}}); err != nil {
+		t.Fatalf("ApplySettings() error = %v", err)
+	}
+
+	resp, err := client.SendRaw(context.Background(), &RawRequest{Target: "http://raw.example.test:8080", Raw: "GET / HTTP/1.1\r\nHost: raw.example.test\r\n\r\n"})
+	if err != nil {
+		t.Fatalf("SendRaw() error = %v", err)
+	}
+	if resp.Response == nil || resp.Response.StatusCode != http.StatusNoContent {
+		t.Errorf("Expected 204 through the proxy, got %+v", resp.Response)
+	}
+	if gotAuth != "Basic dXNlcjpzZWNyZXQ=" {
+		t.Errorf("Expected Proxy-Authorization to be sent, got %q", gotAuth)
+	}
+}
+
+func TestSendRaw(t *testing.T) {
+	client := NewClient()
+
+	t.Run("Sends exact bytes", func(t *testing.T) {
+		received := make(chan string, 1)
+		target := startRawServer(t, func(conn net.Conn, request string) {
+			received <- request
+			conn.Write([]byte("HTTP/1.1 200 OK\r\nContent-Length: 2\r\nX-Odd-CASE: yes\r\n\r\nok"))
+		})
+		raw := "GET /path HTTP/1.0\r\nhOsT: example.com\r\nX-Custom:   spaced\r\n\r\n"
+		resp, err := client.SendRaw(context.Background(), &RawRequest{Target: target, Raw: raw})
+		if err != nil {
+			t.Fatalf("SendRaw() error = %v", err)
+		}
+		if got := <-received; got != raw {
+			t.Errorf("Server received %q, want %q", got, raw)
+		}
+		if resp.BytesSent != len(raw) {
+			t.Errorf("Expected %d bytes sent, got %d", len(raw), resp.BytesSent)
+		}
+		if resp.Raw != "HTTP/1.1 200 OK\r\nContent-Length: 2\r\nX-Odd-CASE: yes\r\n\r\nok" || resp.Encoding != models.RawEncodingText {
+			t.Errorf("Unexpected raw response %q (%s)", resp.Raw, resp.Encoding)
+		}
+		if resp.Response == nil || resp.Response.StatusCode != 200 || resp.Response.Body != "ok" {
+			t.Fatalf("Unexpected parsed response %+v", resp.Response)
+		}
+		if resp.Response.Headers["X-Odd-Case"] != "yes" {
+			t.Errorf("Expected parsed headers, got %v", resp.Response.Headers)
+		}
+		if resp.RemoteAddr == "" {
+			t.Error("Expected remote address")
+		}
+	})
+
+	t.Run("Chunked response", func(t *testing.T) {
+		target := startRawServer(t, func(conn net.Conn, _ string) {
+			conn.Write([]byte("HTTP/1.1 200 OK\r\nTransfer-Encoding: chunked\r\n\r\n5\r\nhello\r\n6\r\n world\r\n0\r\n\r\n"))
+			time.Sleep(5 * time.Second)
+		})
+		start := time.Now()
+		resp, err := client.SendRaw(context.Background(), &RawRequest{Target: target, Raw: "GET / HTTP/1.1\nHost: x\n", NormalizeLineEndings: true})
+		if err != nil {
+			t.Fatalf("SendRaw() error = %v", err)
+		}
+		if time.Since(start) > 2*time.Second {
+			t.Error("Expected complete response to be returned without waiting for close")
+		}
+		if resp.Response == nil || resp.Response.Body != "hello world" {
+			t.Errorf("Expected decoded chunked body, got %+v", resp.Response)
+		}
+		if !strings.Contains(resp.Raw, "5\r\nhello\r\n") {
+			t.Errorf("Expected raw chunk framing to be kept, got %q", resp.Raw)
+		}
+	})
+
+	t.Run("HEAD response", func(t *testing.T) {
+		target := startRawServer(t, func(conn net.Conn, _ string) {
+			conn.Write([]byte("HTTP/1.1 200 OK\r\nContent-Length: 100\r\n\r\n"))
+			time.Sleep(5 * time.Second)
+		})
+		resp, err := client.SendRaw(context.Background(), &RawRequest{Target: target, Raw: "HEAD / HTTP/1.1\r\nHost: x\r\n\r\n"})
+		if err != nil {
+			t.Fatalf("SendRaw() error = %v", err)
+		}
+		if resp.Response == nil || resp.Response.Body != "" {
+			t.Errorf("Expected empty HEAD body, got %+v", resp.Response)
+		}
+	})
+
+	t.Run("Malformed response", func(t *testing.T) {
+		target := startRawServer(t, func(conn net.Conn, _ string) {
+			conn.Write([]byte("NOT HTTP AT ALL\r\n\r\n\xff\xfe"))
+		})
+		resp, err := client.SendRaw(context.Background(), &RawRequest{Target: target, Raw: "GET / HTTP/1.1\r\n\r\n"})
+		if err != nil {
+			t.Fatalf("SendRaw() error = %v", err)
+		}
+		if resp.Response != nil || resp.ParseError == "" {
+			t.Errorf("Expected parse error, got %+v", resp)
+		}
+		if resp.Encoding != models.RawEncodingBase64 {
+			t.Fatalf("Expected base64 encoding for invalid UTF-8, got %s", resp.Encoding)
+		}
+		decoded, _ := base64.StdEncoding.DecodeString(resp.Raw)
+		if string(decoded) != "NOT HTTP AT ALL\r\n\r\n\xff\xfe" {
+			t.Errorf("Unexpected raw bytes %q", decoded)
+		}
+	})
+
+	t.Run("No response", func(t *testing.T) {
+		target := startRawServer(t, func(net.Conn, string) {})
+		_, err := client.SendRaw(context.Background(), &RawRequest{Target: target, Raw: "GET / HTTP/1.1\r\n\r\n"})
+		var reqErr *RequestError
+		if !errors.As(err, &reqErr) || reqErr.Code != ErrorCodeConnectionClosed {
+			t.Errorf("Expected connection_closed error, got %v", err)
+		}
+	})
+
+	t.Run("Cancelled", func(t *testing.T) {
+		target := startRawServer(t, func(net.Conn, string) {
+			time.Sleep(5 * time.Second)
+		})
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(100*time.Millisecond, cancel)
+		_, err := client.SendRaw(ctx, &RawRequest{Target: target, Raw: "GET / HTTP/1.1\r\n\r\n"})
+		var reqErr *RequestError
+		if !errors.As(err, &reqErr) || reqErr.Code != ErrorCodeCancelled {
+			t.Errorf("Expected cancelled error, got %v", err)
+		}
+	})
+
+	t.Run("TLS", func(t *testing.T) {
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("secure " + r.Proto))
+		}))
+		defer server.Close()
+		raw := "GET / HTTP/1.1\r\nHost: example.com\r\nConnection: close\r\n\r\n"
+
+		_, err := client.SendRaw(context.Background(), &RawRequest{Target: server.URL, Raw: raw})
+		var reqErr *RequestError
+		if !errors.As(err, &reqErr) || reqErr.Code != ErrorCodeCertificateUnknown {
+			t.Errorf("Expected certificate error, got %v", err)
+		}
+
+		resp, err := client.SendRaw(context.Background(), &RawRequest{Target: server.URL, Raw: raw, Insecure: true})
+		if err != nil {
+			t.Fatalf("SendRaw() error = %v", err)
+		}
+		if resp.Response == nil || resp.Response.Body != "secure HTTP/1.1" {
+			t.Errorf("Unexpected response %+v", resp.Response)
+		}
+	})
+}
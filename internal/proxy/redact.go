@@ -0,0 +1,53 @@
+package proxy
+
+import (
+	"net/http"
+	"strings"
+)
+
+const RedactedValue = "[redacted]"
+
+var sensitiveHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+}
+
+var sensitiveHeaderParts = []string{"token", "secret", "password", "api-key", "apikey", "api_key", "session"}
+
+func IsSensitiveHeader(name string) bool {
+	if sensitiveHeaders[http.CanonicalHeaderKey(name)] {
+		return true
+	}
+	lower := strings.ToLower(name)
+	for _, part := range sensitiveHeaderParts {
+		if strings.Contains(lower, part) {
+			return true
+		}
+	}
+	return false
+}
+
+func RedactHeaders(headers map[string]string, names ...string) map[string]string {
+	if headers == nil {
+		return nil
+	}
+	redacted := make(map[string]string, len(headers))
+	for name, value := range headers {
+		if IsSensitiveHeader(name) || containsFold(names, name) {
+			value = RedactedValue
+		}
+		redacted[name] = value
+	}
+	return redacted
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,43 @@
+package proxy
+
+import "testing"
+
+func TestRedactHeaders(t *testing.T) {
+	headers := map[string]string{
+		"authorization":   "Bearer abc",
+		"Cookie":          "session=1",
+		"X-API-Key":       "key",
+		"X-Auth-Token":    "token",
+		"X-Custom-Secret": "secret",
+		"X-Tenant":        "acme",
+		"Accept":          "application/json",
+	}
+	redacted := RedactHeaders(headers, "x-tenant")
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "authorization", want: RedactedValue},
+		{name: "Cookie", want: RedactedValue},
+		{name: "X-API-Key", want: RedactedValue},
+		{name: "X-Auth-Token", want: RedactedValue},
+		{name: "X-Custom-Secret", want: RedactedValue},
+		{name: "X-Tenant", want: RedactedValue},
+		{name: "Accept", want: "application/json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if redacted[tt.name] != tt.want {
+				t.Errorf("Expected %s, got %s", tt.want, redacted[tt.name])
+			}
+		})
+	}
+	if headers["authorization"] != "Bearer abc" {
+		t.Errorf("Expected original headers to be left untouched, got %s", headers["authorization"])
+	}
+	if RedactHeaders(nil) != nil {
+		t.Error("Expected nil headers to stay nil")
+	}
+}
@@ -0,0 +1,83 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func ValidateHostOverrides(overrides map[string]string) error {
+	for host, address := range overrides {
+		if strings.TrimSpace(host) == "" {
+			return errors.New("host override requires a host name")
+		}
+		if h, port, err := net.SplitHostPort(host); err == nil {
+			if h == "" || !isValidPort(port) {
+				return errors.New("invalid host override: " + host)
+			}
+		}
+		if strings.TrimSpace(address) == "" {
+			return errors.New("host override for " + host + " requires an address")
+		}
+		if h, port, err := net.SplitHostPort(address); err == nil {
+			if h == "" || !isValidPort(port) {
+				return errors.New("invalid host override address: " + address)
+			}
+		}
+	}
+	return nil
+}
+
+func ResolveOverride(overrides map[string]string, addr string) string {
+	if len(overrides) == 0 {
+		return addr
+	}
+	if target, ok := overrides[addr]; ok {
+		return withDefaultPort(target, addr)
+	}
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	if target, ok := overrides[host]; ok {
+		return withDefaultPort(target, addr)
+	}
+	return addr
+}
+
+func mergeHostOverrides(global, local map[string]string) map[string]string {
+	merged := make(map[string]string, len(global)+len(local))
+	maps.Copy(merged, global)
+	maps.Copy(merged, local)
+	return merged
+}
+
+func overrideDialContext(overrides map[string]string) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return dialer.DialContext(ctx, network, ResolveOverride(overrides, addr))
+	}
+}
+
+func withDefaultPort(target, addr string) string {
+	if _, _, err := net.SplitHostPort(target); err == nil {
+		return target
+	}
+	_, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return target
+	}
+	return net.JoinHostPort(strings.Trim(target, "[]"), port)
+}
+
+func isValidPort(port string) bool {
+	n, err := strconv.Atoi(port)
+	return err == nil && n > 0 && n <= 65535
+}
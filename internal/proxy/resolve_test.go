@@ -0,0 +1,163 @@
+package proxy
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hc/hc/internal/models"
+)
+
+func TestValidateHostOverrides(t *testing.T) {
+	tests := []struct {
+		name      string
+		overrides map[string]string
+		wantErr   bool
+	}{
+		{
+			name:      "Nil overrides",
+			overrides: nil,
+		},
+		{
+			name:      "Host to IP",
+			overrides: map[string]string{"api.example.com": "10.0.0.5"},
+		},
+		{
+			name:      "Host and port to IP and port",
+			overrides: map[string]string{"api.example.com:443": "10.0.0.5:8443"},
+		},
+		{
+			name:      "IPv6 address",
+			overrides: map[string]string{"api.example.com": "::1"},
+		},
+		{
+			name:      "Empty host",
+			overrides: map[string]string{"": "10.0.0.5"},
+			wantErr:   true,
+		},
+		{
+			name:      "Empty address",
+			overrides: map[string]string{"api.example.com": ""},
+			wantErr:   true,
+		},
+		{
+			name:      "Invalid host port",
+			overrides: map[string]string{"api.example.com:https": "10.0.0.5"},
+			wantErr:   true,
+		},
+		{
+			name:      "Invalid address port",
+			overrides: map[string]string{"api.example.com": "10.0.0.5:99999"},
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateHostOverrides(tt.overrides)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateHostOverrides() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestResolveOverride(t *testing.T) {
+	overrides := map[string]string{
+		"api.example.com":     "10.0.0.5",
+		"api.example.com:443": "10.0.0.6:8443",
+		"v6.example.com":      "::1",
+	}
+
+	tests := []struct {
+		addr string
+		want string
+	}{
+		{addr: "api.example.com:80", want: "10.0.0.5:80"},
+		{addr: "api.example.com:443", want: "10.0.0.6:8443"},
+		{addr: "v6.example.com:8080", want: "[::1]:8080"},
+		{addr: "other.example.com:443", want: "other.example.com:443"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			if got := ResolveOverride(overrides, tt.addr); got != tt.want {
+				t.Errorf("ResolveOverride(%q) = %q, want %q", tt.addr, got, tt.want)
+			}
+		})
+	}
+
+	if got := ResolveOverride(nil, "api.example.com:80"); got != "api.example.com:80" {
+		t.Errorf("ResolveOverride() without overrides = %q", got)
+	}
+}
+
+func TestProxyRequestWithHostOverride(t *testing.T) {
+	// The TLS server only answers for example.com, so the request must keep
+	// the original host for SNI and the Host header while dialing 127.0.0.1
+	var gotHost, gotServerName string
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHost = r.Host
+		gotServerName = r.TLS.ServerName
+		w.Write([]byte("pinned"))
+	}))
+	defer testServer.Close()
+
+	_, port, _ := net.SplitHostPort(testServer.Listener.Addr().String())
+
+	client := NewClient()
+	err := client.ApplySettings(&models.Settings{
+		HostOverrides: map[string]string{"example.com": "127.0.0.1"},
+	})
+	if err != nil {
+		t.Fatalf("ApplySettings() error = %v", err)
+	}
+	// Trust the test server certificate, which is issued for example.com
+	client.transport.TLSClientConfig = testServer.Client().Transport.(*http.Transport).TLSClientConfig
+
+	resp, err := client.ProxyRequest(&ProxyRequest{
+		Method: "GET",
+		URL:    "https://example.com:" + port + "/",
+	})
+	if err != nil {
+		t.Fatalf("ProxyRequest() error = %v", err)
+	}
+
+	if resp.Body != "pinned" {
+		t.Errorf("Expected body 'pinned', got %s", resp.Body)
+	}
+	if gotHost != "example.com:"+port {
+		t.Errorf("Expected Host header example.com:%s, got %s", port, gotHost)
+	}
+	if gotServerName != "example.com" {
+		t.Errorf("Expected SNI example.com, got %s", gotServerName)
+	}
+	if resp.RemoteAddr != "127.0.0.1:"+port {
+		t.Errorf("Expected remote address 127.0.0.1:%s, got %s", port, resp.RemoteAddr)
+	}
+}
+
+func TestProxyRequestWithPerRequestResolve(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Host))
+	}))
+	defer testServer.Close()
+
+	client := NewClient()
+	resp, err := client.ProxyRequest(&ProxyRequest{
+		Method:  "GET",
+		URL:     "http://staging.example.test/",
+		Resolve: map[string]string{"staging.example.test:80": testServer.Listener.Addr().String()},
+	})
+	if err != nil {
+		t.Fatalf("ProxyRequest() error = %v", err)
+	}
+
+	if resp.Body != "staging.example.test" {
+		t.Errorf("Expected Host header staging.example.test, got %s", resp.Body)
+	}
+	if resp.RemoteAddr != testServer.Listener.Addr().String() {
+		t.Errorf("Expected remote address %s, got %s", testServer.Listener.Addr().String(), resp.RemoteAddr)
+	}
+}
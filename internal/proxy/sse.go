@@ -0,0 +1,97 @@
+package proxy
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+)
+
+type SSEEvent struct {
+	Event string
+	ID    string
+	Retry int
+	Data  string
+}
+
+func ParseSSE(r io.Reader, fn func(SSEEvent) error) error {
+	reader := &sseLineReader{reader: bufio.NewReader(r)}
+	var (
+		event   SSEEvent
+		data    []string
+		hasData bool
+	)
+	for {
+		line, err := reader.readLine()
+		if err != nil && line == "" {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		switch {
+		case line == "":
+			if hasData {
+				event.Data = strings.Join(data, "\n")
+				if err := fn(event); err != nil {
+					return err
+				}
+			}
+			event = SSEEvent{ID: event.ID}
+			data = nil
+			hasData = false
+		case strings.HasPrefix(line, ":"):
+		default:
+			field, value, _ := strings.Cut(line, ":")
+			value = strings.TrimPrefix(value, " ")
+			switch field {
+			case "data":
+				data = append(data, value)
+				hasData = true
+			case "event":
+				event.Event = value
+			case "id":
+				if !strings.Contains(value, "\x00") {
+					event.ID = value
+				}
+			case "retry":
+				if retry, err := strconv.Atoi(value); err == nil {
+					event.Retry = retry
+				}
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+	}
+}
+
+type sseLineReader struct {
+	reader *bufio.Reader
+	skipLF bool
+}
+
+func (l *sseLineReader) readLine() (string, error) {
+	var sb strings.Builder
+	for {
+		b, err := l.reader.ReadByte()
+		if err != nil {
+			return sb.String(), err
+		}
+		if l.skipLF {
+			l.skipLF = false
+			if b == '\n' {
+				continue
+			}
+		}
+		switch b {
+		case '\n':
+			return sb.String(), nil
+		case '\r':
+			l.skipLF = true
+			return sb.String(), nil
+		default:
+			sb.WriteByte(b)
+		}
+	}
+}
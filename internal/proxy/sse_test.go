@@ -0,0 +1,98 @@
+package proxy
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseSSE(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []SSEEvent
+	}{
+		{
+			name:  "Single data event",
+			input: "data: hello\n\n",
+			want:  []SSEEvent{{Data: "hello"}},
+		},
+		{
+			name:  "Named event with id and retry",
+			input: "event: token\nid: 42\nretry: 3000\ndata: {\"text\":\"hi\"}\n\n",
+			want:  []SSEEvent{{Event: "token", ID: "42", Retry: 3000, Data: `{"text":"hi"}`}},
+		},
+		{
+			name:  "Multi-line data",
+			input: "data: line1\ndata: line2\n\n",
+			want:  []SSEEvent{{Data: "line1\nline2"}},
+		},
+		{
+			name:  "Comments are ignored",
+			input: ": keep-alive\n\ndata: x\n\n",
+			want:  []SSEEvent{{Data: "x"}},
+		},
+		{
+			name:  "CRLF line endings",
+			input: "data: a\r\n\r\ndata: b\r\n\r\n",
+			want:  []SSEEvent{{Data: "a"}, {Data: "b"}},
+		},
+		{
+			name:  "CR line endings",
+			input: "data: a\r\rdata: b\r\r",
+			want:  []SSEEvent{{Data: "a"}, {Data: "b"}},
+		},
+		{
+			name:  "Last event id is carried over",
+			input: "id: 1\ndata: a\n\ndata: b\n\n",
+			want:  []SSEEvent{{ID: "1", Data: "a"}, {ID: "1", Data: "b"}},
+		},
+		{
+			name:  "Event name resets between events",
+			input: "event: start\ndata: a\n\ndata: b\n\n",
+			want:  []SSEEvent{{Event: "start", Data: "a"}, {Data: "b"}},
+		},
+		{
+			name:  "Field without space after colon",
+			input: "data:compact\n\n",
+			want:  []SSEEvent{{Data: "compact"}},
+		},
+		{
+			name:  "Incomplete trailing event is dropped",
+			input: "data: a\n\ndata: partial",
+			want:  []SSEEvent{{Data: "a"}},
+		},
+		{
+			name:  "Event without data is not dispatched",
+			input: "event: ping\n\n",
+			want:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []SSEEvent
+			err := ParseSSE(strings.NewReader(tt.input), func(event SSEEvent) error {
+				got = append(got, event)
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("ParseSSE() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseSSE() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSSECallbackError(t *testing.T) {
+	stop := errors.New("stop")
+	err := ParseSSE(strings.NewReader("data: a\n\ndata: b\n\n"), func(event SSEEvent) error {
+		return stop
+	})
+	if err != stop {
+		t.Errorf("Expected callback error to be returned, got %v", err)
+	}
+}
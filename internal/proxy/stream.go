@@ -0,0 +1,146 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"time"
+	"unicode/utf8"
+
+	"github.com/hc/hc/internal/models"
+)
+
+const streamChunkSize = 32 * 1024
+
+var errStreamIdle = fmt.Errorf("stream idle timeout: %w", context.DeadlineExceeded)
+
+func (c *Client) StreamRequest(ctx context.Context, proxyReq *ProxyRequest, emit func(models.StreamEvent) error) error {
+	return c.executeStream(ctx, proxyReq.request(), proxyReq.options(), emit)
+}
+
+func (c *Client) executeStream(ctx context.Context, req *models.Request, opts *executeOptions, emit func(models.StreamEvent) error) error {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	idle := time.AfterFunc(c.idleTimeout, func() { cancel(errStreamIdle) })
+	defer idle.Stop()
+	p, err := c.prepare(ctx, req, opts)
+	if err != nil {
+		return err
+	}
+	defer p.release()
+	p.httpClient.Timeout = 0
+	start := time.Now()
+	p.timing.begin()
+	newEvent := func(eventType string) models.StreamEvent {
+		now := time.Now()
+		return models.StreamEvent{
+			Type:      eventType,
+			Timestamp: now.UTC(),
+			Elapsed:   now.Sub(start).Milliseconds(),
+		}
+	}
+	resp, err := p.httpClient.Do(p.httpReq)
+	if err != nil {
+		return newRequestError(streamError(ctx, err), p.timing.result())
+	}
+	defer resp.Body.Close()
+	idle.Reset(c.idleTimeout)
+	headers := newEvent(models.StreamEventHeaders)
+	headers.StatusCode = resp.StatusCode
+	headers.Headers = CopyHeaders(resp.Header)
+	headers.RemoteAddr = p.remoteAddr
+	if err := emit(headers); err != nil {
+		return err
+	}
+	body := &countingReader{reader: resp.Body, read: func() { idle.Reset(c.idleTimeout) }}
+	if IsEventStream(resp.Header.Get("Content-Type")) {
+		err = ParseSSE(body, func(sse SSEEvent) error {
+			event := newEvent(models.StreamEventSSE)
+			event.Event = sse.Event
+			event.ID = sse.ID
+			event.Retry = sse.Retry
+			event.Data = sse.Data
+			return emit(event)
+		})
+	} else {
+		err = readChunks(body, func(chunk []byte) error {
+			event := newEvent(models.StreamEventChunk)
+			event.Data = string(chunk)
+			return emit(event)
+		})
+	}
+	if err != nil {
+		return newRequestError(streamError(ctx, err), p.timing.result())
+	}
+	done := newEvent(models.StreamEventDone)
+	done.Bytes = body.n
+	return emit(done)
+}
+
+func streamError(ctx context.Context, err error) error {
+	if errors.Is(context.Cause(ctx), errStreamIdle) {
+		return errStreamIdle
+	}
+	return err
+}
+
+func IsEventStream(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && mediaType == "text/event-stream"
+}
+
+func readChunks(r io.Reader, fn func([]byte) error) error {
+	buf := make([]byte, streamChunkSize)
+	var pending []byte
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			data := append(pending, buf[:n]...)
+			complete, rest := splitUTF8(data)
+			pending = append([]byte(nil), rest...)
+			if len(complete) > 0 {
+				if err := fn(complete); err != nil {
+					return err
+				}
+			}
+		}
+		if err == io.EOF {
+			if len(pending) > 0 {
+				return fn(pending)
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func splitUTF8(b []byte) (complete, rest []byte) {
+	for i := len(b) - 1; i >= 0 && i >= len(b)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(b[i]) {
+			if !utf8.FullRune(b[i:]) {
+				return b[:i], b[i:]
+			}
+			break
+		}
+	}
+	return b, nil
+}
+
+type countingReader struct {
+	reader io.Reader
+	read   func()
+	n      int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.n += int64(n)
+	if n > 0 && r.read != nil {
+		r.read()
+	}
+	return n, err
+}
@@ -0,0 +1,172 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hc/hc/internal/models"
+)
+
+func TestStreamRequestSSE(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream; charset=utf-8")
+		flusher := w.(http.Flusher)
+		for i := 1; i <= 3; i++ {
+			fmt.Fprintf(w, "id: %d\nevent: token\ndata: part-%d\n\n", i, i)
+			flusher.Flush()
+			time.Sleep(10 * time.Millisecond)
+		}
+	}))
+	defer testServer.Close()
+
+	client := NewClient()
+	var events []models.StreamEvent
+	err := client.StreamRequest(context.Background(), &ProxyRequest{Method: "GET", URL: testServer.URL}, func(event models.StreamEvent) error {
+		events = append(events, event)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("StreamRequest() error = %v", err)
+	}
+
+	if len(events) != 5 {
+		t.Fatalf("Expected 5 events (headers, 3 SSE, done), got %d: %+v", len(events), events)
+	}
+	if events[0].Type != models.StreamEventHeaders || events[0].StatusCode != http.StatusOK {
+		t.Errorf("Expected headers event first, got %+v", events[0])
+	}
+	for i, event := range events[1:4] {
+		if event.Type != models.StreamEventSSE || event.Event != "token" || event.Data != fmt.Sprintf("part-%d", i+1) || event.ID != fmt.Sprint(i+1) {
+			t.Errorf("Unexpected SSE event %d: %+v", i, event)
+		}
+		if event.Timestamp.IsZero() {
+			t.Errorf("Expected timestamp on event %d", i)
+		}
+	}
+	if events[3].Elapsed < events[1].Elapsed+10 {
+		t.Errorf("Expected events to arrive incrementally, elapsed %d then %d", events[1].Elapsed, events[3].Elapsed)
+	}
+	if events[4].Type != models.StreamEventDone || events[4].Bytes == 0 {
+		t.Errorf("Expected done event with byte count, got %+v", events[4])
+	}
+}
+
+func TestStreamRequestChunks(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flusher := w.(http.Flusher)
+		for _, chunk := range []string{"first ", "second ", "third"} {
+			w.Write([]byte(chunk))
+			flusher.Flush()
+			time.Sleep(10 * time.Millisecond)
+		}
+	}))
+	defer testServer.Close()
+
+	client := NewClient()
+	var body strings.Builder
+	chunks := 0
+	err := client.StreamRequest(context.Background(), &ProxyRequest{Method: "GET", URL: testServer.URL}, func(event models.StreamEvent) error {
+		if event.Type == models.StreamEventChunk {
+			chunks++
+			body.WriteString(event.Data)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("StreamRequest() error = %v", err)
+	}
+
+	if body.String() != "first second third" {
+		t.Errorf("Expected full body, got %q", body.String())
+	}
+	if chunks < 2 {
+		t.Errorf("Expected multiple chunks, got %d", chunks)
+	}
+}
+
+func TestStreamRequestCancel(t *testing.T) {
+	release := make(chan struct{})
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: first\n\n")
+		w.(http.Flusher).Flush()
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer testServer.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	client := NewClient()
+	err := client.StreamRequest(ctx, &ProxyRequest{Method: "GET", URL: testServer.URL}, func(event models.StreamEvent) error {
+		if event.Type == models.StreamEventSSE {
+			cancel()
+		}
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestStreamRequestIdleTimeout(t *testing.T) {
+	release := make(chan struct{})
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: first\n\n")
+		w.(http.Flusher).Flush()
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer testServer.Close()
+	defer close(release)
+
+	client := NewClient()
+	client.idleTimeout = 100 * time.Millisecond
+	var events int
+	err := client.StreamRequest(context.Background(), &ProxyRequest{Method: "GET", URL: testServer.URL}, func(event models.StreamEvent) error {
+		events++
+		return nil
+	})
+	var reqErr *RequestError
+	if !errors.As(err, &reqErr) || reqErr.Code != ErrorCodeTimeout {
+		t.Fatalf("Expected timeout error, got %v", err)
+	}
+	if events != 2 {
+		t.Errorf("Expected headers and first event before the timeout, got %d events", events)
+	}
+}
+
+func TestSplitUTF8(t *testing.T) {
+	word := []byte("日本")
+	tests := []struct {
+		name         string
+		input        []byte
+		wantComplete string
+		wantRest     []byte
+	}{
+		{name: "ASCII", input: []byte("abc"), wantComplete: "abc"},
+		{name: "Complete multibyte", input: word, wantComplete: "日本"},
+		{name: "Split multibyte", input: word[:4], wantComplete: "日", wantRest: word[3:4]},
+		{name: "Empty", input: nil, wantComplete: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			complete, rest := splitUTF8(tt.input)
+			if string(complete) != tt.wantComplete || string(rest) != string(tt.wantRest) {
+				t.Errorf("splitUTF8() = (%q, %q), want (%q, %q)", complete, rest, tt.wantComplete, tt.wantRest)
+			}
+		})
+	}
+}
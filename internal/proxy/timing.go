@@ -0,0 +1,69 @@
+package proxy
+
+import (
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"github.com/hc/hc/internal/models"
+)
+
+type timingRecorder struct {
+	mu           sync.Mutex
+	start        time.Time
+	dnsStart     time.Time
+	dnsDone      time.Time
+	connectStart time.Time
+	connectDone  time.Time
+	tlsStart     time.Time
+	tlsDone      time.Time
+	firstByte    time.Time
+}
+
+func (t *timingRecorder) trace(next *httptrace.ClientTrace) *httptrace.ClientTrace {
+	set := func(field *time.Time, onlyFirst bool) {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		if onlyFirst && !field.IsZero() {
+			return
+		}
+		*field = time.Now()
+	}
+	next.DNSStart = func(httptrace.DNSStartInfo) { set(&t.dnsStart, true) }
+	next.DNSDone = func(httptrace.DNSDoneInfo) { set(&t.dnsDone, false) }
+	next.ConnectStart = func(string, string) { set(&t.connectStart, true) }
+	next.ConnectDone = func(string, string, error) { set(&t.connectDone, false) }
+	next.TLSHandshakeStart = func() { set(&t.tlsStart, true) }
+	next.TLSHandshakeDone = func(tls.ConnectionState, error) { set(&t.tlsDone, false) }
+	next.GotFirstResponseByte = func() { set(&t.firstByte, true) }
+	return next
+}
+
+func (t *timingRecorder) begin() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.start = time.Now()
+}
+
+func (t *timingRecorder) result() *models.Timing {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return &models.Timing{
+		DNS:       phase(t.dnsStart, t.dnsDone),
+		Connect:   phase(t.connectStart, t.connectDone),
+		TLS:       phase(t.tlsStart, t.tlsDone),
+		FirstByte: phase(t.start, t.firstByte),
+		Total:     time.Since(t.start).Milliseconds(),
+	}
+}
+
+func phase(start, end time.Time) int64 {
+	if start.IsZero() {
+		return 0
+	}
+	if end.IsZero() {
+		end = time.Now()
+	}
+	return end.Sub(start).Milliseconds()
+}
@@ -0,0 +1,39 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+)
+
+const unixScheme = "unix://"
+
+func IsUnixURL(rawURL string) bool {
+	return strings.HasPrefix(rawURL, unixScheme)
+}
+
+func ParseUnixURL(rawURL string) (socketPath, requestPath string, err error) {
+	if !IsUnixURL(rawURL) {
+		return "", "", errors.New("URL must start with " + unixScheme)
+	}
+	rest := strings.TrimPrefix(rawURL, unixScheme)
+	socketPath, requestPath, found := strings.Cut(rest, ":")
+	if socketPath == "" {
+		return "", "", errors.New("unix socket path is required")
+	}
+	if !found || requestPath == "" {
+		requestPath = "/"
+	}
+	if !strings.HasPrefix(requestPath, "/") {
+		return "", "", errors.New("unix socket request path must start with /")
+	}
+	return socketPath, requestPath, nil
+}
+
+func unixDialContext(socketPath string) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	dialer := &net.Dialer{}
+	return func(ctx context.Context, _, _ string) (net.Conn, error) {
+		return dialer.DialContext(ctx, "unix", socketPath)
+	}
+}
@@ -0,0 +1,103 @@
+package proxy
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseUnixURL(t *testing.T) {
+	tests := []struct {
+		name       string
+		url        string
+		wantSocket string
+		wantPath   string
+		wantErr    bool
+	}{
+		{
+			name:       "Socket with path",
+			url:        "unix:///var/run/docker.sock:/v1.43/containers/json",
+			wantSocket: "/var/run/docker.sock",
+			wantPath:   "/v1.43/containers/json",
+		},
+		{
+			name:       "Socket with path and query",
+			url:        "unix:///var/run/docker.sock:/containers/json?all=1",
+			wantSocket: "/var/run/docker.sock",
+			wantPath:   "/containers/json?all=1",
+		},
+		{
+			name:       "Socket without path",
+			url:        "unix:///var/run/docker.sock",
+			wantSocket: "/var/run/docker.sock",
+			wantPath:   "/",
+		},
+		{
+			name:       "Relative socket path",
+			url:        "unix://sidecar.sock:/health",
+			wantSocket: "sidecar.sock",
+			wantPath:   "/health",
+		},
+		{
+			name:    "Missing socket path",
+			url:     "unix://",
+			wantErr: true,
+		},
+		{
+			name:    "Path without leading slash",
+			url:     "unix:///var/run/docker.sock:containers/json",
+			wantErr: true,
+		},
+		{
+			name:    "Not a unix URL",
+			url:     "http://localhost/",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			socket, path, err := ParseUnixURL(tt.url)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseUnixURL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if socket != tt.wantSocket || path != tt.wantPath {
+				t.Errorf("ParseUnixURL() = (%q, %q), want (%q, %q)", socket, path, tt.wantSocket, tt.wantPath)
+			}
+		})
+	}
+}
+
+func TestProxyRequestOverUnixSocket(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "hc.sock")
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Skipf("unix sockets not supported: %v", err)
+	}
+
+	testServer := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"path":"` + r.URL.RequestURI() + `"}`))
+	}))
+	testServer.Listener = listener
+	testServer.Start()
+	defer testServer.Close()
+
+	client := NewClient()
+	resp, err := client.ProxyRequest(&ProxyRequest{
+		Method: "GET",
+		URL:    "unix://" + socketPath + ":/v1.43/containers/json?all=1",
+	})
+	if err != nil {
+		t.Fatalf("ProxyRequest() error = %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", resp.StatusCode)
+	}
+	if resp.Body != `{"path":"/v1.43/containers/json?all=1"}` {
+		t.Errorf("Unexpected body: %s", resp.Body)
+	}
+}
@@ -0,0 +1,82 @@
+package proxy
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/hc/hc/internal/models"
+	"golang.org/x/net/http/httpproxy"
+)
+
+var supportedProxySchemes = []string{"http", "https", "socks5", "socks5h"}
+
+var proxyFromEnvironment = httpproxy.FromEnvironment
+
+func ValidateProxySettings(settings *models.ProxySettings) error {
+	switch settings.Mode {
+	case "", models.ProxyModeSystem, models.ProxyModeNone:
+		return nil
+	case models.ProxyModeManual:
+		_, err := parseProxyURL(settings)
+		return err
+	default:
+		return errors.New("invalid proxy mode: " + settings.Mode)
+	}
+}
+
+func ProxyFunc(settings *models.ProxySettings) (func(*http.Request) (*url.URL, error), error) {
+	if err := ValidateProxySettings(settings); err != nil {
+		return nil, err
+	}
+	var config *httpproxy.Config
+	switch settings.Mode {
+	case models.ProxyModeNone:
+		return nil, nil
+	case models.ProxyModeManual:
+		proxyURL, err := parseProxyURL(settings)
+		if err != nil {
+			return nil, err
+		}
+		config = &httpproxy.Config{
+			HTTPProxy:  proxyURL.String(),
+			HTTPSProxy: proxyURL.String(),
+			NoProxy:    strings.Join(settings.NoProxy, ","),
+		}
+	default:
+		config = proxyFromEnvironment()
+		if len(settings.NoProxy) > 0 {
+			config.NoProxy = strings.Join(slices.Concat(settings.NoProxy, []string{config.NoProxy}), ",")
+		}
+	}
+	fn := config.ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return fn(req.URL)
+	}, nil
+}
+
+func parseProxyURL(settings *models.ProxySettings) (*url.URL, error) {
+	if settings.URL == "" {
+		return nil, errors.New("proxy URL is required")
+	}
+	proxyURL, err := url.Parse(settings.URL)
+	if err != nil {
+		return nil, errors.New("invalid proxy URL: " + settings.URL)
+	}
+	if !isSupportedProxyScheme(proxyURL.Scheme) {
+		return nil, errors.New("proxy URL must start with http://, https://, socks5:// or socks5h://")
+	}
+	if proxyURL.Host == "" {
+		return nil, errors.New("proxy URL must include a host")
+	}
+	if settings.Username != "" {
+		proxyURL.User = url.UserPassword(settings.Username, settings.Password)
+	}
+	return proxyURL, nil
+}
+
+func isSupportedProxyScheme(scheme string) bool {
+	return slices.Contains(supportedProxySchemes, strings.ToLower(scheme))
+}
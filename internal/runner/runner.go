@@ -0,0 +1,97 @@
+package runner
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/hc/hc/internal/models"
+	"github.com/hc/hc/internal/proxy"
+	"github.com/hc/hc/internal/storage"
+)
+
+type Result struct {
+	Request  models.Request
+	Response *models.Response
+	Err      error
+	Skipped  bool
+}
+
+func (r *Result) Passed() bool {
+	return !r.Skipped && r.Err == nil && r.Response.StatusCode < http.StatusBadRequest
+}
+
+type Runner struct {
+	db     *storage.DB
+	client *proxy.Client
+}
+
+func New(db *storage.DB, client *proxy.Client) *Runner {
+	return &Runner{db: db, client: client}
+}
+
+func (r *Runner) Select(filter *models.RequestFilter) ([]models.Request, error) {
+	requests, err := r.db.FilterRequests(filter)
+	if err != nil {
+		return nil, err
+	}
+	tree, err := r.db.GetTree()
+	if err != nil {
+		return nil, err
+	}
+	selected := make(map[int]models.Request, len(requests))
+	for _, request := range requests {
+		selected[request.ID] = request
+	}
+	ordered := make([]models.Request, 0, len(requests))
+	collect := func(requests []models.Request) {
+		for _, request := range requests {
+			if match, ok := selected[request.ID]; ok {
+				ordered = append(ordered, match)
+			}
+		}
+	}
+	var walk func(folders []models.TreeFolder)
+	walk = func(folders []models.TreeFolder) {
+		for _, folder := range folders {
+			collect(folder.Requests)
+			walk(folder.Folders)
+		}
+	}
+	walk(tree.Folders)
+	collect(tree.Requests)
+	return ordered, nil
+}
+
+func (r *Runner) Run(ctx context.Context, requests []models.Request, report func(Result)) []Result {
+	results := make([]Result, 0, len(requests))
+	for _, request := range requests {
+		if ctx.Err() != nil {
+			break
+		}
+		result := r.execute(ctx, request)
+		if report != nil {
+			report(result)
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+func (r *Runner) execute(ctx context.Context, request models.Request) Result {
+	result := Result{Request: request}
+	if request.Type == models.RequestTypeWebSocket || request.Type == models.RequestTypeGRPC {
+		result.Skipped = true
+		return result
+	}
+	var folders []models.Folder
+	if request.FolderID != nil {
+		folders, result.Err = r.db.GetFolderChain(*request.FolderID)
+		if result.Err != nil {
+			return result
+		}
+	}
+	effective := proxy.ResolveRequest(&request, folders)
+	result.Request.URL = effective.URL
+	result.Response, result.Err = r.client.ExecuteEffectiveContext(ctx, effective)
+	return result
+}
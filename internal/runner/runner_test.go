@@ -0,0 +1,129 @@
+package runner
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/hc/hc/internal/models"
+	"github.com/hc/hc/internal/proxy"
+	"github.com/hc/hc/internal/storage"
+)
+
+func setupTestDB(t *testing.T) *storage.DB {
+	t.Helper()
+	t.Setenv("HC_TEST_DB_PATH", filepath.Join(t.TempDir(), "test.db"))
+	db, err := storage.InitDB()
+	if err != nil {
+		t.Fatalf("InitDB() error = %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestSelect(t *testing.T) {
+	db := setupTestDB(t)
+	folder := &models.Folder{Name: "API"}
+	if err := db.CreateFolder(folder); err != nil {
+		t.Fatalf("CreateFolder() error = %v", err)
+	}
+	requests := []*models.Request{
+		{Name: "Root smoke", Method: "GET", URL: "https://example.com/a", Tags: []string{"smoke"}},
+		{Name: "Folder smoke", Method: "GET", URL: "https://example.com/b", FolderID: &folder.ID, Tags: []string{"smoke", "prod-only"}},
+		{Name: "Folder flaky", Method: "GET", URL: "https://example.com/c", FolderID: &folder.ID, Tags: []string{"flaky"}, Favorite: true},
+	}
+	for _, request := range requests {
+		if err := db.CreateRequest(request); err != nil {
+			t.Fatalf("CreateRequest() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		filter models.RequestFilter
+		want   []string
+	}{
+		{name: "By tag in collection order", filter: models.RequestFilter{Tags: []string{"smoke"}}, want: []string{"Folder smoke", "Root smoke"}},
+		{name: "By all tags", filter: models.RequestFilter{Tags: []string{"SMOKE", "prod-only"}}, want: []string{"Folder smoke"}},
+		{name: "By favorite", filter: models.RequestFilter{Favorite: true}, want: []string{"Folder flaky"}},
+		{name: "By folder", filter: models.RequestFilter{FolderID: &folder.ID}, want: []string{"Folder smoke", "Folder flaky"}},
+		{name: "No match", filter: models.RequestFilter{Tags: []string{"missing"}}, want: []string{}},
+	}
+
+	r := New(db, proxy.NewClient())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selected, err := r.Select(&tt.filter)
+			if err != nil {
+				t.Fatalf("Select() error = %v", err)
+			}
+			if len(selected) != len(tt.want) {
+				t.Fatalf("Expected %d requests, got %d", len(tt.want), len(selected))
+			}
+			for i, name := range tt.want {
+				if selected[i].Name != name {
+					t.Errorf("Expected request %d to be %q, got %q", i, name, selected[i].Name)
+				}
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Env") != "test" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	db := setupTestDB(t)
+	folder := &models.Folder{Name: "API", BaseURL: ts.URL, Headers: map[string]string{"X-Env": "test"}}
+	if err := db.CreateFolder(folder); err != nil {
+		t.Fatalf("CreateFolder() error = %v", err)
+	}
+	requests := []models.Request{
+		{Name: "OK", Type: models.RequestTypeHTTP, Method: "GET", URL: "/ok", FolderID: &folder.ID},
+		{Name: "Missing", Type: models.RequestTypeHTTP, Method: "GET", URL: "/missing", FolderID: &folder.ID},
+		{Name: "Socket", Type: models.RequestTypeWebSocket, URL: "ws://localhost"},
+	}
+
+	var reported int
+	results := New(db, proxy.NewClient()).Run(context.Background(), requests, func(Result) { reported++ })
+	if reported != len(requests) || len(results) != len(requests) {
+		t.Fatalf("Expected %d reported results, got %d and %d", len(requests), reported, len(results))
+	}
+
+	tests := []struct {
+		name        string
+		wantPassed  bool
+		wantSkipped bool
+		wantStatus  int
+	}{
+		{name: "OK", wantPassed: true, wantStatus: http.StatusOK},
+		{name: "Missing", wantStatus: http.StatusNotFound},
+		{name: "Socket", wantSkipped: true},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := results[i]
+			if result.Err != nil {
+				t.Fatalf("Unexpected error: %v", result.Err)
+			}
+			if result.Passed() != tt.wantPassed || result.Skipped != tt.wantSkipped {
+				t.Errorf("Expected passed=%v skipped=%v, got passed=%v skipped=%v", tt.wantPassed, tt.wantSkipped, result.Passed(), result.Skipped)
+			}
+			if tt.wantStatus != 0 && result.Response.StatusCode != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, result.Response.StatusCode)
+			}
+		})
+	}
+}
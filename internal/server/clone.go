@@ -0,0 +1,41 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/hc/hc/internal/models"
+	"github.com/labstack/echo/v4"
+)
+
+func (s *Server) handleCloneRequest(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request ID"))
+	}
+	var request models.Request
+	if err := s.db.GetRequest(id, &request); err != nil {
+		return c.JSON(http.StatusNotFound, models.NewErrorResponse("Request not found"))
+	}
+	clone, err := s.db.CloneRequest(c.Request().Context(), id)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to clone request"))
+	}
+	return c.JSON(http.StatusCreated, clone)
+}
+
+func (s *Server) handleCloneFolder(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid folder ID"))
+	}
+	var folder models.Folder
+	if err := s.db.GetFolder(id, &folder); err != nil {
+		return c.JSON(http.StatusNotFound, models.NewErrorResponse("Folder not found"))
+	}
+	clone, err := s.db.CloneFolder(c.Request().Context(), id)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to clone folder"))
+	}
+	return c.JSON(http.StatusCreated, clone)
+}
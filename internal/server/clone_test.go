@@ -0,0 +1,65 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/hc/hc/internal/models"
+	"github.com/labstack/echo/v4"
+)
+
+func TestHandleClone(t *testing.T) {
+	server, db := setupTestServer(t)
+	e := echo.New()
+
+	folder := &models.Folder{Name: "Folder"}
+	if err := db.CreateFolder(folder); err != nil {
+		t.Fatalf("CreateFolder() error = %v", err)
+	}
+	request := &models.Request{Name: "Request", Method: "GET", URL: "https://example.com", FolderID: &folder.ID}
+	if err := db.CreateRequest(request); err != nil {
+		t.Fatalf("CreateRequest() error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		handler    func(echo.Context) error
+		id         string
+		wantStatus int
+		wantName   string
+	}{
+		{name: "Clone request", handler: server.handleCloneRequest, id: strconv.Itoa(request.ID), wantStatus: http.StatusCreated, wantName: "Request (copy)"},
+		{name: "Clone folder", handler: server.handleCloneFolder, id: strconv.Itoa(folder.ID), wantStatus: http.StatusCreated, wantName: "Folder (copy)"},
+		{name: "Clone missing request", handler: server.handleCloneRequest, id: "999", wantStatus: http.StatusNotFound},
+		{name: "Clone missing folder", handler: server.handleCloneFolder, id: "999", wantStatus: http.StatusNotFound},
+		{name: "Invalid folder ID", handler: server.handleCloneFolder, id: "abc", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			c := e.NewContext(httptest.NewRequest("POST", "/", nil), rec)
+			c.SetParamNames("id")
+			c.SetParamValues(tt.id)
+			if err := tt.handler(c); err != nil {
+				t.Fatalf("handler error = %v", err)
+			}
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			if tt.wantName == "" {
+				return
+			}
+			var created struct {
+				Name string `json:"name"`
+			}
+			json.Unmarshal(rec.Body.Bytes(), &created)
+			if created.Name != tt.wantName {
+				t.Errorf("Expected name %q, got %q", tt.wantName, created.Name)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package server
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+
+	"github.com/hc/hc/internal/docs"
+	"github.com/hc/hc/internal/models"
+	"github.com/labstack/echo/v4"
+)
+
+func (s *Server) handleGetDocs(c echo.Context) error {
+	var folderID *int
+	if value := c.QueryParam("folder_id"); value != "" {
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid folder ID"))
+		}
+		var folder models.Folder
+		if err := s.db.GetFolder(id, &folder); err != nil {
+			return c.JSON(http.StatusNotFound, models.NewErrorResponse("Folder not found"))
+		}
+		folderID = &id
+	}
+	var buf bytes.Buffer
+	if err := docs.Generate(s.db, folderID, &buf); err != nil {
+		return c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to generate documentation"))
+	}
+	return c.HTMLBlob(http.StatusOK, buf.Bytes())
+}
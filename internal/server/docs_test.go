@@ -0,0 +1,62 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hc/hc/internal/models"
+	"github.com/labstack/echo/v4"
+)
+
+func TestHandleGetDocs(t *testing.T) {
+	server, db := setupTestServer(t)
+	e := echo.New()
+
+	folder := &models.Folder{Name: "Users", Description: "User management"}
+	if err := db.CreateFolder(folder); err != nil {
+		t.Fatalf("CreateFolder() error = %v", err)
+	}
+	request := &models.Request{Name: "List users", FolderID: &folder.ID, Method: "GET", URL: "https://example.com/users"}
+	if err := db.CreateRequest(request); err != nil {
+		t.Fatalf("CreateRequest() error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "Whole collection", query: "", wantStatus: http.StatusOK, wantBody: "<title>API Reference</title>"},
+		{name: "Single folder", query: "?folder_id=" + strconv.Itoa(folder.ID), wantStatus: http.StatusOK, wantBody: "<title>Users</title>"},
+		{name: "Invalid folder ID", query: "?folder_id=abc", wantStatus: http.StatusBadRequest},
+		{name: "Missing folder", query: "?folder_id=999", wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			if err := server.handleGetDocs(e.NewContext(httptest.NewRequest("GET", "/api/docs"+tt.query, nil), rec)); err != nil {
+				t.Fatalf("handleGetDocs() error = %v", err)
+			}
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			if contentType := rec.Header().Get(echo.HeaderContentType); !strings.HasPrefix(contentType, echo.MIMETextHTML) {
+				t.Errorf("Expected HTML content type, got %s", contentType)
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Errorf("Expected body to contain %q", tt.wantBody)
+			}
+			if !strings.Contains(rec.Body.String(), "List users") {
+				t.Error("Expected body to contain the request")
+			}
+		})
+	}
+}
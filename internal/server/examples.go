@@ -0,0 +1,131 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/hc/hc/internal/models"
+	"github.com/labstack/echo/v4"
+)
+
+func validateExamples(examples []models.ExampleResponse) error {
+	for _, example := range examples {
+		if err := validateExample(&example); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateExample(example *models.ExampleResponse) error {
+	if strings.TrimSpace(example.Name) == "" {
+		return errors.New("example name is required")
+	}
+	if example.StatusCode < 100 || example.StatusCode > 599 {
+		return errors.New("example status code must be between 100 and 599")
+	}
+	return nil
+}
+
+func (s *Server) handleGetExamples(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request ID"))
+	}
+	var request models.Request
+	if err := s.db.GetRequest(id, &request); err != nil {
+		return c.JSON(http.StatusNotFound, models.NewErrorResponse("Request not found"))
+	}
+	return c.JSON(http.StatusOK, request.Examples)
+}
+
+func (s *Server) handleCreateExample(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request ID"))
+	}
+	var example models.ExampleResponse
+	if err := c.Bind(&example); err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body"))
+	}
+	var request models.Request
+	if err := s.db.GetRequest(id, &request); err != nil {
+		return c.JSON(http.StatusNotFound, models.NewErrorResponse("Request not found"))
+	}
+	if example.HistoryID != nil {
+		var entry models.HistoryEntry
+		if err := s.db.GetHistoryEntry(*example.HistoryID, &entry); err != nil {
+			return c.JSON(http.StatusBadRequest, models.NewErrorResponse("History entry not found"))
+		}
+		if example.StatusCode == 0 {
+			example.StatusCode = entry.StatusCode
+		}
+		if example.Headers == nil {
+			example.Headers = entry.ResponseHeaders
+		}
+		if example.Body == "" {
+			example.Body = entry.ResponseBody
+		}
+	}
+	if strings.TrimSpace(example.Name) == "" && example.StatusCode != 0 {
+		example.Name = strings.TrimSpace(fmt.Sprintf("%d %s", example.StatusCode, http.StatusText(example.StatusCode)))
+	}
+	if err := validateExample(&example); err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error()))
+	}
+	example.RequestID = id
+	if err := s.db.CreateExample(&example); err != nil {
+		return c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to create example"))
+	}
+	return c.JSON(http.StatusCreated, example)
+}
+
+func (s *Server) handleUpdateExample(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request ID"))
+	}
+	exampleID, err := strconv.Atoi(c.Param("example"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid example ID"))
+	}
+	var existing models.ExampleResponse
+	if err := s.db.GetExample(id, exampleID, &existing); err != nil {
+		return c.JSON(http.StatusNotFound, models.NewErrorResponse("Example not found"))
+	}
+	var example models.ExampleResponse
+	if err := c.Bind(&example); err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body"))
+	}
+	if err := validateExample(&example); err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error()))
+	}
+	example.RequestID = id
+	example.ID = exampleID
+	if err := s.db.UpdateExample(&example); err != nil {
+		return c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to update example"))
+	}
+	return c.JSON(http.StatusOK, example)
+}
+
+func (s *Server) handleDeleteExample(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request ID"))
+	}
+	exampleID, err := strconv.Atoi(c.Param("example"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid example ID"))
+	}
+	var example models.ExampleResponse
+	if err := s.db.GetExample(id, exampleID, &example); err != nil {
+		return c.JSON(http.StatusNotFound, models.NewErrorResponse("Example not found"))
+	}
+	if err := s.db.DeleteExample(id, exampleID); err != nil {
+		return c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to delete example"))
+	}
+	return c.NoContent(http.StatusNoContent)
+}
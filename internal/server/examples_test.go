@@ -0,0 +1,149 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hc/hc/internal/models"
+	"github.com/labstack/echo/v4"
+)
+
+func TestHandleExamples(t *testing.T) {
+	server, db := setupTestServer(t)
+	e := echo.New()
+
+	request := &models.Request{Name: "Get user", Method: "GET", URL: "https://example.com/users/1"}
+	if err := db.CreateRequest(request); err != nil {
+		t.Fatalf("CreateRequest() error = %v", err)
+	}
+	entry := &models.HistoryEntry{RequestID: &request.ID, Method: "GET", URL: request.URL, StatusCode: 404, ResponseHeaders: map[string]string{"Content-Type": "application/json"}, ResponseBody: `{"error": "not found"}`}
+	if err := db.CreateHistoryEntry(entry); err != nil {
+		t.Fatalf("CreateHistoryEntry() error = %v", err)
+	}
+	id := strconv.Itoa(request.ID)
+
+	createTests := []struct {
+		name       string
+		id         string
+		body       string
+		wantStatus int
+		want       models.ExampleResponse
+	}{
+		{name: "Hand-written", id: id, body: `{"name": "Found", "status_code": 200, "body": "{\"id\": 1}"}`, wantStatus: http.StatusCreated, want: models.ExampleResponse{Name: "Found", StatusCode: 200, Body: `{"id": 1}`}},
+		{name: "From history", id: id, body: `{"history_id": ` + strconv.Itoa(entry.ID) + `}`, wantStatus: http.StatusCreated, want: models.ExampleResponse{Name: "404 Not Found", StatusCode: 404, Body: `{"error": "not found"}`}},
+		{name: "Missing history entry", id: id, body: `{"history_id": 999}`, wantStatus: http.StatusBadRequest},
+		{name: "Invalid status", id: id, body: `{"name": "Broken", "status_code": 42}`, wantStatus: http.StatusBadRequest},
+		{name: "Missing request", id: "999", body: `{"name": "Found", "status_code": 200}`, wantStatus: http.StatusNotFound},
+		{name: "Invalid request ID", id: "abc", body: `{}`, wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range createTests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/", strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("id")
+			c.SetParamValues(tt.id)
+			if err := server.handleCreateExample(c); err != nil {
+				t.Fatalf("handleCreateExample() error = %v", err)
+			}
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusCreated {
+				return
+			}
+			var got models.ExampleResponse
+			json.Unmarshal(rec.Body.Bytes(), &got)
+			if got.Name != tt.want.Name || got.StatusCode != tt.want.StatusCode || got.Body != tt.want.Body || got.RequestID != request.ID {
+				t.Errorf("Expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+
+	examples, err := db.GetExamples(request.ID)
+	if err != nil || len(examples) != 2 {
+		t.Fatalf("Expected 2 examples, got %d, %v", len(examples), err)
+	}
+	exampleID := strconv.Itoa(examples[0].ID)
+
+	t.Run("List examples", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest("GET", "/", nil), rec)
+		c.SetParamNames("id")
+		c.SetParamValues(id)
+		if err := server.handleGetExamples(c); err != nil {
+			t.Fatalf("handleGetExamples() error = %v", err)
+		}
+		var got []models.ExampleResponse
+		json.Unmarshal(rec.Body.Bytes(), &got)
+		if rec.Code != http.StatusOK || len(got) != 2 {
+			t.Errorf("Expected 2 examples, got %d: %s", rec.Code, rec.Body.String())
+		}
+	})
+
+	updateTests := []struct {
+		name       string
+		exampleID  string
+		body       string
+		wantStatus int
+	}{
+		{name: "Update example", exampleID: exampleID, body: `{"name": "Created", "status_code": 201}`, wantStatus: http.StatusOK},
+		{name: "Invalid example", exampleID: exampleID, body: `{"name": "", "status_code": 201}`, wantStatus: http.StatusBadRequest},
+		{name: "Missing example", exampleID: "999", body: `{"name": "Created", "status_code": 201}`, wantStatus: http.StatusNotFound},
+	}
+	for _, tt := range updateTests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("PUT", "/", strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("id", "example")
+			c.SetParamValues(id, tt.exampleID)
+			if err := server.handleUpdateExample(c); err != nil {
+				t.Fatalf("handleUpdateExample() error = %v", err)
+			}
+			if rec.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+		})
+	}
+
+	deleteTests := []struct {
+		name       string
+		wantStatus int
+	}{
+		{name: "Delete example", wantStatus: http.StatusNoContent},
+		{name: "Delete again", wantStatus: http.StatusNotFound},
+	}
+	for _, tt := range deleteTests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			c := e.NewContext(httptest.NewRequest("DELETE", "/", nil), rec)
+			c.SetParamNames("id", "example")
+			c.SetParamValues(id, exampleID)
+			if err := server.handleDeleteExample(c); err != nil {
+				t.Fatalf("handleDeleteExample() error = %v", err)
+			}
+			if rec.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+		})
+	}
+
+	t.Run("Request with invalid example", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/api/requests", strings.NewReader(`{"name": "Bad", "method": "GET", "url": "https://example.com", "examples": [{"name": "", "status_code": 200}]}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		if err := server.handleCreateRequest(e.NewContext(req, rec)); err != nil {
+			t.Fatalf("handleCreateRequest() error = %v", err)
+		}
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d, got %d", http.StatusBadRequest, rec.Code)
+		}
+	})
+}
@@ -0,0 +1,70 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"sync"
+
+	"github.com/hc/hc/internal/models"
+	"github.com/labstack/echo/v4"
+)
+
+const StatusClientClosedRequest = 499
+
+type executionRegistry struct {
+	mu      sync.Mutex
+	cancels map[string]context.CancelFunc
+}
+
+func newExecutionRegistry() *executionRegistry {
+	return &executionRegistry{
+		cancels: make(map[string]context.CancelFunc),
+	}
+}
+
+func (r *executionRegistry) start(parent context.Context, id string) (context.Context, string, func(), error) {
+	if id == "" {
+		id = newExecutionID()
+	}
+	ctx, cancel := context.WithCancel(parent)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.cancels[id]; exists {
+		cancel()
+		return nil, "", nil, errors.New("execution already running: " + id)
+	}
+	r.cancels[id] = cancel
+	return ctx, id, func() {
+		r.mu.Lock()
+		delete(r.cancels, id)
+		r.mu.Unlock()
+		cancel()
+	}, nil
+}
+
+func (r *executionRegistry) cancel(id string) bool {
+	r.mu.Lock()
+	cancel, ok := r.cancels[id]
+	r.mu.Unlock()
+	if ok {
+		cancel()
+	}
+	return ok
+}
+
+func newExecutionID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func (s *Server) handleCancelExecution(c echo.Context) error {
+	id := c.Param("id")
+	if !s.executions.cancel(id) {
+		return c.JSON(http.StatusNotFound, models.NewErrorResponse("Execution not found"))
+	}
+	return c.NoContent(http.StatusNoContent)
+}
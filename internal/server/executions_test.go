@@ -0,0 +1,163 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hc/hc/internal/models"
+	"github.com/labstack/echo/v4"
+)
+
+func TestExecutionRegistry(t *testing.T) {
+	registry := newExecutionRegistry()
+
+	ctx, id, done, err := registry.start(context.Background(), "")
+	if err != nil {
+		t.Fatalf("start() error = %v", err)
+	}
+	if len(id) != 32 {
+		t.Errorf("Expected generated 32 character ID, got %q", id)
+	}
+
+	if _, _, _, err := registry.start(context.Background(), id); err == nil {
+		t.Error("Expected error when reusing a running execution ID")
+	}
+
+	if !registry.cancel(id) {
+		t.Error("Expected cancel() to find the execution")
+	}
+	if ctx.Err() != context.Canceled {
+		t.Errorf("Expected context to be cancelled, got %v", ctx.Err())
+	}
+
+	done()
+	if registry.cancel(id) {
+		t.Error("Expected finished execution to be removed")
+	}
+}
+
+func TestCancelExecution(t *testing.T) {
+	server, _ := setupTestServer(t)
+	e := echo.New()
+
+	// Backend that hangs until the client goes away
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer backend.Close()
+
+	reqBody := `{"execution_id": "exec-1", "method": "GET", "url": "` + backend.URL + `"}`
+	req := httptest.NewRequest("POST", "/api/request", strings.NewReader(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	finished := make(chan error, 1)
+	go func() {
+		finished <- server.handleProxyRequest(c)
+	}()
+
+	// Wait for the execution to be registered, then cancel it
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		cancelReq := httptest.NewRequest("DELETE", "/api/executions/exec-1", nil)
+		cancelRec := httptest.NewRecorder()
+		cancelCtx := e.NewContext(cancelReq, cancelRec)
+		cancelCtx.SetParamNames("id")
+		cancelCtx.SetParamValues("exec-1")
+		if err := server.handleCancelExecution(cancelCtx); err != nil {
+			t.Fatalf("handleCancelExecution() error = %v", err)
+		}
+		if cancelRec.Code == http.StatusNoContent {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Execution was never registered, last status %d", cancelRec.Code)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	select {
+	case err := <-finished:
+		if err != nil {
+			t.Fatalf("handleProxyRequest() error = %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Request was not cancelled")
+	}
+
+	if rec.Code != StatusClientClosedRequest {
+		t.Errorf("Expected status %d, got %d", StatusClientClosedRequest, rec.Code)
+	}
+	var errResp models.ErrorResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &errResp); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if len(errResp.Messages) == 0 || errResp.Messages[0] != "Request cancelled" {
+		t.Errorf("Expected 'Request cancelled', got %v", errResp.Messages)
+	}
+	if rec.Header().Get("X-Execution-ID") != "exec-1" {
+		t.Errorf("Expected X-Execution-ID header, got %q", rec.Header().Get("X-Execution-ID"))
+	}
+}
+
+func TestCancelUnknownExecution(t *testing.T) {
+	server, _ := setupTestServer(t)
+	e := echo.New()
+
+	req := httptest.NewRequest("DELETE", "/api/executions/missing", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("missing")
+
+	if err := server.handleCancelExecution(c); err != nil {
+		t.Fatalf("handleCancelExecution() error = %v", err)
+	}
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d, got %d", http.StatusNotFound, rec.Code)
+	}
+}
+
+func TestHandleProxyRequestErrorClassification(t *testing.T) {
+	server, _ := setupTestServer(t)
+	e := echo.New()
+
+	// Find a port with nothing listening on it
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	reqBody := `{"method": "GET", "url": "http://` + addr + `"}`
+	req := httptest.NewRequest("POST", "/api/request", strings.NewReader(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if err := server.handleProxyRequest(c); err != nil {
+		t.Fatalf("handleProxyRequest() error = %v", err)
+	}
+
+	if rec.Code != http.StatusBadGateway {
+		t.Errorf("Expected status %d, got %d", http.StatusBadGateway, rec.Code)
+	}
+	var errResp models.ErrorResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &errResp); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if errResp.Code != "connection_refused" {
+		t.Errorf("Expected code connection_refused, got %q", errResp.Code)
+	}
+	if errResp.Detail == "" || errResp.Timing == nil {
+		t.Errorf("Expected detail and timing, got %+v", errResp)
+	}
+}
@@ -0,0 +1,126 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/hc/hc/internal/graphql"
+	"github.com/hc/hc/internal/logger"
+	"github.com/hc/hc/internal/models"
+	"github.com/hc/hc/internal/proxy"
+	"github.com/labstack/echo/v4"
+)
+
+type graphQLSchemaRequest struct {
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers"`
+	Refresh bool              `json:"refresh"`
+}
+
+type graphQLSchemaResponse struct {
+	URL       string          `json:"url"`
+	FetchedAt time.Time       `json:"fetched_at"`
+	Cached    bool            `json:"cached"`
+	Schema    *graphql.Schema `json:"schema"`
+}
+
+type graphQLValidateRequest struct {
+	URL   string `json:"url"`
+	Query string `json:"query"`
+}
+
+type graphQLValidateResponse struct {
+	Valid        bool                      `json:"valid"`
+	SchemaLoaded bool                      `json:"schema_loaded"`
+	Errors       []graphql.ValidationError `json:"errors"`
+}
+
+func (s *Server) handleIntrospectGraphQL(c echo.Context) error {
+	var schemaReq graphQLSchemaRequest
+	if err := c.Bind(&schemaReq); err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body"))
+	}
+	if err := proxy.ValidateURL(schemaReq.URL); err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error()))
+	}
+	var cached models.GraphQLSchema
+	if !schemaReq.Refresh && s.db.GetGraphQLSchema(schemaReq.URL, &cached) == nil {
+		return s.graphQLSchemaResponse(c, &cached, true)
+	}
+	logger.Get().Info("Introspecting GraphQL schema", slog.String("url", schemaReq.URL))
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 30*time.Second)
+	defer cancel()
+	raw, err := graphql.Introspect(ctx, s.proxyClient, schemaReq.URL, schemaReq.Headers)
+	if err != nil {
+		logger.Get().Error("GraphQL introspection failed", slog.String("url", schemaReq.URL), slog.String("error", err.Error()))
+		var reqErr *proxy.RequestError
+		if errors.As(err, &reqErr) {
+			return c.JSON(executionErrorResponse(err))
+		}
+		return c.JSON(http.StatusBadGateway, models.NewErrorResponse(err.Error()))
+	}
+	schema := models.GraphQLSchema{URL: schemaReq.URL, Schema: raw}
+	if err := s.db.SaveGraphQLSchema(&schema); err != nil {
+		return c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to save GraphQL schema"))
+	}
+	return s.graphQLSchemaResponse(c, &schema, false)
+}
+
+func (s *Server) handleGetGraphQLSchema(c echo.Context) error {
+	url := c.QueryParam("url")
+	if url == "" {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse("URL is required"))
+	}
+	var schema models.GraphQLSchema
+	if err := s.db.GetGraphQLSchema(url, &schema); err != nil {
+		return c.JSON(http.StatusNotFound, models.NewErrorResponse("GraphQL schema not found"))
+	}
+	return s.graphQLSchemaResponse(c, &schema, true)
+}
+
+func (s *Server) graphQLSchemaResponse(c echo.Context, schema *models.GraphQLSchema, cached bool) error {
+	summary, err := graphql.Summarize(schema.Schema)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to read GraphQL schema"))
+	}
+	return c.JSON(http.StatusOK, graphQLSchemaResponse{
+		URL:       schema.URL,
+		FetchedAt: schema.FetchedAt,
+		Cached:    cached,
+		Schema:    summary,
+	})
+}
+
+func (s *Server) handleValidateGraphQL(c echo.Context) error {
+	var validateReq graphQLValidateRequest
+	if err := c.Bind(&validateReq); err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body"))
+	}
+	if validateReq.Query == "" {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse("GraphQL query is required"))
+	}
+	var (
+		cached models.GraphQLSchema
+		resp   graphQLValidateResponse
+	)
+	if validateReq.URL != "" && s.db.GetGraphQLSchema(validateReq.URL, &cached) == nil {
+		schema, err := graphql.LoadSchema(cached.Schema)
+		if err != nil {
+			logger.Get().Warn("Failed to load cached GraphQL schema", slog.String("url", validateReq.URL), slog.String("error", err.Error()))
+		} else {
+			resp.SchemaLoaded = true
+			resp.Errors = graphql.Validate(schema, validateReq.Query)
+		}
+	}
+	if !resp.SchemaLoaded {
+		resp.Errors = graphql.Validate(nil, validateReq.Query)
+	}
+	if resp.Errors == nil {
+		resp.Errors = []graphql.ValidationError{}
+	}
+	resp.Valid = len(resp.Errors) == 0
+	return c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,151 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+const testGraphQLSchema = `{
+  "queryType": {"name": "Query"},
+  "types": [
+    {"kind": "OBJECT", "name": "Query", "fields": [
+      {"name": "hello", "args": [], "type": {"kind": "SCALAR", "name": "String", "ofType": null}, "isDeprecated": false}
+    ], "interfaces": []},
+    {"kind": "SCALAR", "name": "String"}
+  ],
+  "directives": []
+}`
+
+func TestGraphQLHandlers(t *testing.T) {
+	server, _ := setupTestServer(t)
+	e := echo.New()
+
+	var introspections atomic.Int32
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		introspections.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": {"__schema": ` + testGraphQLSchema + `}}`))
+	}))
+	defer target.Close()
+
+	introspect := func(t *testing.T, body string) (*httptest.ResponseRecorder, graphQLSchemaResponse) {
+		t.Helper()
+		req := httptest.NewRequest("POST", "/api/graphql/schema", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		if err := server.handleIntrospectGraphQL(e.NewContext(req, rec)); err != nil {
+			t.Fatalf("handleIntrospectGraphQL() error = %v", err)
+		}
+		var resp graphQLSchemaResponse
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+		return rec, resp
+	}
+
+	validate := func(t *testing.T, body string) graphQLValidateResponse {
+		t.Helper()
+		req := httptest.NewRequest("POST", "/api/graphql/validate", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		if err := server.handleValidateGraphQL(e.NewContext(req, rec)); err != nil {
+			t.Fatalf("handleValidateGraphQL() error = %v", err)
+		}
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+		}
+		var resp graphQLValidateResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+		return resp
+	}
+
+	t.Run("ValidateWithoutSchema", func(t *testing.T) {
+		resp := validate(t, `{"url": "`+target.URL+`", "query": "{ missing }"}`)
+		if resp.SchemaLoaded || !resp.Valid {
+			t.Errorf("Expected syntax-only validation to pass, got %+v", resp)
+		}
+	})
+
+	t.Run("GetSchemaNotCached", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/graphql/schema?url="+target.URL, nil)
+		rec := httptest.NewRecorder()
+		if err := server.handleGetGraphQLSchema(e.NewContext(req, rec)); err != nil {
+			t.Fatalf("handleGetGraphQLSchema() error = %v", err)
+		}
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("Expected status %d, got %d", http.StatusNotFound, rec.Code)
+		}
+	})
+
+	t.Run("Introspect", func(t *testing.T) {
+		rec, resp := introspect(t, `{"url": "`+target.URL+`"}`)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+		}
+		if resp.Cached || resp.Schema == nil || resp.Schema.QueryType != "Query" {
+			t.Errorf("Unexpected schema response %+v", resp)
+		}
+
+		rec, resp = introspect(t, `{"url": "`+target.URL+`"}`)
+		if rec.Code != http.StatusOK || !resp.Cached {
+			t.Errorf("Expected cached schema, got %d %+v", rec.Code, resp)
+		}
+		if introspections.Load() != 1 {
+			t.Errorf("Expected 1 introspection request, got %d", introspections.Load())
+		}
+
+		_, resp = introspect(t, `{"url": "`+target.URL+`", "refresh": true}`)
+		if resp.Cached || introspections.Load() != 2 {
+			t.Errorf("Expected refresh to re-run introspection, got %+v", resp)
+		}
+	})
+
+	t.Run("GetSchemaCached", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/graphql/schema?url="+target.URL, nil)
+		rec := httptest.NewRecorder()
+		if err := server.handleGetGraphQLSchema(e.NewContext(req, rec)); err != nil {
+			t.Fatalf("handleGetGraphQLSchema() error = %v", err)
+		}
+		if rec.Code != http.StatusOK {
+			t.Errorf("Expected status %d, got %d", http.StatusOK, rec.Code)
+		}
+	})
+
+	t.Run("ValidateWithSchema", func(t *testing.T) {
+		resp := validate(t, `{"url": "`+target.URL+`", "query": "{ hello }"}`)
+		if !resp.SchemaLoaded || !resp.Valid {
+			t.Errorf("Expected valid query, got %+v", resp)
+		}
+
+		resp = validate(t, `{"url": "`+target.URL+`", "query": "{ missing }"}`)
+		if resp.Valid || len(resp.Errors) != 1 {
+			t.Fatalf("Expected one validation error, got %+v", resp)
+		}
+		if resp.Errors[0].Line != 1 {
+			t.Errorf("Expected error location, got %+v", resp.Errors[0])
+		}
+	})
+
+	t.Run("IntrospectInvalidURL", func(t *testing.T) {
+		rec, _ := introspect(t, `{"url": "ftp://example.com"}`)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d, got %d", http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("IntrospectConnectionRefused", func(t *testing.T) {
+		closed := httptest.NewServer(http.NotFoundHandler())
+		url := closed.URL
+		closed.Close()
+		rec, _ := introspect(t, `{"url": "`+url+`"}`)
+		if rec.Code != http.StatusBadGateway {
+			t.Errorf("Expected status %d, got %d", http.StatusBadGateway, rec.Code)
+		}
+	})
+}
@@ -0,0 +1,155 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hc/hc/internal/grpcclient"
+	"github.com/hc/hc/internal/logger"
+	"github.com/hc/hc/internal/models"
+	"github.com/hc/hc/internal/proxy"
+	"github.com/labstack/echo/v4"
+	"google.golang.org/grpc"
+)
+
+type grpcInvokeRequest struct {
+	ExecutionID string `json:"execution_id,omitempty"`
+	models.GRPCRequest
+}
+
+func (s *Server) handleGetGRPCProtoSets(c echo.Context) error {
+	protoSets, err := s.db.GetGRPCProtoSets()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to get proto sets"))
+	}
+	if protoSets == nil {
+		protoSets = []models.GRPCProtoSet{}
+	}
+	return c.JSON(http.StatusOK, protoSets)
+}
+
+func (s *Server) handleCreateGRPCProtoSet(c echo.Context) error {
+	var protoSet models.GRPCProtoSet
+	if err := c.Bind(&protoSet); err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body"))
+	}
+	if protoSet.Name == "" {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse("Name is required"))
+	}
+	if _, err := grpcclient.CompileProtos(c.Request().Context(), protoSet.Files); err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error()))
+	}
+	if err := s.db.CreateGRPCProtoSet(&protoSet); err != nil {
+		return c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to create proto set"))
+	}
+	return c.JSON(http.StatusCreated, protoSet)
+}
+
+func (s *Server) handleGetGRPCProtoSetByID(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid proto set ID"))
+	}
+	var protoSet models.GRPCProtoSet
+	if err := s.db.GetGRPCProtoSet(id, &protoSet); err != nil {
+		return c.JSON(http.StatusNotFound, models.NewErrorResponse("Proto set not found"))
+	}
+	return c.JSON(http.StatusOK, protoSet)
+}
+
+func (s *Server) handleDeleteGRPCProtoSet(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid proto set ID"))
+	}
+	if err := s.db.DeleteGRPCProtoSet(id); err != nil {
+		return c.JSON(http.StatusNotFound, models.NewErrorResponse("Proto set not found"))
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (s *Server) handleGetGRPCServices(c echo.Context) error {
+	var grpcReq models.GRPCRequest
+	if err := c.Bind(&grpcReq); err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body"))
+	}
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 30*time.Second)
+	defer cancel()
+	var conn *grpc.ClientConn
+	if grpcReq.ProtoSetID == nil {
+		if err := grpcclient.ValidateTarget(grpcReq.Target); err != nil {
+			return c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error()))
+		}
+		var err error
+		conn, err = grpcclient.Dial(&grpcReq, proxy.TunnelDialer(s.proxyClient.Transport()))
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error()))
+		}
+		defer conn.Close()
+	}
+	descriptors, status, err := s.grpcDescriptors(ctx, conn, &grpcReq)
+	if err != nil {
+		return c.JSON(status, models.NewErrorResponse(err.Error()))
+	}
+	return c.JSON(http.StatusOK, descriptors.Services())
+}
+
+func (s *Server) handleInvokeGRPC(c echo.Context) error {
+	var invokeReq grpcInvokeRequest
+	if err := c.Bind(&invokeReq); err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body"))
+	}
+	grpcReq := &invokeReq.GRPCRequest
+	if err := grpcclient.ValidateRequest(grpcReq); err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error()))
+	}
+	ctx, executionID, done, err := s.executions.start(c.Request().Context(), invokeReq.ExecutionID)
+	if err != nil {
+		return c.JSON(http.StatusConflict, models.NewErrorResponse(err.Error()))
+	}
+	defer done()
+	c.Response().Header().Set("X-Execution-ID", executionID)
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	conn, err := grpcclient.Dial(grpcReq, proxy.TunnelDialer(s.proxyClient.Transport()))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error()))
+	}
+	defer conn.Close()
+	descriptors, status, err := s.grpcDescriptors(ctx, conn, grpcReq)
+	if err != nil {
+		return c.JSON(status, models.NewErrorResponse(err.Error()))
+	}
+	logger.Get().Info("Invoking gRPC method",
+		slog.String("execution_id", executionID),
+		slog.String("target", grpcReq.Target),
+		slog.String("method", grpcReq.Method))
+	resp, err := grpcclient.Invoke(ctx, conn, descriptors, grpcReq)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error()))
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (s *Server) grpcDescriptors(ctx context.Context, conn *grpc.ClientConn, grpcReq *models.GRPCRequest) (*grpcclient.Descriptors, int, error) {
+	if grpcReq.ProtoSetID != nil {
+		var protoSet models.GRPCProtoSet
+		if err := s.db.GetGRPCProtoSet(*grpcReq.ProtoSetID, &protoSet); err != nil {
+			return nil, http.StatusNotFound, err
+		}
+		descriptors, err := grpcclient.CompileProtos(ctx, protoSet.Files)
+		if err != nil {
+			return nil, http.StatusBadRequest, err
+		}
+		return descriptors, http.StatusOK, nil
+	}
+	descriptors, err := grpcclient.Reflect(ctx, conn)
+	if err != nil {
+		logger.Get().Error("gRPC reflection failed", slog.String("target", grpcReq.Target), slog.String("error", err.Error()))
+		return nil, http.StatusBadGateway, err
+	}
+	return descriptors, http.StatusOK, nil
+}
@@ -0,0 +1,204 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hc/hc/internal/models"
+	"github.com/labstack/echo/v4"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+const testHealthProto = `syntax = "proto3";
+package grpc.health.v1;
+
+message HealthCheckRequest {
+  string service = 1;
+}
+
+message HealthCheckResponse {
+  enum ServingStatus {
+    UNKNOWN = 0;
+    SERVING = 1;
+    NOT_SERVING = 2;
+    SERVICE_UNKNOWN = 3;
+  }
+  ServingStatus status = 1;
+}
+
+service Health {
+  rpc Check(HealthCheckRequest) returns (HealthCheckResponse);
+}
+`
+
+func startTestGRPCServer(t *testing.T, withReflection bool) string {
+	t.Helper()
+	server := grpc.NewServer()
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	if withReflection {
+		reflection.Register(server)
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+	return listener.Addr().String()
+}
+
+func TestGRPCHandlers(t *testing.T) {
+	server, _ := setupTestServer(t)
+	e := echo.New()
+	reflectionTarget := startTestGRPCServer(t, true)
+	plainTarget := startTestGRPCServer(t, false)
+
+	post := func(t *testing.T, path, body string, handler echo.HandlerFunc) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest("POST", path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		if err := handler(e.NewContext(req, rec)); err != nil {
+			t.Fatalf("handler error = %v", err)
+		}
+		return rec
+	}
+
+	t.Run("ServicesViaReflection", func(t *testing.T) {
+		rec := post(t, "/api/grpc/services", `{"target": "`+reflectionTarget+`"}`, server.handleGetGRPCServices)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+		}
+		var services []models.GRPCService
+		if err := json.Unmarshal(rec.Body.Bytes(), &services); err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+		if len(services) != 1 || services[0].Name != "grpc.health.v1.Health" {
+			t.Errorf("Expected health service, got %+v", services)
+		}
+	})
+
+	t.Run("ServicesWithoutReflection", func(t *testing.T) {
+		rec := post(t, "/api/grpc/services", `{"target": "`+plainTarget+`"}`, server.handleGetGRPCServices)
+		if rec.Code != http.StatusBadGateway {
+			t.Errorf("Expected status %d, got %d", http.StatusBadGateway, rec.Code)
+		}
+	})
+
+	t.Run("InvokeViaReflection", func(t *testing.T) {
+		rec := post(t, "/api/grpc/invoke", `{"target": "`+reflectionTarget+`", "method": "grpc.health.v1.Health/Check", "message": "{}"}`, server.handleInvokeGRPC)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+		}
+		if rec.Header().Get("X-Execution-ID") == "" {
+			t.Error("Expected X-Execution-ID header")
+		}
+		var resp models.GRPCResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+		if resp.Status != "OK" || !strings.Contains(resp.Body, "SERVING") {
+			t.Errorf("Unexpected response %+v", resp)
+		}
+	})
+
+	t.Run("InvokeNotFoundStatus", func(t *testing.T) {
+		rec := post(t, "/api/grpc/invoke", `{"target": "`+reflectionTarget+`", "method": "grpc.health.v1.Health/Check", "message": "{\"service\": \"missing\"}"}`, server.handleInvokeGRPC)
+		var resp models.GRPCResponse
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+		if rec.Code != http.StatusOK || resp.Status != "NotFound" || resp.StatusCode != 5 {
+			t.Errorf("Expected NotFound gRPC status, got %d %+v", rec.Code, resp)
+		}
+	})
+
+	var protoSetID int
+	t.Run("CreateProtoSet", func(t *testing.T) {
+		body, _ := json.Marshal(models.GRPCProtoSet{Name: "health", Files: map[string]string{"health.proto": testHealthProto}})
+		rec := post(t, "/api/grpc/protos", string(body), server.handleCreateGRPCProtoSet)
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, rec.Code, rec.Body.String())
+		}
+		var protoSet models.GRPCProtoSet
+		json.Unmarshal(rec.Body.Bytes(), &protoSet)
+		protoSetID = protoSet.ID
+	})
+
+	t.Run("CreateInvalidProtoSet", func(t *testing.T) {
+		rec := post(t, "/api/grpc/protos", `{"name": "broken", "files": {"broken.proto": "syntax = \"proto3\"; message {"}}`, server.handleCreateGRPCProtoSet)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d, got %d", http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("ServicesViaProtoSet", func(t *testing.T) {
+		rec := post(t, "/api/grpc/services", fmt.Sprintf(`{"proto_set_id": %d}`, protoSetID), server.handleGetGRPCServices)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+		}
+	})
+
+	t.Run("InvokeViaProtoSet", func(t *testing.T) {
+		body := fmt.Sprintf(`{"target": "%s", "method": "grpc.health.v1.Health/Check", "proto_set_id": %d}`, plainTarget, protoSetID)
+		rec := post(t, "/api/grpc/invoke", body, server.handleInvokeGRPC)
+		var resp models.GRPCResponse
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+		if rec.Code != http.StatusOK || resp.Status != "OK" {
+			t.Errorf("Expected OK response, got %d %s", rec.Code, rec.Body.String())
+		}
+	})
+
+	t.Run("InvokeUnknownProtoSet", func(t *testing.T) {
+		body := fmt.Sprintf(`{"target": "%s", "method": "grpc.health.v1.Health/Check", "proto_set_id": 999}`, plainTarget)
+		rec := post(t, "/api/grpc/invoke", body, server.handleInvokeGRPC)
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("Expected status %d, got %d", http.StatusNotFound, rec.Code)
+		}
+	})
+
+	t.Run("InvokeInvalidRequest", func(t *testing.T) {
+		rec := post(t, "/api/grpc/invoke", `{"target": "http://localhost:50051", "method": "x/y"}`, server.handleInvokeGRPC)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d, got %d", http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("InvokeUnknownMethod", func(t *testing.T) {
+		rec := post(t, "/api/grpc/invoke", `{"target": "`+reflectionTarget+`", "method": "grpc.health.v1.Health/Missing"}`, server.handleInvokeGRPC)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d, got %d", http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("ListAndDeleteProtoSet", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/grpc/protos", nil)
+		rec := httptest.NewRecorder()
+		if err := server.handleGetGRPCProtoSets(e.NewContext(req, rec)); err != nil {
+			t.Fatalf("handleGetGRPCProtoSets() error = %v", err)
+		}
+		var protoSets []models.GRPCProtoSet
+		json.Unmarshal(rec.Body.Bytes(), &protoSets)
+		if len(protoSets) != 1 {
+			t.Fatalf("Expected 1 proto set, got %d", len(protoSets))
+		}
+
+		req = httptest.NewRequest("DELETE", "/api/grpc/protos/"+fmt.Sprint(protoSetID), nil)
+		rec = httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(fmt.Sprint(protoSetID))
+		if err := server.handleDeleteGRPCProtoSet(c); err != nil {
+			t.Fatalf("handleDeleteGRPCProtoSet() error = %v", err)
+		}
+		if rec.Code != http.StatusNoContent {
+			t.Errorf("Expected status %d, got %d", http.StatusNoContent, rec.Code)
+		}
+	})
+}
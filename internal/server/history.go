@@ -0,0 +1,191 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/hc/hc/internal/logger"
+	"github.com/hc/hc/internal/models"
+	"github.com/hc/hc/internal/proxy"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	defaultHistoryLimit   = 100
+	maxHistoryLimit       = 1000
+	maxHistoryEntries     = 10000
+	maxStreamHistoryBytes = 1 << 20
+)
+
+func (s *Server) recordHistory(proxyReq *proxy.ProxyRequest, resp *models.Response) {
+	entry := newHistoryEntry(proxyReq)
+	metrics := resp.ResponseMetrics
+	entry.StatusCode = resp.StatusCode
+	entry.ResponseHeaders = proxy.RedactHeaders(resp.Headers)
+	entry.ResponseBody = resp.Body
+	entry.Duration = resp.Duration
+	entry.Metrics = &metrics
+	s.saveHistory(entry)
+}
+
+func (s *Server) recordFailedHistory(proxyReq *proxy.ProxyRequest, err error) {
+	var reqErr *proxy.RequestError
+	if !errors.As(err, &reqErr) {
+		return
+	}
+	entry := newHistoryEntry(proxyReq)
+	entry.ErrorCode = reqErr.Code
+	if reqErr.Timing != nil {
+		entry.Duration = reqErr.Timing.Total
+	}
+	entry.Metrics = &models.ResponseMetrics{RequestBodySize: int64(len(proxyReq.Body))}
+	s.saveHistory(entry)
+}
+
+func (s *Server) saveHistory(entry *models.HistoryEntry) {
+	if err := s.db.CreateHistoryEntry(entry); err != nil {
+		logger.Get().Error("Failed to record history", slog.String("error", err.Error()))
+	}
+}
+
+func (s *Server) purgeHistory() {
+	settings, err := s.db.GetSettings()
+	if err != nil {
+		logger.Get().Warn("Failed to load settings, skipping history purge", slog.String("error", err.Error()))
+		return
+	}
+	if _, err := s.db.PurgeHistory(time.Now().Add(-settings.HistoryRetention()), maxHistoryEntries); err != nil {
+		logger.Get().Warn("Failed to purge history", slog.String("error", err.Error()))
+	}
+}
+
+func newHistoryEntry(proxyReq *proxy.ProxyRequest) *models.HistoryEntry {
+	var sensitive []string
+	if auth := proxyReq.Auth; auth != nil && auth.Type == models.AuthTypeAPIKey && auth.In != models.AuthInQuery {
+		sensitive = append(sensitive, auth.Key)
+	}
+	return &models.HistoryEntry{
+		RequestID:      proxyReq.RequestID,
+		Method:         proxyReq.Method,
+		URL:            proxyReq.URL,
+		RequestHeaders: proxy.RedactHeaders(proxyReq.Headers, sensitive...),
+		RequestBody:    proxyReq.Body,
+	}
+}
+
+type streamHistory struct {
+	entry     *models.HistoryEntry
+	body      strings.Builder
+	bytes     int64
+	truncated bool
+}
+
+func newStreamHistory(proxyReq *proxy.ProxyRequest) *streamHistory {
+	entry := newHistoryEntry(proxyReq)
+	entry.Streamed = true
+	return &streamHistory{entry: entry}
+}
+
+func (h *streamHistory) observe(event models.StreamEvent) {
+	h.entry.Duration = event.Elapsed
+	switch event.Type {
+	case models.StreamEventHeaders:
+		h.entry.StatusCode = event.StatusCode
+		h.entry.ResponseHeaders = proxy.RedactHeaders(event.Headers)
+	case models.StreamEventChunk:
+		h.write(event.Data)
+	case models.StreamEventSSE:
+		h.write(formatSSE(event))
+	case models.StreamEventDone:
+		h.bytes = event.Bytes
+	}
+}
+
+func (h *streamHistory) write(data string) {
+	h.bytes += int64(len(data))
+	if h.truncated {
+		return
+	}
+	if h.body.Len()+len(data) > maxStreamHistoryBytes {
+		cut := maxStreamHistoryBytes - h.body.Len()
+		for cut > 0 && !utf8.RuneStart(data[cut]) {
+			cut--
+		}
+		h.body.WriteString(data[:cut])
+		h.truncated = true
+		return
+	}
+	h.body.WriteString(data)
+}
+
+func (h *streamHistory) finish(ctx context.Context, err error) *models.HistoryEntry {
+	var reqErr *proxy.RequestError
+	switch {
+	case err == nil:
+	case errors.Is(err, context.Canceled) || ctx.Err() != nil:
+		h.entry.ErrorCode = proxy.ErrorCodeCancelled
+	case errors.As(err, &reqErr):
+		h.entry.ErrorCode = reqErr.Code
+	default:
+		h.entry.ErrorCode = proxy.ErrorCodeRequestFailed
+	}
+	h.entry.ResponseBody = h.body.String()
+	h.entry.Metrics = &models.ResponseMetrics{
+		RequestBodySize: int64(len(h.entry.RequestBody)),
+		BodySize:        h.bytes,
+	}
+	return h.entry
+}
+
+func formatSSE(event models.StreamEvent) string {
+	var b strings.Builder
+	if event.Event != "" {
+		b.WriteString("event: " + event.Event + "\n")
+	}
+	if event.ID != "" {
+		b.WriteString("id: " + event.ID + "\n")
+	}
+	for _, line := range strings.Split(event.Data, "\n") {
+		b.WriteString("data: " + line + "\n")
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+func (s *Server) handleGetHistory(c echo.Context) error {
+	limit := defaultHistoryLimit
+	if value := c.QueryParam("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 || parsed > maxHistoryLimit {
+			return c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid limit"))
+		}
+		limit = parsed
+	}
+	s.purgeHistory()
+	entries, err := s.db.GetHistory(limit)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to get history"))
+	}
+	if entries == nil {
+		entries = []models.HistoryEntry{}
+	}
+	return c.JSON(http.StatusOK, entries)
+}
+
+func (s *Server) handleGetHistoryEntryByID(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid history entry ID"))
+	}
+	var entry models.HistoryEntry
+	if err := s.db.GetHistoryEntry(id, &entry); err != nil {
+		return c.JSON(http.StatusNotFound, models.NewErrorResponse("History entry not found"))
+	}
+	return c.JSON(http.StatusOK, entry)
+}
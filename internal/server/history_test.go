@@ -0,0 +1,178 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/hc/hc/internal/models"
+	"github.com/labstack/echo/v4"
+)
+
+func TestProxyRequestRecordsHistory(t *testing.T) {
+	server, _ := setupTestServer(t)
+	e := echo.New()
+
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc"})
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("created"))
+	}))
+	defer target.Close()
+
+	body, _ := json.Marshal(map[string]any{
+		"method":     "POST",
+		"url":        target.URL,
+		"body":       `{"name": "x"}`,
+		"request_id": 3,
+		"headers":    map[string]string{"Authorization": "Bearer secret", "X-Api-Key": "key", "Accept": "text/plain"},
+	})
+	req := httptest.NewRequest("POST", "/api/request", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	if err := server.handleProxyRequest(e.NewContext(req, rec)); err != nil {
+		t.Fatalf("handleProxyRequest() error = %v", err)
+	}
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	req = httptest.NewRequest("GET", "/api/history", nil)
+	rec = httptest.NewRecorder()
+	if err := server.handleGetHistory(e.NewContext(req, rec)); err != nil {
+		t.Fatalf("handleGetHistory() error = %v", err)
+	}
+	var entries []models.HistoryEntry
+	if err := json.Unmarshal(rec.Body.Bytes(), &entries); err != nil {
+		t.Fatalf("Failed to decode history: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 history entry, got %d", len(entries))
+	}
+	entry := entries[0]
+	if entry.StatusCode != http.StatusCreated || entry.ResponseBody != "created" {
+		t.Errorf("Expected recorded response, got %d %q", entry.StatusCode, entry.ResponseBody)
+	}
+	if entry.RequestID == nil || *entry.RequestID != 3 {
+		t.Errorf("Expected request ID 3, got %v", entry.RequestID)
+	}
+	if entry.Metrics == nil || entry.Metrics.Protocol != "HTTP/1.1" || entry.Metrics.RequestBodySize != 13 || entry.Metrics.BodySize != 7 {
+		t.Errorf("Expected recorded metrics, got %+v", entry.Metrics)
+	}
+	if entry.RequestHeaders["Authorization"] != "[redacted]" || entry.RequestHeaders["X-Api-Key"] != "[redacted]" || entry.RequestHeaders["Accept"] != "text/plain" {
+		t.Errorf("Expected sensitive request headers to be redacted, got %v", entry.RequestHeaders)
+	}
+	if entry.ResponseHeaders["Set-Cookie"] != "[redacted]" {
+		t.Errorf("Expected Set-Cookie to be redacted, got %v", entry.ResponseHeaders)
+	}
+
+	c := e.NewContext(httptest.NewRequest("GET", "/", nil), httptest.NewRecorder())
+	c.SetParamNames("id")
+	c.SetParamValues(strconv.Itoa(entry.ID))
+	if err := server.handleGetHistoryEntryByID(c); err != nil {
+		t.Fatalf("handleGetHistoryEntryByID() error = %v", err)
+	}
+	if code := c.Response().Status; code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", code)
+	}
+}
+
+func TestFailedProxyRequestRecordsHistory(t *testing.T) {
+	server, db := setupTestServer(t)
+	e := echo.New()
+
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := target.URL
+	target.Close()
+
+	body, _ := json.Marshal(map[string]any{"method": "GET", "url": url})
+	req := httptest.NewRequest("POST", "/api/request", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	if err := server.handleProxyRequest(e.NewContext(req, rec)); err != nil {
+		t.Fatalf("handleProxyRequest() error = %v", err)
+	}
+	if rec.Code != http.StatusBadGateway {
+		t.Fatalf("Expected status 502, got %d: %s", rec.Code, rec.Body.String())
+	}
+	entries, err := db.GetHistory(1)
+	if err != nil {
+		t.Fatalf("GetHistory() error = %v", err)
+	}
+	if len(entries) != 1 || entries[0].ErrorCode != "connection_refused" || entries[0].StatusCode != 0 {
+		t.Errorf("Expected failed execution in history, got %+v", entries)
+	}
+}
+
+func TestHandleGetHistoryErrors(t *testing.T) {
+	server, _ := setupTestServer(t)
+	e := echo.New()
+
+	tests := []struct {
+		name       string
+		handler    func(echo.Context) error
+		target     string
+		id         string
+		wantStatus int
+	}{
+		{name: "Invalid limit", handler: server.handleGetHistory, target: "/api/history?limit=abc", wantStatus: http.StatusBadRequest},
+		{name: "Limit too large", handler: server.handleGetHistory, target: "/api/history?limit=5000", wantStatus: http.StatusBadRequest},
+		{name: "Invalid ID", handler: server.handleGetHistoryEntryByID, target: "/api/history/abc", id: "abc", wantStatus: http.StatusBadRequest},
+		{name: "Missing entry", handler: server.handleGetHistoryEntryByID, target: "/api/history/999", id: "999", wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			c := e.NewContext(httptest.NewRequest("GET", tt.target, nil), rec)
+			if tt.id != "" {
+				c.SetParamNames("id")
+				c.SetParamValues(tt.id)
+			}
+			if err := tt.handler(c); err != nil {
+				t.Fatalf("handler error = %v", err)
+			}
+			if rec.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+		})
+	}
+}
+
+func TestStreamHistoryTruncatesOnRuneBoundary(t *testing.T) {
+	tests := []struct {
+		name    string
+		prefix  int
+		data    string
+		wantLen int
+	}{
+		{name: "Fits exactly", prefix: maxStreamHistoryBytes - 2, data: "é", wantLen: maxStreamHistoryBytes},
+		{name: "Two-byte rune across the limit", prefix: maxStreamHistoryBytes - 1, data: "é", wantLen: maxStreamHistoryBytes - 1},
+		{name: "Four-byte rune across the limit", prefix: maxStreamHistoryBytes - 2, data: "😀", wantLen: maxStreamHistoryBytes - 2},
+		{name: "ASCII across the limit", prefix: maxStreamHistoryBytes - 1, data: "ab", wantLen: maxStreamHistoryBytes},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &streamHistory{entry: &models.HistoryEntry{}}
+			h.write(strings.Repeat("a", tt.prefix))
+			h.write(tt.data)
+			h.write("more")
+			entry := h.finish(t.Context(), nil)
+			if len(entry.ResponseBody) != tt.wantLen {
+				t.Errorf("Expected %d stored bytes, got %d", tt.wantLen, len(entry.ResponseBody))
+			}
+			if !utf8.ValidString(entry.ResponseBody) {
+				t.Error("Expected stored body to be valid UTF-8")
+			}
+			if want := int64(tt.prefix + len(tt.data) + len("more")); entry.Metrics.BodySize != want {
+				t.Errorf("Expected body size %d, got %d", want, entry.Metrics.BodySize)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package server
+
+import (
+	"errors"
+	"maps"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/hc/hc/internal/models"
+	"github.com/hc/hc/internal/proxy"
+	"github.com/labstack/echo/v4"
+)
+
+func validateFolder(folder *models.Folder) error {
+	if folder.BaseURL != "" && !strings.Contains(folder.BaseURL, "{{") {
+		if err := proxy.ValidateURL(folder.BaseURL); err != nil {
+			return errors.New("invalid base URL: " + err.Error())
+		}
+	}
+	if err := proxy.ValidateHostOverrides(folder.HostOverrides); err != nil {
+		return err
+	}
+	return proxy.ValidateAuth(folder.Auth)
+}
+
+func (s *Server) inheritFolder(proxyReq *proxy.ProxyRequest) error {
+	folders, err := s.db.GetFolderChain(*proxyReq.FolderID)
+	if err != nil {
+		return err
+	}
+	effective := proxy.ResolveRequest(&models.Request{
+		Method:  proxyReq.Method,
+		URL:     proxyReq.URL,
+		Headers: proxyReq.Headers,
+		Body:    proxyReq.Body,
+		Auth:    proxyReq.Auth,
+		GraphQL: proxyReq.GraphQL,
+	}, folders)
+	proxyReq.URL = effective.URL
+	proxyReq.Headers = effective.Headers
+	proxyReq.Body = effective.Body
+	proxyReq.Auth = effective.Auth
+	proxyReq.GraphQL = effective.GraphQL
+	if len(effective.HostOverrides) > 0 {
+		resolve := make(map[string]string, len(effective.HostOverrides)+len(proxyReq.Resolve))
+		maps.Copy(resolve, effective.HostOverrides)
+		maps.Copy(resolve, proxyReq.Resolve)
+		proxyReq.Resolve = resolve
+	}
+	return nil
+}
+
+func (s *Server) handleGetEffectiveRequest(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request ID"))
+	}
+	var request models.Request
+	if err := s.db.GetRequest(id, &request); err != nil {
+		return c.JSON(http.StatusNotFound, models.NewErrorResponse("Request not found"))
+	}
+	var folders []models.Folder
+	if request.FolderID != nil {
+		folders, err = s.db.GetFolderChain(*request.FolderID)
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to resolve folder settings"))
+		}
+	}
+	return c.JSON(http.StatusOK, proxy.ResolveRequest(&request, folders))
+}
@@ -0,0 +1,297 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hc/hc/internal/models"
+	"github.com/labstack/echo/v4"
+)
+
+func TestHandleGetEffectiveRequest(t *testing.T) {
+	server, db := setupTestServer(t)
+	e := echo.New()
+
+	root := &models.Folder{
+		Name:      "API",
+		Headers:   map[string]string{"Accept": "application/json"},
+		Auth:      &models.Auth{Type: models.AuthTypeBearer, Token: "{{token}}"},
+		BaseURL:   "https://api.example.com",
+		Variables: map[string]string{"token": "secret"},
+	}
+	if err := db.CreateFolder(root); err != nil {
+		t.Fatalf("CreateFolder() error = %v", err)
+	}
+	child := &models.Folder{Name: "Users", ParentID: &root.ID, BaseURL: "https://api.example.com/users"}
+	if err := db.CreateFolder(child); err != nil {
+		t.Fatalf("CreateFolder() error = %v", err)
+	}
+	request := &models.Request{Name: "Get user", Method: "GET", URL: "/42", FolderID: &child.ID}
+	if err := db.CreateRequest(request); err != nil {
+		t.Fatalf("CreateRequest() error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		id         string
+		wantStatus int
+	}{
+		{name: "Effective request", id: strconv.Itoa(request.ID), wantStatus: http.StatusOK},
+		{name: "Invalid ID", id: "abc", wantStatus: http.StatusBadRequest},
+		{name: "Missing request", id: "999", wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			c := e.NewContext(httptest.NewRequest("GET", "/", nil), rec)
+			c.SetParamNames("id")
+			c.SetParamValues(tt.id)
+			if err := server.handleGetEffectiveRequest(c); err != nil {
+				t.Fatalf("handleGetEffectiveRequest() error = %v", err)
+			}
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+			if rec.Code != http.StatusOK {
+				return
+			}
+			var effective models.EffectiveRequest
+			if err := json.Unmarshal(rec.Body.Bytes(), &effective); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if effective.URL != "https://api.example.com/users/42" {
+				t.Errorf("Expected resolved URL, got %s", effective.URL)
+			}
+			if effective.Headers["Accept"] != "application/json" {
+				t.Errorf("Expected inherited Accept header, got %v", effective.Headers)
+			}
+			if effective.Auth == nil || effective.Auth.Token != "secret" {
+				t.Errorf("Expected inherited bearer auth, got %+v", effective.Auth)
+			}
+		})
+	}
+}
+
+func TestProxyRequestInheritsFolder(t *testing.T) {
+	server, db := setupTestServer(t)
+	e := echo.New()
+
+	var gotAuth, gotPath string
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		gotPath = r.URL.Path
+	}))
+	defer target.Close()
+
+	folder := &models.Folder{
+		Name:    "Local",
+		BaseURL: target.URL,
+		Auth:    &models.Auth{Type: models.AuthTypeBearer, Token: "folder-token"},
+	}
+	if err := db.CreateFolder(folder); err != nil {
+		t.Fatalf("CreateFolder() error = %v", err)
+	}
+
+	body, _ := json.Marshal(map[string]any{"method": "GET", "url": "/status", "folder_id": folder.ID})
+	req := httptest.NewRequest("POST", "/api/request", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	if err := server.handleProxyRequest(e.NewContext(req, rec)); err != nil {
+		t.Fatalf("handleProxyRequest() error = %v", err)
+	}
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if gotAuth != "Bearer folder-token" || gotPath != "/status" {
+		t.Errorf("Expected inherited auth and base URL, got %q and %q", gotAuth, gotPath)
+	}
+
+	body, _ = json.Marshal(map[string]any{"method": "GET", "url": "/status", "folder_id": 999})
+	req = httptest.NewRequest("POST", "/api/request", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec = httptest.NewRecorder()
+	if err := server.handleProxyRequest(e.NewContext(req, rec)); err != nil {
+		t.Fatalf("handleProxyRequest() error = %v", err)
+	}
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for missing folder, got %d", rec.Code)
+	}
+}
+
+func TestProxyRequestInheritsHostOverrides(t *testing.T) {
+	server, db := setupTestServer(t)
+	e := echo.New()
+
+	var gotHost string
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHost = r.Host
+	}))
+	defer target.Close()
+
+	folder := &models.Folder{
+		Name:          "Staging",
+		HostOverrides: map[string]string{"api.example.test": strings.TrimPrefix(target.URL, "http://")},
+	}
+	if err := db.CreateFolder(folder); err != nil {
+		t.Fatalf("CreateFolder() error = %v", err)
+	}
+
+	body, _ := json.Marshal(map[string]any{"method": "GET", "url": "http://api.example.test/status", "folder_id": folder.ID})
+	req := httptest.NewRequest("POST", "/api/request", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	if err := server.handleProxyRequest(e.NewContext(req, rec)); err != nil {
+		t.Fatalf("handleProxyRequest() error = %v", err)
+	}
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if gotHost != "api.example.test" {
+		t.Errorf("Expected Host api.example.test, got %s", gotHost)
+	}
+}
+
+func TestStreamRequestInheritsFolder(t *testing.T) {
+	server, db := setupTestServer(t)
+	e := echo.New()
+
+	var gotAuth, gotQuery string
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		var body struct {
+			Query string `json:"query"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		gotQuery = body.Query
+	}))
+	defer target.Close()
+
+	folder := &models.Folder{
+		Name:      "Local",
+		BaseURL:   target.URL,
+		Auth:      &models.Auth{Type: models.AuthTypeBearer, Token: "folder-token"},
+		Variables: map[string]string{"id": "42"},
+	}
+	if err := db.CreateFolder(folder); err != nil {
+		t.Fatalf("CreateFolder() error = %v", err)
+	}
+
+	body, _ := json.Marshal(map[string]any{
+		"method":    "POST",
+		"url":       "/graphql",
+		"folder_id": folder.ID,
+		"graphql":   map[string]string{"query": "{ user(id: {{id}}) { name } }"},
+	})
+	req := httptest.NewRequest("POST", "/api/request/stream", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	if err := server.handleStreamRequest(e.NewContext(req, rec)); err != nil {
+		t.Fatalf("handleStreamRequest() error = %v", err)
+	}
+	if !strings.Contains(rec.Body.String(), "event: done") {
+		t.Fatalf("Expected stream to complete, got %s", rec.Body.String())
+	}
+	if gotAuth != "Bearer folder-token" || gotQuery != "{ user(id: 42) { name } }" {
+		t.Errorf("Expected inherited auth and substituted query, got %q and %q", gotAuth, gotQuery)
+	}
+}
+
+func TestValidateFolder(t *testing.T) {
+	tests := []struct {
+		name    string
+		folder  models.Folder
+		wantErr bool
+	}{
+		{name: "empty", folder: models.Folder{Name: "x"}},
+		{name: "base URL", folder: models.Folder{Name: "x", BaseURL: "https://api.example.com"}},
+		{name: "templated base URL", folder: models.Folder{Name: "x", BaseURL: "{{base}}"}},
+		{name: "invalid base URL", folder: models.Folder{Name: "x", BaseURL: "api.example.com"}, wantErr: true},
+		{name: "host overrides", folder: models.Folder{Name: "x", HostOverrides: map[string]string{"api.example.com": "{{staging}}"}}},
+		{name: "invalid host override", folder: models.Folder{Name: "x", HostOverrides: map[string]string{"api.example.com": ""}}, wantErr: true},
+		{name: "invalid auth", folder: models.Folder{Name: "x", Auth: &models.Auth{Type: "digest"}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateFolder(&tt.folder)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateFolder() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUpdateFolderPreservesOmittedSettings(t *testing.T) {
+	server, db := setupTestServer(t)
+	e := echo.New()
+
+	folder := &models.Folder{
+		Name:          "API",
+		Description:   "Docs",
+		Headers:       map[string]string{"Accept": "application/json"},
+		Auth:          &models.Auth{Type: models.AuthTypeBearer, Token: "t0ken"},
+		BaseURL:       "https://api.example.com",
+		Variables:     map[string]string{"version": "v1"},
+		HostOverrides: map[string]string{"api.example.com": "127.0.0.1"},
+	}
+	if err := db.CreateFolder(folder); err != nil {
+		t.Fatalf("CreateFolder() error = %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		body        string
+		wantBaseURL string
+		wantHeaders int
+	}{
+		{name: "Omitted settings are kept", body: `{"id": ` + strconv.Itoa(folder.ID) + `, "name": "Renamed", "parent_id": null}`, wantBaseURL: "https://api.example.com", wantHeaders: 1},
+		{name: "Sent settings are applied", body: `{"name": "Renamed", "base_url": "", "headers": {}}`, wantBaseURL: "", wantHeaders: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("PUT", "/", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("id")
+			c.SetParamValues(strconv.Itoa(folder.ID))
+			if err := server.handleUpdateFolderByID(c); err != nil {
+				t.Fatalf("handleUpdateFolderByID() error = %v", err)
+			}
+			if rec.Code != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+			}
+
+			var updated models.Folder
+			if err := db.GetFolder(folder.ID, &updated); err != nil {
+				t.Fatalf("GetFolder() error = %v", err)
+			}
+			if updated.Name != "Renamed" || updated.BaseURL != tt.wantBaseURL || len(updated.Headers) != tt.wantHeaders {
+				t.Errorf("Expected name Renamed, base URL %q and %d headers, got %q, %q and %v", tt.wantBaseURL, tt.wantHeaders, updated.Name, updated.BaseURL, updated.Headers)
+			}
+			if updated.Description != "Docs" || updated.Auth == nil || updated.Auth.Token != "t0ken" || updated.Variables["version"] != "v1" || updated.HostOverrides["api.example.com"] != "127.0.0.1" {
+				t.Errorf("Expected description, auth, variables and host overrides to be kept, got %+v", updated)
+			}
+		})
+	}
+
+	req := httptest.NewRequest("PUT", "/", strings.NewReader(`{"name": "Missing"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("999")
+	if err := server.handleUpdateFolderByID(c); err != nil {
+		t.Fatalf("handleUpdateFolderByID() error = %v", err)
+	}
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,137 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/hc/hc/internal/mock"
+	"github.com/hc/hc/internal/models"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	maxMockLatency  = 60000
+	mockStopTimeout = 5 * time.Second
+)
+
+type mockController struct {
+	mu     sync.Mutex
+	server *mock.Server
+	config models.MockConfig
+}
+
+func newMockController() *mockController {
+	return &mockController{}
+}
+
+func (m *mockController) status() models.MockStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.server == nil {
+		return models.MockStatus{}
+	}
+	config := m.config
+	return models.MockStatus{Running: true, Address: m.server.Address(), Routes: m.server.Routes(), Config: &config}
+}
+
+func (m *mockController) start(server *mock.Mock, config models.MockConfig) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.stopLocked(); err != nil {
+		return err
+	}
+	started, err := mock.Start(server, config.Port)
+	if err != nil {
+		return err
+	}
+	m.server = started
+	m.config = config
+	return nil
+}
+
+func (m *mockController) stop() (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	running := m.server != nil
+	return running, m.stopLocked()
+}
+
+func (m *mockController) stopLocked() error {
+	if m.server == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), mockStopTimeout)
+	defer cancel()
+	err := m.server.Stop(ctx)
+	m.server = nil
+	return err
+}
+
+func (m *mockController) log() ([]models.MockLogEntry, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.server == nil {
+		return nil, false
+	}
+	return m.server.Log(), true
+}
+
+func validateMockConfig(config *models.MockConfig) error {
+	if config.Port < 0 || config.Port > 65535 {
+		return errors.New("port must be between 0 and 65535")
+	}
+	if config.Latency < 0 || config.Latency > maxMockLatency {
+		return errors.New("latency must be between 0 and 60000 milliseconds")
+	}
+	return mock.ValidateCORSOrigins(config.CORSOrigins)
+}
+
+func (s *Server) handleGetMock(c echo.Context) error {
+	return c.JSON(http.StatusOK, s.mock.status())
+}
+
+func (s *Server) handleStartMock(c echo.Context) error {
+	config := models.MockConfig{Port: models.DefaultMockPort}
+	if err := c.Bind(&config); err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body"))
+	}
+	if err := validateMockConfig(&config); err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error()))
+	}
+	if config.FolderID != nil {
+		var folder models.Folder
+		if err := s.db.GetFolder(*config.FolderID, &folder); err != nil {
+			return c.JSON(http.StatusNotFound, models.NewErrorResponse("Folder not found"))
+		}
+	}
+	server, err := mock.Load(s.db, config, nil)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to load mock routes"))
+	}
+	if err := s.mock.start(server, config); err != nil {
+		return c.JSON(http.StatusConflict, models.NewErrorResponse("Failed to start mock server: "+err.Error()))
+	}
+	return c.JSON(http.StatusOK, s.mock.status())
+}
+
+func (s *Server) handleStopMock(c echo.Context) error {
+	running, err := s.mock.stop()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to stop mock server"))
+	}
+	if !running {
+		return c.JSON(http.StatusNotFound, models.NewErrorResponse("Mock server is not running"))
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (s *Server) handleGetMockLog(c echo.Context) error {
+	entries, running := s.mock.log()
+	if !running {
+		return c.JSON(http.StatusNotFound, models.NewErrorResponse("Mock server is not running"))
+	}
+	return c.JSON(http.StatusOK, entries)
+}
@@ -0,0 +1,106 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hc/hc/internal/models"
+	"github.com/labstack/echo/v4"
+)
+
+func TestHandleMock(t *testing.T) {
+	server, db := setupTestServer(t)
+	e := echo.New()
+	t.Cleanup(func() { server.mock.stop() })
+
+	folder := &models.Folder{Name: "API"}
+	if err := db.CreateFolder(folder); err != nil {
+		t.Fatalf("CreateFolder() error = %v", err)
+	}
+	request := &models.Request{Name: "Health", FolderID: &folder.ID, Method: "GET", URL: "https://example.com/health", Examples: []models.ExampleResponse{{Name: "OK", StatusCode: 200, Body: "ok"}}}
+	if err := db.CreateRequest(request); err != nil {
+		t.Fatalf("CreateRequest() error = %v", err)
+	}
+
+	call := func(handler echo.HandlerFunc, method, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/api/mock", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		if err := handler(e.NewContext(req, rec)); err != nil {
+			t.Fatalf("handler error = %v", err)
+		}
+		return rec
+	}
+
+	startTests := []struct {
+		name       string
+		body       string
+		wantStatus int
+	}{
+		{name: "Negative latency", body: `{"port": 0, "latency": -1}`, wantStatus: http.StatusBadRequest},
+		{name: "Invalid port", body: `{"port": 70000}`, wantStatus: http.StatusBadRequest},
+		{name: "Invalid CORS origin", body: `{"port": 0, "cors_origins": ["example.com/path"]}`, wantStatus: http.StatusBadRequest},
+		{name: "Missing folder", body: `{"port": 0, "folder_id": 999}`, wantStatus: http.StatusNotFound},
+		{name: "Start", body: `{"port": 0, "folder_id": ` + strconv.Itoa(folder.ID) + `}`, wantStatus: http.StatusOK},
+	}
+	for _, tt := range startTests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := call(server.handleStartMock, "POST", tt.body)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+		})
+	}
+
+	var status models.MockStatus
+	json.Unmarshal(call(server.handleGetMock, "GET", "").Body.Bytes(), &status)
+	if !status.Running || status.Routes != 1 || status.Address == "" {
+		t.Fatalf("Expected running mock server with 1 route, got %+v", status)
+	}
+
+	resp, err := http.Get(status.Address + "/health")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || string(body) != "ok" {
+		t.Errorf("Expected 200 ok, got %d %q", resp.StatusCode, body)
+	}
+
+	rec := call(server.handleGetMockLog, "GET", "")
+	var entries []models.MockLogEntry
+	json.Unmarshal(rec.Body.Bytes(), &entries)
+	if rec.Code != http.StatusOK || len(entries) != 1 || entries[0].Path != "/health" {
+		t.Errorf("Expected one log entry for /health, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	stopTests := []struct {
+		name       string
+		wantStatus int
+	}{
+		{name: "Stop", wantStatus: http.StatusNoContent},
+		{name: "Stop again", wantStatus: http.StatusNotFound},
+	}
+	for _, tt := range stopTests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := call(server.handleStopMock, "DELETE", "")
+			if rec.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+		})
+	}
+
+	if rec := call(server.handleGetMockLog, "GET", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d for stopped mock log, got %d", http.StatusNotFound, rec.Code)
+	}
+	json.Unmarshal(call(server.handleGetMock, "GET", "").Body.Bytes(), &status)
+	if status.Running {
+		t.Error("Expected mock server to be stopped")
+	}
+}
@@ -0,0 +1,44 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/hc/hc/internal/logger"
+	"github.com/hc/hc/internal/models"
+	"github.com/hc/hc/internal/proxy"
+	"github.com/labstack/echo/v4"
+)
+
+func (s *Server) handleRawRequest(c echo.Context) error {
+	var rawReq proxy.RawRequest
+	if err := c.Bind(&rawReq); err != nil {
+		logger.Get().Error("Failed to bind raw request", slog.String("error", err.Error()))
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body"))
+	}
+	if err := proxy.ValidateRawRequest(&rawReq); err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error()))
+	}
+	ctx, executionID, done, err := s.executions.start(c.Request().Context(), rawReq.ExecutionID)
+	if err != nil {
+		return c.JSON(http.StatusConflict, models.NewErrorResponse(err.Error()))
+	}
+	defer done()
+	c.Response().Header().Set("X-Execution-ID", executionID)
+	logger.Get().Info("Sending raw request",
+		slog.String("execution_id", executionID),
+		slog.String("target", rawReq.Target),
+		slog.Int("bytes", len(rawReq.Raw)))
+	resp, err := s.proxyClient.SendRaw(ctx, &rawReq)
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			logger.Get().Info("Raw request cancelled", slog.String("execution_id", executionID))
+		} else {
+			logger.Get().Error("Raw request failed", slog.String("error", err.Error()))
+		}
+		return c.JSON(executionErrorResponse(err))
+	}
+	return c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,91 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hc/hc/internal/models"
+	"github.com/labstack/echo/v4"
+)
+
+func TestHandleRawRequest(t *testing.T) {
+	server, _ := setupTestServer(t)
+	e := echo.New()
+
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Method", r.Method)
+		w.WriteHeader(http.StatusTeapot)
+		w.Write([]byte("raw ok"))
+	}))
+	defer target.Close()
+
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closedURL := closed.URL
+	closed.Close()
+
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		expectedCode   string
+	}{
+		{
+			name:           "Valid raw request",
+			body:           `{"target": "` + target.URL + `", "raw": "PURGE /cache HTTP/1.1\nHost: example.com\nConnection: close\n", "normalize_line_endings": true}`,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Missing raw request",
+			body:           `{"target": "` + target.URL + `"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid target",
+			body:           `{"target": "ftp://example.com", "raw": "GET / HTTP/1.1\r\n\r\n"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Connection refused",
+			body:           `{"target": "` + closedURL + `", "raw": "GET / HTTP/1.1\r\n\r\n"}`,
+			expectedStatus: http.StatusBadGateway,
+			expectedCode:   "connection_refused",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/request/raw", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			if err := server.handleRawRequest(e.NewContext(req, rec)); err != nil {
+				t.Fatalf("handleRawRequest() error = %v", err)
+			}
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if tt.expectedCode != "" {
+				var errResp models.ErrorResponse
+				json.Unmarshal(rec.Body.Bytes(), &errResp)
+				if errResp.Code != tt.expectedCode {
+					t.Errorf("Expected code %q, got %q", tt.expectedCode, errResp.Code)
+				}
+			}
+			if rec.Code != http.StatusOK {
+				return
+			}
+			var resp models.RawResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("Failed to unmarshal response: %v", err)
+			}
+			if !strings.HasPrefix(resp.Raw, "HTTP/1.1 418 I'm a teapot\r\n") {
+				t.Errorf("Unexpected raw response %q", resp.Raw)
+			}
+			if resp.Response == nil || resp.Response.Headers["X-Method"] != "PURGE" || resp.Response.Body != "raw ok" {
+				t.Errorf("Unexpected parsed response %+v", resp.Response)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/hc/hc/internal/models"
+	"github.com/hc/hc/internal/storage"
+	"github.com/labstack/echo/v4"
+)
+
+var revisionFields = []struct {
+	name  string
+	value func(*models.Request) any
+}{
+	{"name", func(r *models.Request) any { return r.Name }},
+	{"description", func(r *models.Request) any { return r.Description }},
+	{"folder_id", func(r *models.Request) any { return r.FolderID }},
+	{"type", func(r *models.Request) any { return r.Type }},
+	{"method", func(r *models.Request) any { return r.Method }},
+	{"url", func(r *models.Request) any { return r.URL }},
+	{"body", func(r *models.Request) any { return r.Body }},
+	{"compression", func(r *models.Request) any { return r.Compression }},
+	{"protocol", func(r *models.Request) any { return r.Protocol }},
+	{"auth", func(r *models.Request) any { return r.Auth }},
+	{"graphql", func(r *models.Request) any { return r.GraphQL }},
+	{"grpc", func(r *models.Request) any { return r.GRPC }},
+	{"tags", func(r *models.Request) any { return storage.NormalizeTags(r.Tags) }},
+	{"favorite", func(r *models.Request) any { return r.Favorite }},
+}
+
+func (s *Server) handleGetRequestRevisions(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request ID"))
+	}
+	var request models.Request
+	if err := s.db.GetRequest(id, &request); err != nil {
+		return c.JSON(http.StatusNotFound, models.NewErrorResponse("Request not found"))
+	}
+	revisions, err := s.db.GetRequestRevisions(id)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to get revisions"))
+	}
+	if revisions == nil {
+		revisions = []models.RequestRevision{}
+	}
+	return c.JSON(http.StatusOK, revisions)
+}
+
+func (s *Server) handleDiffRequestRevisions(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request ID"))
+	}
+	var current models.Request
+	if err := s.db.GetRequest(id, &current); err != nil {
+		return c.JSON(http.StatusNotFound, models.NewErrorResponse("Request not found"))
+	}
+	from, err := s.requestAtRevision(id, c.QueryParam("from"), &current)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error()))
+	}
+	to, err := s.requestAtRevision(id, c.QueryParam("to"), &current)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error()))
+	}
+	return c.JSON(http.StatusOK, diffRequests(from, to))
+}
+
+func (s *Server) handleRestoreRequestRevision(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request ID"))
+	}
+	number, err := strconv.Atoi(c.Param("revision"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid revision"))
+	}
+	var request models.Request
+	if err := s.db.GetRequest(id, &request); err != nil {
+		return c.JSON(http.StatusNotFound, models.NewErrorResponse("Request not found"))
+	}
+	var revision models.RequestRevision
+	if err := s.db.GetRequestRevision(id, number, &revision); err != nil {
+		return c.JSON(http.StatusNotFound, models.NewErrorResponse("Revision not found"))
+	}
+	restored := revision.Request
+	restored.ID = request.ID
+	restored.FolderID = request.FolderID
+	if err := s.db.UpdateRequest(&restored); err != nil {
+		return c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to restore revision"))
+	}
+	if err := s.db.GetRequest(id, &request); err != nil {
+		return c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to get request"))
+	}
+	return c.JSON(http.StatusOK, request)
+}
+
+func (s *Server) requestAtRevision(id int, value string, current *models.Request) (*models.Request, error) {
+	if value == "" || value == "current" {
+		return current, nil
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, fmt.Errorf("revision %q not found", value)
+	}
+	var revision models.RequestRevision
+	if err := s.db.GetRequestRevision(id, number, &revision); err != nil {
+		return nil, fmt.Errorf("revision %q not found", value)
+	}
+	return &revision.Request, nil
+}
+
+func diffRequests(from, to *models.Request) []models.FieldChange {
+	changes := []models.FieldChange{}
+	for _, field := range revisionFields {
+		a, b := field.value(from), field.value(to)
+		if !reflect.DeepEqual(a, b) {
+			changes = append(changes, models.FieldChange{Field: field.name, From: a, To: b})
+		}
+	}
+	keys := make(map[string]bool)
+	for key := range from.Headers {
+		keys[key] = true
+	}
+	for key := range to.Headers {
+		keys[key] = true
+	}
+	var names []string
+	for key := range keys {
+		names = append(names, key)
+	}
+	sort.Strings(names)
+	for _, key := range names {
+		a, inFrom := from.Headers[key]
+		b, inTo := to.Headers[key]
+		if inFrom == inTo && a == b {
+			continue
+		}
+		change := models.FieldChange{Field: "headers." + key}
+		if inFrom {
+			change.From = a
+		}
+		if inTo {
+			change.To = b
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
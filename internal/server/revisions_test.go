@@ -0,0 +1,241 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hc/hc/internal/models"
+	"github.com/labstack/echo/v4"
+)
+
+func TestHandleRequestRevisions(t *testing.T) {
+	server, db := setupTestServer(t)
+	e := echo.New()
+
+	request := &models.Request{Name: "Request", Method: "GET", URL: "https://example.com", Headers: map[string]string{"Accept": "text/plain"}}
+	if err := db.CreateRequest(request); err != nil {
+		t.Fatalf("CreateRequest() error = %v", err)
+	}
+	request.Method = "POST"
+	request.Headers = map[string]string{"Accept": "application/json", "X-Trace": "1"}
+	if err := db.UpdateRequest(request); err != nil {
+		t.Fatalf("UpdateRequest() error = %v", err)
+	}
+	id := strconv.Itoa(request.ID)
+
+	t.Run("List revisions", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest("GET", "/", nil), rec)
+		c.SetParamNames("id")
+		c.SetParamValues(id)
+		if err := server.handleGetRequestRevisions(c); err != nil {
+			t.Fatalf("handleGetRequestRevisions() error = %v", err)
+		}
+		var revisions []models.RequestRevision
+		json.Unmarshal(rec.Body.Bytes(), &revisions)
+		if rec.Code != http.StatusOK || len(revisions) != 1 || revisions[0].Request.Method != "GET" {
+			t.Errorf("Expected one revision with the original method, got %d: %s", rec.Code, rec.Body.String())
+		}
+	})
+
+	diffTests := []struct {
+		name        string
+		query       string
+		wantStatus  int
+		wantChanges []models.FieldChange
+	}{
+		{
+			name:       "Diff revision against current",
+			query:      "?from=1",
+			wantStatus: http.StatusOK,
+			wantChanges: []models.FieldChange{
+				{Field: "method", From: "GET", To: "POST"},
+				{Field: "headers.Accept", From: "text/plain", To: "application/json"},
+				{Field: "headers.X-Trace", From: nil, To: "1"},
+			},
+		},
+		{name: "Diff identical revisions", query: "?from=1&to=1", wantStatus: http.StatusOK, wantChanges: []models.FieldChange{}},
+		{name: "Diff missing revision", query: "?from=9", wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range diffTests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			c := e.NewContext(httptest.NewRequest("GET", "/"+tt.query, nil), rec)
+			c.SetParamNames("id")
+			c.SetParamValues(id)
+			if err := server.handleDiffRequestRevisions(c); err != nil {
+				t.Fatalf("handleDiffRequestRevisions() error = %v", err)
+			}
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			if tt.wantChanges == nil {
+				return
+			}
+			want, _ := json.Marshal(tt.wantChanges)
+			if got := rec.Body.String(); got != string(want)+"\n" {
+				t.Errorf("Expected changes %s, got %s", want, got)
+			}
+		})
+	}
+
+	restoreTests := []struct {
+		name       string
+		revision   string
+		wantStatus int
+	}{
+		{name: "Restore revision", revision: "1", wantStatus: http.StatusOK},
+		{name: "Restore missing revision", revision: "9", wantStatus: http.StatusNotFound},
+		{name: "Invalid revision", revision: "abc", wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range restoreTests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			c := e.NewContext(httptest.NewRequest("POST", "/", nil), rec)
+			c.SetParamNames("id", "revision")
+			c.SetParamValues(id, tt.revision)
+			if err := server.handleRestoreRequestRevision(c); err != nil {
+				t.Fatalf("handleRestoreRequestRevision() error = %v", err)
+			}
+			if rec.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+		})
+	}
+
+	var restored models.Request
+	if err := db.GetRequest(request.ID, &restored); err != nil {
+		t.Fatalf("GetRequest() error = %v", err)
+	}
+	if restored.Method != "GET" || restored.Headers["Accept"] != "text/plain" {
+		t.Errorf("Expected original fields to be restored, got %+v", restored)
+	}
+	revisions, err := db.GetRequestRevisions(request.ID)
+	if err != nil || len(revisions) != 2 {
+		t.Errorf("Expected restore to record a new revision, got %d, %v", len(revisions), err)
+	}
+}
+
+func TestUpdateRequestPreservesOmittedFields(t *testing.T) {
+	server, db := setupTestServer(t)
+	e := echo.New()
+
+	request := &models.Request{Name: "Request", Description: "Docs", Method: "GET", URL: "https://example.com", Tags: []string{"smoke"}, Favorite: true}
+	if err := db.CreateRequest(request); err != nil {
+		t.Fatalf("CreateRequest() error = %v", err)
+	}
+	id := strconv.Itoa(request.ID)
+
+	update := func(t *testing.T, body string) {
+		t.Helper()
+		req := httptest.NewRequest("PUT", "/", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(id)
+		if err := server.handleUpdateRequestByID(c); err != nil {
+			t.Fatalf("handleUpdateRequestByID() error = %v", err)
+		}
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+	}
+
+	tests := []struct {
+		name            string
+		body            string
+		wantTags        []string
+		wantFavorite    bool
+		wantDescription string
+		wantRevisions   int
+	}{
+		{name: "Omitted fields are kept", body: `{"name": "Request", "method": "GET", "url": "https://example.com"}`, wantTags: []string{"smoke"}, wantFavorite: true, wantDescription: "Docs", wantRevisions: 0},
+		{name: "Explicit fields are applied", body: `{"name": "Request", "method": "GET", "url": "https://example.com", "tags": [], "favorite": false, "description": ""}`, wantTags: []string{}, wantFavorite: false, wantDescription: "", wantRevisions: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			update(t, tt.body)
+			var updated models.Request
+			if err := db.GetRequest(request.ID, &updated); err != nil {
+				t.Fatalf("GetRequest() error = %v", err)
+			}
+			if len(updated.Tags) != len(tt.wantTags) || (len(tt.wantTags) > 0 && updated.Tags[0] != tt.wantTags[0]) {
+				t.Errorf("Expected tags %v, got %v", tt.wantTags, updated.Tags)
+			}
+			if updated.Favorite != tt.wantFavorite || updated.Description != tt.wantDescription {
+				t.Errorf("Expected favorite %v and description %q, got %v and %q", tt.wantFavorite, tt.wantDescription, updated.Favorite, updated.Description)
+			}
+			revisions, err := db.GetRequestRevisions(request.ID)
+			if err != nil {
+				t.Fatalf("GetRequestRevisions() error = %v", err)
+			}
+			if len(revisions) != tt.wantRevisions {
+				t.Errorf("Expected %d revisions, got %d", tt.wantRevisions, len(revisions))
+			}
+		})
+	}
+}
+
+func TestUpdateRequestPreservesTypedFields(t *testing.T) {
+	server, db := setupTestServer(t)
+	e := echo.New()
+
+	tests := []struct {
+		name    string
+		request models.Request
+	}{
+		{name: "GraphQL request", request: models.Request{Name: "Query", Type: models.RequestTypeGraphQL, Method: "POST", URL: "https://example.com/graphql", Compression: "gzip", Protocol: models.ProtocolHTTP1, Auth: &models.Auth{Type: models.AuthTypeBearer, Token: "t0ken"}, GraphQL: &models.GraphQLBody{Query: "{ users { id } }"}}},
+		{name: "gRPC request", request: models.Request{Name: "Call", Type: models.RequestTypeGRPC, URL: "grpc://localhost:50051", GRPC: &models.GRPCRequest{Target: "localhost:50051", Method: "pkg.Service/Call", Message: "{}"}}},
+		{name: "WebSocket request", request: models.Request{Name: "Socket", Type: models.RequestTypeWebSocket, URL: "ws://localhost:8080/ws"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := tt.request
+			if err := db.CreateRequest(&request); err != nil {
+				t.Fatalf("CreateRequest() error = %v", err)
+			}
+			body := `{"id": ` + strconv.Itoa(request.ID) + `, "name": "Renamed", "folder_id": null, "method": "` + request.Method + `", "url": "` + request.URL + `", "headers": {}, "body": ""}`
+			req := httptest.NewRequest("PUT", "/", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("id")
+			c.SetParamValues(strconv.Itoa(request.ID))
+			if err := server.handleUpdateRequestByID(c); err != nil {
+				t.Fatalf("handleUpdateRequestByID() error = %v", err)
+			}
+			if rec.Code != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+			}
+
+			var updated models.Request
+			if err := db.GetRequest(request.ID, &updated); err != nil {
+				t.Fatalf("GetRequest() error = %v", err)
+			}
+			if updated.Name != "Renamed" {
+				t.Errorf("Expected name Renamed, got %q", updated.Name)
+			}
+			if updated.Type != request.Type || updated.Compression != request.Compression || updated.Protocol != request.Protocol {
+				t.Errorf("Expected type %q, compression %q and protocol %q, got %q, %q and %q", request.Type, request.Compression, request.Protocol, updated.Type, updated.Compression, updated.Protocol)
+			}
+			if !reflect.DeepEqual(updated.Auth, request.Auth) || !reflect.DeepEqual(updated.GraphQL, request.GraphQL) || !reflect.DeepEqual(updated.GRPC, request.GRPC) {
+				t.Errorf("Expected auth %+v, graphql %+v and grpc %+v, got %+v, %+v and %+v", request.Auth, request.GraphQL, request.GRPC, updated.Auth, updated.GraphQL, updated.GRPC)
+			}
+			revisions, err := db.GetRequestRevisions(request.ID)
+			if err != nil {
+				t.Fatalf("GetRequestRevisions() error = %v", err)
+			}
+			if len(revisions) != 1 {
+				t.Errorf("Expected 1 revision for the rename, got %d", len(revisions))
+			}
+		})
+	}
+}
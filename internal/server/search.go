@@ -0,0 +1,90 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hc/hc/internal/models"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	defaultSearchLimit = 50
+	maxSearchLimit     = 500
+)
+
+func (s *Server) handleSearch(c echo.Context) error {
+	query, err := parseSearchQuery(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error()))
+	}
+	results, err := s.db.Search(query)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to search"))
+	}
+	return c.JSON(http.StatusOK, results)
+}
+
+func parseSearchQuery(c echo.Context) (*models.SearchQuery, error) {
+	query := &models.SearchQuery{
+		Query:  strings.TrimSpace(c.QueryParam("q")),
+		Method: c.QueryParam("method"),
+		Limit:  defaultSearchLimit,
+	}
+	if query.Query == "" {
+		return nil, fmt.Errorf("search query is required")
+	}
+	if value := c.QueryParam("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit <= 0 || limit > maxSearchLimit {
+			return nil, fmt.Errorf("invalid limit")
+		}
+		query.Limit = limit
+	}
+	if value := c.QueryParam("folder_id"); value != "" {
+		folderID, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid folder ID")
+		}
+		query.FolderID = &folderID
+	}
+	if value := c.QueryParam("status"); value != "" {
+		status, err := strconv.Atoi(value)
+		if err != nil || status < 100 || status > 599 {
+			return nil, fmt.Errorf("invalid status")
+		}
+		query.StatusCode = status
+	}
+	if value := c.QueryParam("from"); value != "" {
+		from, _, err := parseSearchDate(value)
+		if err != nil {
+			return nil, err
+		}
+		query.From = &from
+	}
+	if value := c.QueryParam("to"); value != "" {
+		to, dateOnly, err := parseSearchDate(value)
+		if err != nil {
+			return nil, err
+		}
+		if dateOnly {
+			to = to.AddDate(0, 0, 1)
+		}
+		query.To = &to
+	}
+	return query, nil
+}
+
+func parseSearchDate(value string) (time.Time, bool, error) {
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed, false, nil
+	}
+	parsed, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid date %q", value)
+	}
+	return parsed, true, nil
+}
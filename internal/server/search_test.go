@@ -0,0 +1,60 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hc/hc/internal/models"
+	"github.com/labstack/echo/v4"
+)
+
+func TestHandleSearch(t *testing.T) {
+	server, db := setupTestServer(t)
+	e := echo.New()
+
+	request := &models.Request{Name: "Create invoice", Method: "POST", URL: "https://example.com/invoices"}
+	if err := db.CreateRequest(request); err != nil {
+		t.Fatalf("CreateRequest() error = %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		query       string
+		wantStatus  int
+		wantResults int
+	}{
+		{name: "Search requests", query: "?q=invoice", wantStatus: http.StatusOK, wantResults: 1},
+		{name: "Search with filters", query: "?q=invoice&method=POST&from=2000-01-01&to=2999-01-01", wantStatus: http.StatusOK, wantResults: 1},
+		{name: "Filter excludes results", query: "?q=invoice&status=200", wantStatus: http.StatusOK, wantResults: 0},
+		{name: "Missing query", query: "", wantStatus: http.StatusBadRequest},
+		{name: "Invalid limit", query: "?q=invoice&limit=0", wantStatus: http.StatusBadRequest},
+		{name: "Invalid folder ID", query: "?q=invoice&folder_id=abc", wantStatus: http.StatusBadRequest},
+		{name: "Invalid status", query: "?q=invoice&status=999", wantStatus: http.StatusBadRequest},
+		{name: "Invalid date", query: "?q=invoice&from=yesterday", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			c := e.NewContext(httptest.NewRequest("GET", "/api/search"+tt.query, nil), rec)
+			if err := server.handleSearch(c); err != nil {
+				t.Fatalf("handleSearch() error = %v", err)
+			}
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var results []models.SearchResult
+			if err := json.Unmarshal(rec.Body.Bytes(), &results); err != nil {
+				t.Fatalf("Failed to unmarshal response: %v", err)
+			}
+			if len(results) != tt.wantResults {
+				t.Errorf("Expected %d results, got %d", tt.wantResults, len(results))
+			}
+		})
+	}
+}
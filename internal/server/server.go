@@ -1,6 +1,8 @@
 package server
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"io/fs"
@@ -9,11 +11,13 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/gorilla/websocket"
 	"github.com/hc/hc/internal/logger"
 	customMiddleware "github.com/hc/hc/internal/middleware"
 	"github.com/hc/hc/internal/models"
 	"github.com/hc/hc/internal/proxy"
 	"github.com/hc/hc/internal/storage"
+	"github.com/hc/hc/internal/wssession"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 )
@@ -22,19 +26,36 @@ type Server struct {
 	port        int
 	db          *storage.DB
 	proxyClient *proxy.Client
+	wsManager   *wssession.Manager
+	executions  *executionRegistry
+	mock        *mockController
+	upgrader    websocket.Upgrader
 	frontendFS  fs.FS
 }
 
 func New(port int, db *storage.DB, frontendFS fs.FS) *Server {
-	return &Server{
+	s := &Server{
 		port:        port,
 		db:          db,
 		proxyClient: proxy.NewClient(),
+		wsManager:   wssession.NewManager(db),
+		executions:  newExecutionRegistry(),
+		mock:        newMockController(),
 		frontendFS:  frontendFS,
 	}
+	s.loadSettings()
+	s.purgeTrash()
+	s.purgeHistory()
+	return s
 }
 
 func (s *Server) Start() error {
+	e := s.router()
+	logger.Get().Info("Starting server", slog.String("address", fmt.Sprintf(":%d", s.port)))
+	return e.Start(fmt.Sprintf(":%d", s.port))
+}
+
+func (s *Server) router() *echo.Echo {
 	e := echo.New()
 	e.HideBanner = true
 	e.HidePort = true
@@ -51,19 +72,68 @@ func (s *Server) Start() error {
 	}))
 	api := e.Group("/api")
 	api.POST("/request", s.handleProxyRequest)
+	api.POST("/request/stream", s.handleStreamRequest)
+	api.POST("/request/raw", s.handleRawRequest)
+	api.DELETE("/executions/:id", s.handleCancelExecution)
+	api.GET("/methods", s.handleGetMethods)
 	api.GET("/requests", s.handleGetRequests)
 	api.POST("/requests", s.handleCreateRequest)
 	api.GET("/requests/:id", s.handleGetRequestByID)
 	api.PUT("/requests/:id", s.handleUpdateRequestByID)
 	api.DELETE("/requests/:id", s.handleDeleteRequestByID)
+	api.GET("/requests/:id/effective", s.handleGetEffectiveRequest)
+	api.POST("/requests/:id/favorite", s.handleAddFavorite)
+	api.DELETE("/requests/:id/favorite", s.handleRemoveFavorite)
+	api.GET("/tags", s.handleGetTags)
+	api.GET("/requests/:id/examples", s.handleGetExamples)
+	api.POST("/requests/:id/examples", s.handleCreateExample)
+	api.PUT("/requests/:id/examples/:example", s.handleUpdateExample)
+	api.DELETE("/requests/:id/examples/:example", s.handleDeleteExample)
+	api.GET("/requests/:id/revisions", s.handleGetRequestRevisions)
+	api.GET("/requests/:id/revisions/diff", s.handleDiffRequestRevisions)
+	api.POST("/requests/:id/revisions/:revision/restore", s.handleRestoreRequestRevision)
 	api.GET("/folders", s.handleGetFolders)
 	api.POST("/folders", s.handleCreateFolder)
 	api.GET("/folders/:id", s.handleGetFolderByID)
 	api.PUT("/folders/:id", s.handleUpdateFolderByID)
 	api.DELETE("/folders/:id", s.handleDeleteFolderByID)
+	api.POST("/folders/:id/move", s.handleMoveFolder)
+	api.POST("/requests/:id/move", s.handleMoveRequest)
+	api.POST("/folders/:id/clone", s.handleCloneFolder)
+	api.POST("/requests/:id/clone", s.handleCloneRequest)
+	api.GET("/tree", s.handleGetTree)
+	api.GET("/trash", s.handleGetTrash)
+	api.DELETE("/trash", s.handleEmptyTrash)
+	api.POST("/trash/folders/:id/restore", s.handleRestoreFolder)
+	api.POST("/trash/requests/:id/restore", s.handleRestoreRequest)
+	api.GET("/websocket/sessions", s.handleGetWebSocketSessions)
+	api.POST("/websocket/sessions", s.handleCreateWebSocketSession)
+	api.GET("/websocket/sessions/:id", s.handleGetWebSocketSessionByID)
+	api.DELETE("/websocket/sessions/:id", s.handleDeleteWebSocketSession)
+	api.POST("/websocket/sessions/:id/close", s.handleCloseWebSocketSession)
+	api.GET("/websocket/sessions/:id/messages", s.handleGetWebSocketMessages)
+	api.GET("/websocket/sessions/:id/stream", s.handleWebSocketStream)
+	api.GET("/graphql/schema", s.handleGetGraphQLSchema)
+	api.POST("/graphql/schema", s.handleIntrospectGraphQL)
+	api.POST("/graphql/validate", s.handleValidateGraphQL)
+	api.GET("/grpc/protos", s.handleGetGRPCProtoSets)
+	api.POST("/grpc/protos", s.handleCreateGRPCProtoSet)
+	api.GET("/grpc/protos/:id", s.handleGetGRPCProtoSetByID)
+	api.DELETE("/grpc/protos/:id", s.handleDeleteGRPCProtoSet)
+	api.POST("/grpc/services", s.handleGetGRPCServices)
+	api.POST("/grpc/invoke", s.handleInvokeGRPC)
+	api.GET("/history", s.handleGetHistory)
+	api.GET("/history/:id", s.handleGetHistoryEntryByID)
+	api.GET("/search", s.handleSearch)
+	api.GET("/docs", s.handleGetDocs)
+	api.GET("/mock", s.handleGetMock)
+	api.POST("/mock", s.handleStartMock)
+	api.DELETE("/mock", s.handleStopMock)
+	api.GET("/mock/log", s.handleGetMockLog)
+	api.GET("/settings", s.handleGetSettings)
+	api.PUT("/settings", s.handleUpdateSettings)
 	e.GET("/*", s.handleStatic)
-	logger.Get().Info("Starting server", slog.String("address", fmt.Sprintf(":%d", s.port)))
-	return e.Start(fmt.Sprintf(":%d", s.port))
+	return e
 }
 
 func (s *Server) handleProxyRequest(c echo.Context) error {
@@ -72,25 +142,112 @@ func (s *Server) handleProxyRequest(c echo.Context) error {
 		logger.Get().Error("Failed to bind proxy request", slog.String("error", err.Error()))
 		return c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body"))
 	}
+	if proxyReq.FolderID != nil {
+		if err := s.inheritFolder(&proxyReq); err != nil {
+			return c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error()))
+		}
+	}
+	if err := validateProxyRequest(&proxyReq); err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error()))
+	}
+	ctx, executionID, done, err := s.executions.start(c.Request().Context(), proxyReq.ExecutionID)
+	if err != nil {
+		return c.JSON(http.StatusConflict, models.NewErrorResponse(err.Error()))
+	}
+	defer done()
+	c.Response().Header().Set("X-Execution-ID", executionID)
+	logger.Get().Info("Proxying request",
+		slog.String("execution_id", executionID),
+		slog.String("method", proxyReq.Method),
+		slog.String("url", proxyReq.URL))
+	resp, err := s.proxyClient.ProxyRequestContext(ctx, &proxyReq)
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			logger.Get().Info("Proxy request cancelled", slog.String("execution_id", executionID))
+		} else {
+			logger.Get().Error("Proxy request failed", slog.String("error", err.Error()))
+		}
+		s.recordFailedHistory(&proxyReq, err)
+		return c.JSON(executionErrorResponse(err))
+	}
+	s.recordHistory(&proxyReq, resp)
+	return c.JSON(http.StatusOK, resp)
+}
+
+func executionErrorResponse(err error) (int, *models.ErrorResponse) {
+	var reqErr *proxy.RequestError
+	if !errors.As(err, &reqErr) {
+		return http.StatusInternalServerError, models.NewErrorResponse("Failed to execute request")
+	}
+	status := http.StatusBadGateway
+	resp := models.NewErrorResponseWithCode(reqErr.Code, reqErr.Explanation)
+	switch reqErr.Code {
+	case proxy.ErrorCodeCancelled:
+		status = StatusClientClosedRequest
+		resp.Messages = []string{"Request cancelled"}
+	case proxy.ErrorCodeTimeout:
+		status = http.StatusGatewayTimeout
+	}
+	resp.Detail = reqErr.Error()
+	resp.Timing = reqErr.Timing
+	return status, resp
+}
+
+func (s *Server) handleGetMethods(c echo.Context) error {
+	return c.JSON(http.StatusOK, proxy.KnownMethods)
+}
+
+func validateProxyRequest(proxyReq *proxy.ProxyRequest) error {
 	if err := proxy.ValidateURL(proxyReq.URL); err != nil {
 		logger.Get().Error("Invalid URL", slog.String("url", proxyReq.URL), slog.String("error", err.Error()))
-		return c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error()))
+		return err
 	}
 	if err := proxy.ValidateMethod(proxyReq.Method); err != nil {
 		logger.Get().Error("Invalid HTTP method", slog.String("method", proxyReq.Method), slog.String("error", err.Error()))
-		return c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error()))
+		return err
 	}
-	logger.Get().Info("Proxying request", slog.String("method", proxyReq.Method), slog.String("url", proxyReq.URL))
-	resp, err := s.proxyClient.ProxyRequest(&proxyReq)
-	if err != nil {
-		logger.Get().Error("Proxy request failed", slog.String("error", err.Error()))
-		return c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to execute request"))
+	if err := proxy.ValidateCompression(proxyReq.Compression); err != nil {
+		logger.Get().Error("Invalid compression", slog.String("compression", proxyReq.Compression), slog.String("error", err.Error()))
+		return err
 	}
-	return c.JSON(http.StatusOK, resp)
+	if err := proxy.ValidateProtocol(proxyReq.Protocol, proxyReq.URL); err != nil {
+		logger.Get().Error("Invalid protocol", slog.String("protocol", proxyReq.Protocol), slog.String("error", err.Error()))
+		return err
+	}
+	if err := proxy.ValidateAuth(proxyReq.Auth); err != nil {
+		logger.Get().Error("Invalid auth", slog.String("error", err.Error()))
+		return err
+	}
+	if proxyReq.GraphQL != nil {
+		if err := proxy.ValidateGraphQL(proxyReq.GraphQL); err != nil {
+			logger.Get().Error("Invalid GraphQL body", slog.String("error", err.Error()))
+			return err
+		}
+	}
+	if proxyReq.Proxy != nil {
+		if err := proxy.ValidateProxySettings(proxyReq.Proxy); err != nil {
+			logger.Get().Error("Invalid proxy settings", slog.String("error", err.Error()))
+			return err
+		}
+	}
+	if err := proxy.ValidateHostOverrides(proxyReq.Resolve); err != nil {
+		logger.Get().Error("Invalid host overrides", slog.String("error", err.Error()))
+		return err
+	}
+	return nil
 }
 
 func (s *Server) handleGetRequests(c echo.Context) error {
-	requests, err := s.db.GetRequests()
+	filter, filtered, err := parseRequestFilter(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error()))
+	}
+	var requests []models.Request
+	if filtered {
+		requests, err = s.db.FilterRequests(filter)
+	} else {
+		requests, err = s.db.GetRequests()
+	}
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to get requests"))
 	}
@@ -105,6 +262,12 @@ func (s *Server) handleCreateRequest(c echo.Context) error {
 	if err := c.Bind(&request); err != nil {
 		return c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body"))
 	}
+	if err := validateRequest(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error()))
+	}
+	if err := s.db.CheckRequestFolder(request.FolderID); err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error()))
+	}
 	if err := s.db.CreateRequest(&request); err != nil {
 		return c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to create request"))
 	}
@@ -128,17 +291,70 @@ func (s *Server) handleUpdateRequestByID(c echo.Context) error {
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request ID"))
 	}
-	var request models.Request
-	if err := c.Bind(&request); err != nil {
+	var update requestUpdate
+	if err := c.Bind(&update); err != nil {
 		return c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body"))
 	}
+	var current models.Request
+	if err := s.db.GetRequest(id, &current); err != nil {
+		return c.JSON(http.StatusNotFound, models.NewErrorResponse("Request not found"))
+	}
+	request := update.merge(&current)
+	if err := validateRequest(request); err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error()))
+	}
+	if err := s.db.CheckRequestFolder(request.FolderID); err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error()))
+	}
 	request.ID = id
-	if err := s.db.UpdateRequest(&request); err != nil {
+	if err := s.db.UpdateRequest(request); err != nil {
 		return c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to update request"))
 	}
 	return c.JSON(http.StatusOK, request)
 }
 
+type requestUpdate struct {
+	models.Request
+	Description *string             `json:"description"`
+	Type        *string             `json:"type"`
+	Compression *string             `json:"compression"`
+	Protocol    *string             `json:"protocol"`
+	Auth        *models.Auth        `json:"auth"`
+	GraphQL     *models.GraphQLBody `json:"graphql"`
+	GRPC        *models.GRPCRequest `json:"grpc"`
+	Tags        *[]string           `json:"tags"`
+	Favorite    *bool               `json:"favorite"`
+}
+
+func (u *requestUpdate) merge(current *models.Request) *models.Request {
+	request := u.Request
+	request.Description = mergeField(u.Description, current.Description)
+	request.Type = mergeField(u.Type, current.Type)
+	request.Compression = mergeField(u.Compression, current.Compression)
+	request.Protocol = mergeField(u.Protocol, current.Protocol)
+	request.Auth = mergePointer(u.Auth, current.Auth)
+	request.GraphQL = mergePointer(u.GraphQL, current.GraphQL)
+	request.GRPC = mergePointer(u.GRPC, current.GRPC)
+	request.Tags = mergeField(u.Tags, current.Tags)
+	request.Favorite = mergeField(u.Favorite, current.Favorite)
+	request.Examples = current.Examples
+	return &request
+}
+
+func mergeField[T any](value *T, current T) T {
+	if value != nil {
+		return *value
+	}
+	return current
+}
+
+func mergePointer[T any](value, current *T) *T {
+	if value != nil {
+		return value
+	}
+	return current
+}
+
 func (s *Server) handleDeleteRequestByID(c echo.Context) error {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
@@ -166,6 +382,12 @@ func (s *Server) handleCreateFolder(c echo.Context) error {
 	if err := c.Bind(&folder); err != nil {
 		return c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body"))
 	}
+	if err := validateFolder(&folder); err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error()))
+	}
+	if err := s.db.CheckFolderParent(0, folder.ParentID); err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error()))
+	}
 	if err := s.db.CreateFolder(&folder); err != nil {
 		return c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to create folder"))
 	}
@@ -189,17 +411,49 @@ func (s *Server) handleUpdateFolderByID(c echo.Context) error {
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid folder ID"))
 	}
-	var folder models.Folder
-	if err := c.Bind(&folder); err != nil {
+	var update folderUpdate
+	if err := c.Bind(&update); err != nil {
 		return c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body"))
 	}
+	var current models.Folder
+	if err := s.db.GetFolder(id, &current); err != nil {
+		return c.JSON(http.StatusNotFound, models.NewErrorResponse("Folder not found"))
+	}
+	folder := update.merge(&current)
+	if err := validateFolder(folder); err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error()))
+	}
 	folder.ID = id
-	if err := s.db.UpdateFolder(&folder); err != nil {
+	if err := s.db.UpdateFolder(folder); err != nil {
+		if isFolderParentError(err) {
+			return c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error()))
+		}
 		return c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to update folder"))
 	}
 	return c.JSON(http.StatusOK, folder)
 }
 
+type folderUpdate struct {
+	models.Folder
+	Description   *string            `json:"description"`
+	Headers       *map[string]string `json:"headers"`
+	Auth          *models.Auth       `json:"auth"`
+	BaseURL       *string            `json:"base_url"`
+	Variables     *map[string]string `json:"variables"`
+	HostOverrides *map[string]string `json:"host_overrides"`
+}
+
+func (u *folderUpdate) merge(current *models.Folder) *models.Folder {
+	folder := u.Folder
+	folder.Description = mergeField(u.Description, current.Description)
+	folder.Headers = mergeField(u.Headers, current.Headers)
+	folder.Auth = mergePointer(u.Auth, current.Auth)
+	folder.BaseURL = mergeField(u.BaseURL, current.BaseURL)
+	folder.Variables = mergeField(u.Variables, current.Variables)
+	folder.HostOverrides = mergeField(u.HostOverrides, current.HostOverrides)
+	return &folder
+}
+
 func (s *Server) handleDeleteFolderByID(c echo.Context) error {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
@@ -211,6 +465,33 @@ func (s *Server) handleDeleteFolderByID(c echo.Context) error {
 	return c.NoContent(http.StatusNoContent)
 }
 
+func validateRequest(request *models.Request) error {
+	if err := validateRequestType(request.Type); err != nil {
+		return err
+	}
+	if err := proxy.ValidateCompression(request.Compression); err != nil {
+		return err
+	}
+	if err := proxy.ValidateProtocol(request.Protocol, ""); err != nil {
+		return err
+	}
+	if err := validateTags(request.Tags); err != nil {
+		return err
+	}
+	if err := validateExamples(request.Examples); err != nil {
+		return err
+	}
+	return proxy.ValidateAuth(request.Auth)
+}
+
+func validateRequestType(requestType string) error {
+	switch requestType {
+	case "", models.RequestTypeHTTP, models.RequestTypeWebSocket, models.RequestTypeGraphQL, models.RequestTypeGRPC:
+		return nil
+	}
+	return errors.New("invalid request type: " + requestType)
+}
+
 func (s *Server) handleStatic(c echo.Context) error {
 	path := c.Request().URL.Path
 	if path == "/" {
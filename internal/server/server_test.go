@@ -122,6 +122,8 @@ func setupTestServer(t *testing.T) (*Server, *storage.DB) {
 
 func TestHandleProxyRequest(t *testing.T) {
 	server, _ := setupTestServer(t)
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer target.Close()
 
 	tests := []struct {
 		name       string
@@ -148,15 +150,47 @@ func TestHandleProxyRequest(t *testing.T) {
 			wantError:  true,
 		},
 		{
-			name:   "Invalid HTTP method in request",
+			name:   "Custom HTTP method token in request",
 			method: "POST",
 			body: map[string]interface{}{
 				"method": "INVALID",
+				"url":    target.URL,
+			},
+			wantStatus: http.StatusOK,
+			wantError:  false,
+		},
+		{
+			name:   "Invalid HTTP method in request",
+			method: "POST",
+			body: map[string]interface{}{
+				"method": "BAD METHOD",
 				"url":    "https://example.com",
 			},
 			wantStatus: http.StatusBadRequest,
 			wantError:  true,
 		},
+		{
+			name:   "Invalid compression",
+			method: "POST",
+			body: map[string]interface{}{
+				"method":      "POST",
+				"url":         "https://example.com",
+				"compression": "lzma",
+			},
+			wantStatus: http.StatusBadRequest,
+			wantError:  true,
+		},
+		{
+			name:   "HTTP/2 over cleartext URL",
+			method: "POST",
+			body: map[string]interface{}{
+				"method":   "GET",
+				"url":      "http://example.com",
+				"protocol": "h2",
+			},
+			wantStatus: http.StatusBadRequest,
+			wantError:  true,
+		},
 		{
 			name:   "Invalid URL",
 			method: "POST",
@@ -198,6 +232,10 @@ func TestHandleProxyRequest(t *testing.T) {
 				return
 			}
 
+			if !tt.wantError && rec.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+
 			if tt.wantError {
 				if rec.Code != tt.wantStatus {
 					t.Errorf("Expected status %d, got %d", tt.wantStatus, rec.Code)
@@ -577,6 +615,30 @@ func TestErrorResponses(t *testing.T) {
 			wantStatus: http.StatusBadRequest,
 			wantMsg:    "Invalid request body",
 		},
+		{
+			name:    "Invalid request type",
+			handler: server.handleCreateRequest,
+			setup: func() echo.Context {
+				req := httptest.NewRequest("POST", "/api/requests", strings.NewReader(`{"name": "x", "type": "ftp", "method": "GET", "url": "https://example.com"}`))
+				req.Header.Set("Content-Type", "application/json")
+				rec := httptest.NewRecorder()
+				return e.NewContext(req, rec)
+			},
+			wantStatus: http.StatusBadRequest,
+			wantMsg:    "invalid request type: ftp",
+		},
+		{
+			name:    "Invalid request compression",
+			handler: server.handleCreateRequest,
+			setup: func() echo.Context {
+				req := httptest.NewRequest("POST", "/api/requests", strings.NewReader(`{"name": "x", "method": "POST", "url": "https://example.com", "compression": "lzma"}`))
+				req.Header.Set("Content-Type", "application/json")
+				rec := httptest.NewRecorder()
+				return e.NewContext(req, rec)
+			},
+			wantStatus: http.StatusBadRequest,
+			wantMsg:    "unsupported compression: lzma",
+		},
 	}
 
 	for _, tt := range tests {
@@ -606,3 +668,30 @@ func TestErrorResponses(t *testing.T) {
 		})
 	}
 }
+
+func TestHandleGetMethods(t *testing.T) {
+	server, _ := setupTestServer(t)
+	e := echo.New()
+
+	req := httptest.NewRequest("GET", "/api/methods", nil)
+	rec := httptest.NewRecorder()
+	if err := server.handleGetMethods(e.NewContext(req, rec)); err != nil {
+		t.Fatalf("handleGetMethods() error = %v", err)
+	}
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	var methods []models.HTTPMethod
+	if err := json.Unmarshal(rec.Body.Bytes(), &methods); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	var propfind *models.HTTPMethod
+	for i := range methods {
+		if methods[i].Name == "PROPFIND" {
+			propfind = &methods[i]
+		}
+	}
+	if propfind == nil || !propfind.Safe || !propfind.BodyAllowed {
+		t.Errorf("Expected PROPFIND in method catalog, got %+v", propfind)
+	}
+}
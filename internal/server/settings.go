@@ -0,0 +1,72 @@
+package server
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/hc/hc/internal/logger"
+	"github.com/hc/hc/internal/models"
+	"github.com/hc/hc/internal/proxy"
+	"github.com/labstack/echo/v4"
+)
+
+func (s *Server) loadSettings() {
+	settings, err := s.db.GetSettings()
+	if err != nil {
+		logger.Get().Warn("Failed to load settings, using defaults", slog.String("error", err.Error()))
+		return
+	}
+	if err := s.proxyClient.ApplySettings(settings); err != nil {
+		logger.Get().Warn("Failed to apply settings, using defaults", slog.String("error", err.Error()))
+	}
+}
+
+func (s *Server) handleGetSettings(c echo.Context) error {
+	settings, err := s.db.GetSettings()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to get settings"))
+	}
+	return c.JSON(http.StatusOK, maskSettings(settings))
+}
+
+func (s *Server) handleUpdateSettings(c echo.Context) error {
+	var settings models.Settings
+	if err := c.Bind(&settings); err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body"))
+	}
+	if err := proxy.ValidateProxySettings(&settings.Proxy); err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error()))
+	}
+	if err := proxy.ValidateHostOverrides(settings.HostOverrides); err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error()))
+	}
+	if settings.TrashRetentionDays < 0 {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse("trash retention days must not be negative"))
+	}
+	if settings.HistoryRetentionDays < 0 {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse("history retention days must not be negative"))
+	}
+	if settings.Proxy.Password == "" && (settings.Proxy.PasswordSet == nil || *settings.Proxy.PasswordSet) {
+		current, err := s.db.GetSettings()
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to get settings"))
+		}
+		settings.Proxy.Password = current.Proxy.Password
+	}
+	settings.Proxy.PasswordSet = nil
+	if err := s.proxyClient.ApplySettings(&settings); err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse("Failed to apply settings: "+err.Error()))
+	}
+	if err := s.db.UpdateSettings(&settings); err != nil {
+		return c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to update settings"))
+	}
+	return c.JSON(http.StatusOK, maskSettings(&settings))
+}
+
+func maskSettings(settings *models.Settings) *models.Settings {
+	masked := *settings
+	passwordSet := settings.Proxy.Password != ""
+	masked.Proxy.Password = ""
+	masked.Proxy.PasswordSet = &passwordSet
+	return &masked
+}
@@ -0,0 +1,155 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hc/hc/internal/models"
+	"github.com/labstack/echo/v4"
+)
+
+func TestSettingsHandlers(t *testing.T) {
+	server, db := setupTestServer(t)
+	e := echo.New()
+
+	t.Run("GetDefaultSettings", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/settings", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		if err := server.handleGetSettings(c); err != nil {
+			t.Fatalf("handleGetSettings() error = %v", err)
+		}
+
+		if rec.Code != http.StatusOK {
+			t.Errorf("Expected status %d, got %d", http.StatusOK, rec.Code)
+		}
+	})
+
+	t.Run("UpdateSettings", func(t *testing.T) {
+		reqBody := `{"proxy": {"mode": "manual", "url": "socks5://127.0.0.1:1080", "no_proxy": ["localhost"]}}`
+		req := httptest.NewRequest("PUT", "/api/settings", strings.NewReader(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		if err := server.handleUpdateSettings(c); err != nil {
+			t.Fatalf("handleUpdateSettings() error = %v", err)
+		}
+
+		if rec.Code != http.StatusOK {
+			t.Errorf("Expected status %d, got %d", http.StatusOK, rec.Code)
+		}
+
+		settings, err := db.GetSettings()
+		if err != nil {
+			t.Fatalf("Failed to get settings: %v", err)
+		}
+		if settings.Proxy.URL != "socks5://127.0.0.1:1080" {
+			t.Errorf("Expected proxy URL to be saved, got %+v", settings.Proxy)
+		}
+	})
+
+	t.Run("UpdateSettingsNegativeTrashRetention", func(t *testing.T) {
+		req := httptest.NewRequest("PUT", "/api/settings", strings.NewReader(`{"trash_retention_days": -1}`))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		if err := server.handleUpdateSettings(c); err != nil {
+			t.Fatalf("handleUpdateSettings() error = %v", err)
+		}
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d, got %d", http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("UpdateSettingsNegativeHistoryRetention", func(t *testing.T) {
+		req := httptest.NewRequest("PUT", "/api/settings", strings.NewReader(`{"history_retention_days": -1}`))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		if err := server.handleUpdateSettings(c); err != nil {
+			t.Fatalf("handleUpdateSettings() error = %v", err)
+		}
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d, got %d", http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("UpdateSettingsInvalidProxy", func(t *testing.T) {
+		reqBody := `{"proxy": {"mode": "manual", "url": "ftp://proxy.local"}}`
+		req := httptest.NewRequest("PUT", "/api/settings", strings.NewReader(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		if err := server.handleUpdateSettings(c); err != nil {
+			t.Fatalf("handleUpdateSettings() error = %v", err)
+		}
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d, got %d", http.StatusBadRequest, rec.Code)
+		}
+
+		var errResp models.ErrorResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &errResp); err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+		if len(errResp.Messages) == 0 {
+			t.Error("Expected error messages in response")
+		}
+	})
+
+	t.Run("ProxyPasswordIsMasked", func(t *testing.T) {
+		tests := []struct {
+			name         string
+			body         string
+			wantPassword string
+		}{
+			{name: "Set password", body: `{"proxy": {"mode": "manual", "url": "http://proxy.local:3128", "username": "user", "password": "secret"}}`, wantPassword: "secret"},
+			{name: "Omitted password is kept", body: `{"proxy": {"mode": "manual", "url": "http://proxy.local:3128", "username": "user"}}`, wantPassword: "secret"},
+			{name: "Masked password is kept", body: `{"proxy": {"mode": "manual", "url": "http://proxy.local:3128", "username": "user", "password": "", "password_set": true}}`, wantPassword: "secret"},
+			{name: "Cleared password", body: `{"proxy": {"mode": "manual", "url": "http://proxy.local:3128", "username": "user", "password": "", "password_set": false}}`, wantPassword: ""},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				req := httptest.NewRequest("PUT", "/api/settings", strings.NewReader(tt.body))
+				req.Header.Set("Content-Type", "application/json")
+				rec := httptest.NewRecorder()
+				if err := server.handleUpdateSettings(e.NewContext(req, rec)); err != nil {
+					t.Fatalf("handleUpdateSettings() error = %v", err)
+				}
+				if rec.Code != http.StatusOK {
+					t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+				}
+				if strings.Contains(rec.Body.String(), "secret") {
+					t.Errorf("Expected password to be masked, got %s", rec.Body.String())
+				}
+				settings, err := db.GetSettings()
+				if err != nil {
+					t.Fatalf("Failed to get settings: %v", err)
+				}
+				if settings.Proxy.Password != tt.wantPassword {
+					t.Errorf("Expected stored password %q, got %q", tt.wantPassword, settings.Proxy.Password)
+				}
+			})
+		}
+
+		rec := httptest.NewRecorder()
+		if err := server.handleGetSettings(e.NewContext(httptest.NewRequest("GET", "/api/settings", nil), rec)); err != nil {
+			t.Fatalf("handleGetSettings() error = %v", err)
+		}
+		var settings models.Settings
+		json.Unmarshal(rec.Body.Bytes(), &settings)
+		if settings.Proxy.Password != "" || settings.Proxy.PasswordSet == nil || *settings.Proxy.PasswordSet {
+			t.Errorf("Expected masked settings without a password, got %+v", settings.Proxy)
+		}
+	})
+}
@@ -0,0 +1,90 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/hc/hc/internal/logger"
+	"github.com/hc/hc/internal/models"
+	"github.com/hc/hc/internal/proxy"
+	"github.com/labstack/echo/v4"
+)
+
+func (s *Server) handleStreamRequest(c echo.Context) error {
+	var proxyReq proxy.ProxyRequest
+	if err := c.Bind(&proxyReq); err != nil {
+		logger.Get().Error("Failed to bind stream request", slog.String("error", err.Error()))
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body"))
+	}
+	if proxyReq.FolderID != nil {
+		if err := s.inheritFolder(&proxyReq); err != nil {
+			return c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error()))
+		}
+	}
+	if err := validateProxyRequest(&proxyReq); err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error()))
+	}
+	ctx, executionID, done, err := s.executions.start(c.Request().Context(), proxyReq.ExecutionID)
+	if err != nil {
+		return c.JSON(http.StatusConflict, models.NewErrorResponse(err.Error()))
+	}
+	defer done()
+	logger.Get().Info("Streaming request",
+		slog.String("execution_id", executionID),
+		slog.String("method", proxyReq.Method),
+		slog.String("url", proxyReq.URL))
+	res := c.Response()
+	res.Header().Set("X-Execution-ID", executionID)
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set("Cache-Control", "no-cache")
+	res.Header().Set("Connection", "keep-alive")
+	res.WriteHeader(http.StatusOK)
+	res.Flush()
+	history := newStreamHistory(&proxyReq)
+	err = s.proxyClient.StreamRequest(ctx, &proxyReq, func(event models.StreamEvent) error {
+		history.observe(event)
+		return writeSSE(res, event)
+	})
+	s.saveHistory(history.finish(ctx, err))
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, context.Canceled) || ctx.Err() != nil {
+		logger.Get().Info("Stream request cancelled", slog.String("execution_id", executionID))
+		if c.Request().Context().Err() == nil {
+			event := models.StreamEvent{Type: models.StreamEventCancelled, Timestamp: time.Now().UTC()}
+			if writeErr := writeSSE(res, event); writeErr != nil {
+				logger.Get().Warn("Failed to write stream cancellation", slog.String("error", writeErr.Error()))
+			}
+		}
+		return nil
+	}
+	logger.Get().Error("Stream request failed", slog.String("error", err.Error()))
+	event := models.StreamEvent{Type: models.StreamEventError, Data: err.Error(), Timestamp: time.Now().UTC()}
+	var reqErr *proxy.RequestError
+	if errors.As(err, &reqErr) {
+		event.Code = reqErr.Code
+		event.Data = reqErr.Explanation + ": " + reqErr.Error()
+	}
+	if writeErr := writeSSE(res, event); writeErr != nil {
+		logger.Get().Warn("Failed to write stream error", slog.String("error", writeErr.Error()))
+	}
+	return nil
+}
+
+func writeSSE(res *echo.Response, event models.StreamEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(res, "event: %s\ndata: %s\n\n", event.Type, data); err != nil {
+		return err
+	}
+	res.Flush()
+	return nil
+}
@@ -0,0 +1,158 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hc/hc/internal/models"
+	"github.com/labstack/echo/v4"
+)
+
+func TestHandleStreamRequest(t *testing.T) {
+	server, db := setupTestServer(t)
+	e := echo.New()
+
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "event: message\ndata: hello\n\n")
+	}))
+	defer backend.Close()
+
+	body, _ := json.Marshal(map[string]interface{}{"method": "GET", "url": backend.URL})
+	req := httptest.NewRequest("POST", "/api/request/stream", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if err := server.handleStreamRequest(c); err != nil {
+		t.Fatalf("handleStreamRequest() error = %v", err)
+	}
+
+	if rec.Header().Get("Content-Type") != "text/event-stream" {
+		t.Errorf("Expected text/event-stream, got %s", rec.Header().Get("Content-Type"))
+	}
+
+	// Collect the relayed event types in order
+	var types []string
+	var sse models.StreamEvent
+	for _, block := range strings.Split(strings.TrimSpace(rec.Body.String()), "\n\n") {
+		lines := strings.SplitN(block, "\n", 2)
+		types = append(types, strings.TrimPrefix(lines[0], "event: "))
+		var event models.StreamEvent
+		if err := json.Unmarshal([]byte(strings.TrimPrefix(lines[1], "data: ")), &event); err != nil {
+			t.Fatalf("Failed to decode event %q: %v", block, err)
+		}
+		if event.Type == models.StreamEventSSE {
+			sse = event
+		}
+	}
+	want := []string{models.StreamEventHeaders, models.StreamEventSSE, models.StreamEventDone}
+	if strings.Join(types, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected events %v, got %v", want, types)
+	}
+	if sse.Event != "message" || sse.Data != "hello" {
+		t.Errorf("Unexpected SSE event: %+v", sse)
+	}
+
+	entries, err := db.GetHistory(1)
+	if err != nil {
+		t.Fatalf("GetHistory() error = %v", err)
+	}
+	if len(entries) != 1 || !entries[0].Streamed || entries[0].StatusCode != http.StatusOK || entries[0].ErrorCode != "" {
+		t.Fatalf("Expected streamed history entry, got %+v", entries)
+	}
+	if entries[0].ResponseBody != "event: message\ndata: hello\n\n" || entries[0].Metrics == nil || entries[0].Metrics.BodySize == 0 {
+		t.Errorf("Expected recorded stream body and metrics, got %q %+v", entries[0].ResponseBody, entries[0].Metrics)
+	}
+}
+
+func TestHandleStreamRequestErrors(t *testing.T) {
+	server, _ := setupTestServer(t)
+	e := echo.New()
+
+	t.Run("InvalidURL", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/api/request/stream", strings.NewReader(`{"method": "GET", "url": "not-a-url"}`))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		if err := server.handleStreamRequest(c); err != nil {
+			t.Fatalf("handleStreamRequest() error = %v", err)
+		}
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d, got %d", http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("ConnectionFailure", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/api/request/stream", strings.NewReader(`{"method": "GET", "url": "http://127.0.0.1:1"}`))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		if err := server.handleStreamRequest(c); err != nil {
+			t.Fatalf("handleStreamRequest() error = %v", err)
+		}
+		if !strings.HasPrefix(rec.Body.String(), "event: error\n") {
+			t.Errorf("Expected error event, got %q", rec.Body.String())
+		}
+	})
+}
+
+func TestHandleStreamRequestCancel(t *testing.T) {
+	server, db := setupTestServer(t)
+	e := echo.New()
+
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer backend.Close()
+
+	reqBody := `{"execution_id": "stream-1", "method": "GET", "url": "` + backend.URL + `"}`
+	req := httptest.NewRequest("POST", "/api/request/stream", strings.NewReader(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	finished := make(chan error, 1)
+	go func() {
+		finished <- server.handleStreamRequest(c)
+	}()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !server.executions.cancel("stream-1") {
+		if time.Now().After(deadline) {
+			t.Fatal("Stream execution was never registered")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	select {
+	case err := <-finished:
+		if err != nil {
+			t.Fatalf("handleStreamRequest() error = %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Stream was not cancelled")
+	}
+
+	if !strings.Contains(rec.Body.String(), "event: cancelled\n") {
+		t.Errorf("Expected cancelled event, got %q", rec.Body.String())
+	}
+
+	entries, err := db.GetHistory(1)
+	if err != nil {
+		t.Fatalf("GetHistory() error = %v", err)
+	}
+	if len(entries) != 1 || !entries[0].Streamed || entries[0].ErrorCode != "cancelled" || entries[0].StatusCode != http.StatusOK {
+		t.Errorf("Expected cancelled stream in history, got %+v", entries)
+	}
+}
@@ -0,0 +1,83 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/hc/hc/internal/models"
+	"github.com/labstack/echo/v4"
+)
+
+const maxTagLength = 50
+
+func validateTags(tags []string) error {
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			return errors.New("tags must not be empty")
+		}
+		if len(tag) > maxTagLength {
+			return errors.New("tag " + strconv.Quote(tag) + " is too long")
+		}
+		if strings.ContainsAny(tag, ", \t\n") {
+			return errors.New("tag " + strconv.Quote(tag) + " must not contain commas or whitespace")
+		}
+	}
+	return nil
+}
+
+func parseRequestFilter(c echo.Context) (*models.RequestFilter, bool, error) {
+	params := c.QueryParams()
+	filter := &models.RequestFilter{}
+	for _, value := range params["tag"] {
+		filter.Tags = append(filter.Tags, strings.Split(value, ",")...)
+	}
+	if value := c.QueryParam("favorite"); value != "" {
+		favorite, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, false, errors.New("invalid favorite filter")
+		}
+		filter.Favorite = favorite
+	}
+	if value := c.QueryParam("folder_id"); value != "" {
+		folderID, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, false, errors.New("invalid folder ID")
+		}
+		filter.FolderID = &folderID
+	}
+	return filter, len(filter.Tags) > 0 || filter.Favorite || filter.FolderID != nil, nil
+}
+
+func (s *Server) handleGetTags(c echo.Context) error {
+	tags, err := s.db.GetTags()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to get tags"))
+	}
+	return c.JSON(http.StatusOK, tags)
+}
+
+func (s *Server) handleAddFavorite(c echo.Context) error {
+	return s.setFavorite(c, true)
+}
+
+func (s *Server) handleRemoveFavorite(c echo.Context) error {
+	return s.setFavorite(c, false)
+}
+
+func (s *Server) setFavorite(c echo.Context, favorite bool) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request ID"))
+	}
+	if err := s.db.SetRequestFavorite(id, favorite); err != nil {
+		return c.JSON(http.StatusNotFound, models.NewErrorResponse("Request not found"))
+	}
+	var request models.Request
+	if err := s.db.GetRequest(id, &request); err != nil {
+		return c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to get request"))
+	}
+	return c.JSON(http.StatusOK, request)
+}
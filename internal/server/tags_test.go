@@ -0,0 +1,128 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hc/hc/internal/models"
+	"github.com/labstack/echo/v4"
+)
+
+func TestHandleRequestTags(t *testing.T) {
+	server, db := setupTestServer(t)
+	e := echo.New()
+
+	for _, request := range []*models.Request{
+		{Name: "Smoke", Method: "GET", URL: "https://example.com", Tags: []string{"smoke"}},
+		{Name: "Flaky", Method: "GET", URL: "https://example.com", Tags: []string{"flaky"}, Favorite: true},
+	} {
+		if err := db.CreateRequest(request); err != nil {
+			t.Fatalf("CreateRequest() error = %v", err)
+		}
+	}
+
+	filterTests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantCount  int
+	}{
+		{name: "No filter", query: "", wantStatus: http.StatusOK, wantCount: 2},
+		{name: "By tag", query: "?tag=smoke", wantStatus: http.StatusOK, wantCount: 1},
+		{name: "By comma separated tags", query: "?tag=smoke,flaky", wantStatus: http.StatusOK, wantCount: 0},
+		{name: "By favorite", query: "?favorite=true", wantStatus: http.StatusOK, wantCount: 1},
+		{name: "Invalid favorite", query: "?favorite=maybe", wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range filterTests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			if err := server.handleGetRequests(e.NewContext(httptest.NewRequest("GET", "/api/requests"+tt.query, nil), rec)); err != nil {
+				t.Fatalf("handleGetRequests() error = %v", err)
+			}
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var requests []models.Request
+			json.Unmarshal(rec.Body.Bytes(), &requests)
+			if len(requests) != tt.wantCount {
+				t.Errorf("Expected %d requests, got %d", tt.wantCount, len(requests))
+			}
+		})
+	}
+
+	t.Run("List tags", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		if err := server.handleGetTags(e.NewContext(httptest.NewRequest("GET", "/api/tags", nil), rec)); err != nil {
+			t.Fatalf("handleGetTags() error = %v", err)
+		}
+		var tags []models.TagCount
+		json.Unmarshal(rec.Body.Bytes(), &tags)
+		if len(tags) != 2 || tags[0].Tag != "flaky" || tags[1].Tag != "smoke" {
+			t.Errorf("Expected flaky and smoke tags, got %+v", tags)
+		}
+	})
+
+	t.Run("Reject invalid tag", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/api/requests", strings.NewReader(`{"name": "Bad", "method": "GET", "url": "https://example.com", "tags": ["two words"]}`))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		if err := server.handleCreateRequest(e.NewContext(req, rec)); err != nil {
+			t.Fatalf("handleCreateRequest() error = %v", err)
+		}
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d, got %d", http.StatusBadRequest, rec.Code)
+		}
+	})
+}
+
+func TestHandleFavorite(t *testing.T) {
+	server, db := setupTestServer(t)
+	e := echo.New()
+
+	request := &models.Request{Name: "Request", Method: "GET", URL: "https://example.com"}
+	if err := db.CreateRequest(request); err != nil {
+		t.Fatalf("CreateRequest() error = %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		handler      func(echo.Context) error
+		id           string
+		wantStatus   int
+		wantFavorite bool
+	}{
+		{name: "Add favorite", handler: server.handleAddFavorite, id: strconv.Itoa(request.ID), wantStatus: http.StatusOK, wantFavorite: true},
+		{name: "Remove favorite", handler: server.handleRemoveFavorite, id: strconv.Itoa(request.ID), wantStatus: http.StatusOK},
+		{name: "Missing request", handler: server.handleAddFavorite, id: "999", wantStatus: http.StatusNotFound},
+		{name: "Invalid ID", handler: server.handleAddFavorite, id: "abc", wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			c := e.NewContext(httptest.NewRequest("POST", "/", nil), rec)
+			c.SetParamNames("id")
+			c.SetParamValues(tt.id)
+			if err := tt.handler(c); err != nil {
+				t.Fatalf("handler error = %v", err)
+			}
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var updated models.Request
+			json.Unmarshal(rec.Body.Bytes(), &updated)
+			if updated.Favorite != tt.wantFavorite {
+				t.Errorf("Expected favorite %v, got %v", tt.wantFavorite, updated.Favorite)
+			}
+		})
+	}
+}
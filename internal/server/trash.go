@@ -0,0 +1,69 @@
+package server
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hc/hc/internal/logger"
+	"github.com/hc/hc/internal/models"
+	"github.com/labstack/echo/v4"
+)
+
+func (s *Server) purgeTrash() {
+	settings, err := s.db.GetSettings()
+	if err != nil {
+		logger.Get().Warn("Failed to load settings, skipping trash purge", slog.String("error", err.Error()))
+		return
+	}
+	if _, err := s.db.PurgeTrash(time.Now().Add(-settings.TrashRetention())); err != nil {
+		logger.Get().Warn("Failed to purge trash", slog.String("error", err.Error()))
+	}
+}
+
+func (s *Server) handleGetTrash(c echo.Context) error {
+	s.purgeTrash()
+	trash, err := s.db.GetTrash()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to get trash"))
+	}
+	return c.JSON(http.StatusOK, trash)
+}
+
+func (s *Server) handleEmptyTrash(c echo.Context) error {
+	if _, err := s.db.EmptyTrash(); err != nil {
+		return c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to empty trash"))
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (s *Server) handleRestoreFolder(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid folder ID"))
+	}
+	if err := s.db.RestoreFolder(c.Request().Context(), id); err != nil {
+		return c.JSON(http.StatusNotFound, models.NewErrorResponse("Folder not found in trash"))
+	}
+	var folder models.Folder
+	if err := s.db.GetFolder(id, &folder); err != nil {
+		return c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to get folder"))
+	}
+	return c.JSON(http.StatusOK, folder)
+}
+
+func (s *Server) handleRestoreRequest(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request ID"))
+	}
+	if err := s.db.RestoreRequest(c.Request().Context(), id); err != nil {
+		return c.JSON(http.StatusNotFound, models.NewErrorResponse("Request not found in trash"))
+	}
+	var request models.Request
+	if err := s.db.GetRequest(id, &request); err != nil {
+		return c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to get request"))
+	}
+	return c.JSON(http.StatusOK, request)
+}
@@ -0,0 +1,85 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/hc/hc/internal/models"
+	"github.com/labstack/echo/v4"
+)
+
+func TestHandleTrash(t *testing.T) {
+	server, db := setupTestServer(t)
+	e := echo.New()
+
+	folder := &models.Folder{Name: "Folder"}
+	if err := db.CreateFolder(folder); err != nil {
+		t.Fatalf("CreateFolder() error = %v", err)
+	}
+	request := &models.Request{Name: "Request", Method: "GET", URL: "https://example.com"}
+	if err := db.CreateRequest(request); err != nil {
+		t.Fatalf("CreateRequest() error = %v", err)
+	}
+	if err := db.DeleteFolder(folder.ID); err != nil {
+		t.Fatalf("DeleteFolder() error = %v", err)
+	}
+	if err := db.DeleteRequest(request.ID); err != nil {
+		t.Fatalf("DeleteRequest() error = %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	if err := server.handleGetTrash(e.NewContext(httptest.NewRequest("GET", "/api/trash", nil), rec)); err != nil {
+		t.Fatalf("handleGetTrash() error = %v", err)
+	}
+	var trash models.Trash
+	json.Unmarshal(rec.Body.Bytes(), &trash)
+	if rec.Code != http.StatusOK || len(trash.Folders) != 1 || len(trash.Requests) != 1 {
+		t.Fatalf("Expected one folder and one request in trash, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	tests := []struct {
+		name       string
+		handler    func(echo.Context) error
+		id         string
+		wantStatus int
+	}{
+		{name: "Restore folder", handler: server.handleRestoreFolder, id: strconv.Itoa(folder.ID), wantStatus: http.StatusOK},
+		{name: "Restore request", handler: server.handleRestoreRequest, id: strconv.Itoa(request.ID), wantStatus: http.StatusOK},
+		{name: "Restore folder twice", handler: server.handleRestoreFolder, id: strconv.Itoa(folder.ID), wantStatus: http.StatusNotFound},
+		{name: "Restore missing request", handler: server.handleRestoreRequest, id: "999", wantStatus: http.StatusNotFound},
+		{name: "Invalid request ID", handler: server.handleRestoreRequest, id: "abc", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			c := e.NewContext(httptest.NewRequest("POST", "/", nil), rec)
+			c.SetParamNames("id")
+			c.SetParamValues(tt.id)
+			if err := tt.handler(c); err != nil {
+				t.Fatalf("handler error = %v", err)
+			}
+			if rec.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+		})
+	}
+
+	if err := db.DeleteRequest(request.ID); err != nil {
+		t.Fatalf("DeleteRequest() error = %v", err)
+	}
+	rec = httptest.NewRecorder()
+	if err := server.handleEmptyTrash(e.NewContext(httptest.NewRequest("DELETE", "/api/trash", nil), rec)); err != nil {
+		t.Fatalf("handleEmptyTrash() error = %v", err)
+	}
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("Expected status %d, got %d", http.StatusNoContent, rec.Code)
+	}
+	trashed, err := db.GetTrash()
+	if err != nil || len(trashed.Requests) != 0 {
+		t.Errorf("Expected empty trash, got %+v, %v", trashed, err)
+	}
+}
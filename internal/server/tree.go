@@ -0,0 +1,73 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/hc/hc/internal/models"
+	"github.com/hc/hc/internal/storage"
+	"github.com/labstack/echo/v4"
+)
+
+func (s *Server) handleGetTree(c echo.Context) error {
+	tree, err := s.db.GetTree()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to get tree"))
+	}
+	return c.JSON(http.StatusOK, tree)
+}
+
+func (s *Server) handleMoveFolder(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid folder ID"))
+	}
+	var target models.MoveTarget
+	if err := c.Bind(&target); err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body"))
+	}
+	var folder models.Folder
+	if err := s.db.GetFolder(id, &folder); err != nil {
+		return c.JSON(http.StatusNotFound, models.NewErrorResponse("Folder not found"))
+	}
+	if err := s.db.MoveFolder(c.Request().Context(), id, target); err != nil {
+		if isFolderParentError(err) {
+			return c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error()))
+		}
+		return c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to move folder"))
+	}
+	if err := s.db.GetFolder(id, &folder); err != nil {
+		return c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to get folder"))
+	}
+	return c.JSON(http.StatusOK, folder)
+}
+
+func (s *Server) handleMoveRequest(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request ID"))
+	}
+	var target models.MoveTarget
+	if err := c.Bind(&target); err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body"))
+	}
+	var request models.Request
+	if err := s.db.GetRequest(id, &request); err != nil {
+		return c.JSON(http.StatusNotFound, models.NewErrorResponse("Request not found"))
+	}
+	if err := s.db.CheckRequestFolder(target.ParentID); err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error()))
+	}
+	if err := s.db.MoveRequest(c.Request().Context(), id, target); err != nil {
+		return c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to move request"))
+	}
+	if err := s.db.GetRequest(id, &request); err != nil {
+		return c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to get request"))
+	}
+	return c.JSON(http.StatusOK, request)
+}
+
+func isFolderParentError(err error) bool {
+	return errors.Is(err, storage.ErrFolderOwnParent) || errors.Is(err, storage.ErrFolderCycle) || errors.Is(err, storage.ErrParentNotFound)
+}
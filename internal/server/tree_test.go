@@ -0,0 +1,167 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hc/hc/internal/models"
+	"github.com/labstack/echo/v4"
+)
+
+func TestHandleGetTree(t *testing.T) {
+	server, db := setupTestServer(t)
+	e := echo.New()
+
+	parent := &models.Folder{Name: "Parent"}
+	if err := db.CreateFolder(parent); err != nil {
+		t.Fatalf("CreateFolder() error = %v", err)
+	}
+	child := &models.Folder{Name: "Child", ParentID: &parent.ID}
+	if err := db.CreateFolder(child); err != nil {
+		t.Fatalf("CreateFolder() error = %v", err)
+	}
+	request := &models.Request{Name: "Nested", Method: "GET", URL: "https://example.com", FolderID: &child.ID}
+	if err := db.CreateRequest(request); err != nil {
+		t.Fatalf("CreateRequest() error = %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	if err := server.handleGetTree(e.NewContext(httptest.NewRequest("GET", "/api/tree", nil), rec)); err != nil {
+		t.Fatalf("handleGetTree() error = %v", err)
+	}
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", rec.Code)
+	}
+	var tree models.Tree
+	if err := json.Unmarshal(rec.Body.Bytes(), &tree); err != nil {
+		t.Fatalf("Failed to decode tree: %v", err)
+	}
+	if len(tree.Folders) != 1 || len(tree.Folders[0].Folders) != 1 || len(tree.Folders[0].Folders[0].Requests) != 1 {
+		t.Errorf("Expected Parent > Child > Nested, got %+v", tree)
+	}
+}
+
+func TestHandleMove(t *testing.T) {
+	server, db := setupTestServer(t)
+	e := echo.New()
+
+	root := &models.Folder{Name: "Root"}
+	if err := db.CreateFolder(root); err != nil {
+		t.Fatalf("CreateFolder() error = %v", err)
+	}
+	child := &models.Folder{Name: "Child", ParentID: &root.ID}
+	if err := db.CreateFolder(child); err != nil {
+		t.Fatalf("CreateFolder() error = %v", err)
+	}
+	other := &models.Folder{Name: "Other"}
+	if err := db.CreateFolder(other); err != nil {
+		t.Fatalf("CreateFolder() error = %v", err)
+	}
+	request := &models.Request{Name: "Request", Method: "GET", URL: "https://example.com"}
+	if err := db.CreateRequest(request); err != nil {
+		t.Fatalf("CreateRequest() error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		handler    func(echo.Context) error
+		id         string
+		body       string
+		wantStatus int
+		wantMsg    string
+	}{
+		{name: "Move folder", handler: server.handleMoveFolder, id: strconv.Itoa(other.ID), body: `{"parent_id": ` + strconv.Itoa(child.ID) + `, "position": 0}`, wantStatus: http.StatusOK},
+		{name: "Move folder to root", handler: server.handleMoveFolder, id: strconv.Itoa(child.ID), body: `{"parent_id": null, "position": 0}`, wantStatus: http.StatusOK},
+		{name: "Move folder into itself", handler: server.handleMoveFolder, id: strconv.Itoa(root.ID), body: `{"parent_id": ` + strconv.Itoa(root.ID) + `}`, wantStatus: http.StatusBadRequest, wantMsg: "folder cannot be its own parent"},
+		{name: "Move folder into descendant", handler: server.handleMoveFolder, id: strconv.Itoa(child.ID), body: `{"parent_id": ` + strconv.Itoa(other.ID) + `}`, wantStatus: http.StatusBadRequest, wantMsg: "folder cannot be moved into its own subfolder"},
+		{name: "Move folder to missing parent", handler: server.handleMoveFolder, id: strconv.Itoa(root.ID), body: `{"parent_id": 999}`, wantStatus: http.StatusBadRequest, wantMsg: "parent folder not found"},
+		{name: "Move missing folder", handler: server.handleMoveFolder, id: "999", body: `{}`, wantStatus: http.StatusNotFound, wantMsg: "Folder not found"},
+		{name: "Move request", handler: server.handleMoveRequest, id: strconv.Itoa(request.ID), body: `{"parent_id": ` + strconv.Itoa(root.ID) + `}`, wantStatus: http.StatusOK},
+		{name: "Move request to missing folder", handler: server.handleMoveRequest, id: strconv.Itoa(request.ID), body: `{"parent_id": 999}`, wantStatus: http.StatusBadRequest, wantMsg: "folder not found"},
+		{name: "Move missing request", handler: server.handleMoveRequest, id: "999", body: `{}`, wantStatus: http.StatusNotFound, wantMsg: "Request not found"},
+		{name: "Invalid ID", handler: server.handleMoveRequest, id: "abc", body: `{}`, wantStatus: http.StatusBadRequest, wantMsg: "Invalid request ID"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("id")
+			c.SetParamValues(tt.id)
+			if err := tt.handler(c); err != nil {
+				t.Fatalf("handler error = %v", err)
+			}
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			if tt.wantMsg != "" {
+				var errResp models.ErrorResponse
+				json.Unmarshal(rec.Body.Bytes(), &errResp)
+				if len(errResp.Messages) == 0 || errResp.Messages[0] != tt.wantMsg {
+					t.Errorf("Expected error message %q, got %v", tt.wantMsg, errResp.Messages)
+				}
+			}
+		})
+	}
+
+	var moved models.Request
+	if err := db.GetRequest(request.ID, &moved); err != nil {
+		t.Fatalf("GetRequest() error = %v", err)
+	}
+	if moved.FolderID == nil || *moved.FolderID != root.ID {
+		t.Errorf("Expected request to be in Root, got %v", moved.FolderID)
+	}
+}
+
+func TestFolderParentValidation(t *testing.T) {
+	server, db := setupTestServer(t)
+	e := echo.New()
+
+	folder := &models.Folder{Name: "Folder"}
+	if err := db.CreateFolder(folder); err != nil {
+		t.Fatalf("CreateFolder() error = %v", err)
+	}
+	child := &models.Folder{Name: "Child", ParentID: &folder.ID}
+	if err := db.CreateFolder(child); err != nil {
+		t.Fatalf("CreateFolder() error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		handler    func(echo.Context) error
+		id         string
+		body       string
+		wantStatus int
+	}{
+		{name: "Create folder with missing parent", handler: server.handleCreateFolder, body: `{"name": "x", "parent_id": 999}`, wantStatus: http.StatusBadRequest},
+		{name: "Update folder to be its own parent", handler: server.handleUpdateFolderByID, id: strconv.Itoa(folder.ID), body: `{"name": "Folder", "parent_id": ` + strconv.Itoa(folder.ID) + `}`, wantStatus: http.StatusBadRequest},
+		{name: "Update folder into its own subfolder", handler: server.handleUpdateFolderByID, id: strconv.Itoa(folder.ID), body: `{"name": "Folder", "parent_id": ` + strconv.Itoa(child.ID) + `}`, wantStatus: http.StatusBadRequest},
+		{name: "Move folder into its own subfolder", handler: server.handleMoveFolder, id: strconv.Itoa(folder.ID), body: `{"parent_id": ` + strconv.Itoa(child.ID) + `}`, wantStatus: http.StatusBadRequest},
+		{name: "Create request in missing folder", handler: server.handleCreateRequest, body: `{"name": "x", "method": "GET", "url": "https://example.com", "folder_id": 999}`, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			if tt.id != "" {
+				c.SetParamNames("id")
+				c.SetParamValues(tt.id)
+			}
+			if err := tt.handler(c); err != nil {
+				t.Fatalf("handler error = %v", err)
+			}
+			if rec.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/hc/hc/internal/logger"
+	"github.com/hc/hc/internal/models"
+	"github.com/hc/hc/internal/wssession"
+	"github.com/labstack/echo/v4"
+)
+
+type webSocketSessionRequest struct {
+	RequestID *int              `json:"request_id"`
+	URL       string            `json:"url"`
+	Headers   map[string]string `json:"headers"`
+}
+
+type webSocketFrame struct {
+	Type string `json:"type"`
+	Data string `json:"data"`
+}
+
+func (s *Server) webSocketDialer() *websocket.Dialer {
+	transport := s.proxyClient.Transport()
+	return &websocket.Dialer{
+		Proxy:            transport.Proxy,
+		NetDialContext:   transport.DialContext,
+		TLSClientConfig:  transport.TLSClientConfig,
+		HandshakeTimeout: 30 * time.Second,
+	}
+}
+
+func (s *Server) handleCreateWebSocketSession(c echo.Context) error {
+	var sessionReq webSocketSessionRequest
+	if err := c.Bind(&sessionReq); err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid request body"))
+	}
+	if err := wssession.ValidateURL(sessionReq.URL); err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse(err.Error()))
+	}
+	session := models.WebSocketSession{
+		RequestID: sessionReq.RequestID,
+		URL:       sessionReq.URL,
+		Headers:   sessionReq.Headers,
+	}
+	if err := s.db.CreateWebSocketSession(&session); err != nil {
+		return c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to create websocket session"))
+	}
+	logger.Get().Info("Opening websocket session", slog.Int("id", session.ID), slog.String("url", session.URL))
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 30*time.Second)
+	defer cancel()
+	_, resp, err := s.wsManager.Open(ctx, session.ID, session.URL, session.Headers, s.webSocketDialer())
+	if err != nil {
+		logger.Get().Error("WebSocket handshake failed", slog.Int("id", session.ID), slog.String("error", err.Error()))
+		code := 0
+		if resp != nil {
+			code = resp.StatusCode
+		}
+		if closeErr := s.db.CloseWebSocketSession(session.ID, models.WebSocketStatusFailed, code, err.Error()); closeErr != nil {
+			logger.Get().Error("Failed to record websocket failure", slog.String("error", closeErr.Error()))
+		}
+		return c.JSON(http.StatusBadGateway, models.NewErrorResponse("Failed to connect: "+err.Error()))
+	}
+	return c.JSON(http.StatusCreated, session)
+}
+
+func (s *Server) handleGetWebSocketSessions(c echo.Context) error {
+	sessions, err := s.db.GetWebSocketSessions()
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to get websocket sessions"))
+	}
+	if sessions == nil {
+		sessions = []models.WebSocketSession{}
+	}
+	return c.JSON(http.StatusOK, sessions)
+}
+
+func (s *Server) handleGetWebSocketSessionByID(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid session ID"))
+	}
+	var session models.WebSocketSession
+	if err := s.db.GetWebSocketSession(id, &session); err != nil {
+		return c.JSON(http.StatusNotFound, models.NewErrorResponse("WebSocket session not found"))
+	}
+	return c.JSON(http.StatusOK, session)
+}
+
+func (s *Server) handleGetWebSocketMessages(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid session ID"))
+	}
+	messages, err := s.db.GetWebSocketMessages(id)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to get websocket messages"))
+	}
+	if messages == nil {
+		messages = []models.WebSocketMessage{}
+	}
+	return c.JSON(http.StatusOK, messages)
+}
+
+func (s *Server) handleCloseWebSocketSession(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid session ID"))
+	}
+	if err := s.wsManager.Close(id); err != nil {
+		return c.JSON(http.StatusNotFound, models.NewErrorResponse("WebSocket session not open"))
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (s *Server) handleDeleteWebSocketSession(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid session ID"))
+	}
+	if _, ok := s.wsManager.Get(id); ok {
+		if err := s.wsManager.Close(id); err != nil {
+			logger.Get().Warn("Failed to close websocket session", slog.Int("id", id), slog.String("error", err.Error()))
+		}
+	}
+	if err := s.db.DeleteWebSocketSession(id); err != nil {
+		return c.JSON(http.StatusInternalServerError, models.NewErrorResponse("Failed to delete websocket session"))
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (s *Server) handleWebSocketStream(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, models.NewErrorResponse("Invalid session ID"))
+	}
+	session, ok := s.wsManager.Get(id)
+	if !ok {
+		return c.JSON(http.StatusNotFound, models.NewErrorResponse("WebSocket session not open"))
+	}
+	conn, err := s.upgrader.Upgrade(c.Response(), c.Request(), nil)
+	if err != nil {
+		logger.Get().Error("Failed to upgrade websocket stream", slog.String("error", err.Error()))
+		return nil
+	}
+	defer conn.Close()
+	var writeMu sync.Mutex
+	write := func(v any) {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+		if err := conn.WriteJSON(v); err != nil {
+			logger.Get().Warn("Failed to relay websocket message", slog.Int("id", id), slog.String("error", err.Error()))
+		}
+	}
+	unsubscribe := session.Subscribe(func(message models.WebSocketMessage) {
+		write(message)
+	})
+	defer unsubscribe()
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-session.Done():
+			writeMu.Lock()
+			conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseNormalClosure, "upstream closed"),
+				time.Now().Add(5*time.Second))
+			writeMu.Unlock()
+			conn.Close()
+		case <-stop:
+		}
+	}()
+	for {
+		var frame webSocketFrame
+		if err := conn.ReadJSON(&frame); err != nil {
+			return nil
+		}
+		if _, err := session.Send(frame.Type, frame.Data); err != nil {
+			write(models.NewErrorResponse(err.Error()))
+		}
+	}
+}
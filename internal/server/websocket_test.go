@@ -0,0 +1,186 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/hc/hc/internal/models"
+	"github.com/labstack/echo/v4"
+)
+
+func TestWebSocketSessionHandlers(t *testing.T) {
+	server, db := setupTestServer(t)
+
+	// Upstream websocket server that echoes text frames in upper case
+	upgrader := websocket.Upgrader{}
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			_, payload, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			conn.WriteMessage(websocket.TextMessage, bytes.ToUpper(payload))
+		}
+	}))
+	defer upstream.Close()
+
+	hc := httptest.NewServer(server.router())
+	defer hc.Close()
+
+	upstreamURL := "ws" + strings.TrimPrefix(upstream.URL, "http")
+	body, _ := json.Marshal(map[string]interface{}{"url": upstreamURL})
+	resp, err := http.Post(hc.URL+"/api/websocket/sessions", "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d", http.StatusCreated, resp.StatusCode)
+	}
+	var session models.WebSocketSession
+	if err := json.NewDecoder(resp.Body).Decode(&session); err != nil {
+		t.Fatalf("Failed to decode session: %v", err)
+	}
+
+	// Attach to the relay stream and exchange a message
+	streamURL := "ws" + strings.TrimPrefix(hc.URL, "http") + "/api/websocket/sessions/" + strconv.Itoa(session.ID) + "/stream"
+	stream, _, err := websocket.DefaultDialer.Dial(streamURL, nil)
+	if err != nil {
+		t.Fatalf("Failed to connect to stream: %v", err)
+	}
+	defer stream.Close()
+
+	if err := stream.WriteJSON(map[string]string{"type": "text", "data": "hello"}); err != nil {
+		t.Fatalf("Failed to send frame: %v", err)
+	}
+
+	stream.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var sent, received models.WebSocketMessage
+	if err := stream.ReadJSON(&sent); err != nil {
+		t.Fatalf("Failed to read sent event: %v", err)
+	}
+	if err := stream.ReadJSON(&received); err != nil {
+		t.Fatalf("Failed to read received event: %v", err)
+	}
+	if sent.Direction != models.WebSocketDirectionSent || sent.Data != "hello" {
+		t.Errorf("Unexpected sent event: %+v", sent)
+	}
+	if received.Direction != models.WebSocketDirectionReceived || received.Data != "HELLO" {
+		t.Errorf("Unexpected received event: %+v", received)
+	}
+
+	// Closing the session closes the relay stream
+	closeReq, _ := http.NewRequest("POST", hc.URL+"/api/websocket/sessions/"+strconv.Itoa(session.ID)+"/close", nil)
+	closeResp, err := http.DefaultClient.Do(closeReq)
+	if err != nil {
+		t.Fatalf("Failed to close session: %v", err)
+	}
+	closeResp.Body.Close()
+	if closeResp.StatusCode != http.StatusNoContent {
+		t.Errorf("Expected status %d, got %d", http.StatusNoContent, closeResp.StatusCode)
+	}
+	for {
+		var event models.WebSocketMessage
+		if err := stream.ReadJSON(&event); err != nil {
+			break
+		}
+	}
+
+	messages, err := db.GetWebSocketMessages(session.ID)
+	if err != nil {
+		t.Fatalf("Failed to get messages: %v", err)
+	}
+	if len(messages) != 3 {
+		t.Errorf("Expected 3 logged messages, got %d", len(messages))
+	}
+	var stored models.WebSocketSession
+	if err := db.GetWebSocketSession(session.ID, &stored); err != nil {
+		t.Fatalf("Failed to get session: %v", err)
+	}
+	if stored.Status != models.WebSocketStatusClosed {
+		t.Errorf("Expected session to be closed, got %s", stored.Status)
+	}
+}
+
+func TestWebSocketSessionErrors(t *testing.T) {
+	server, _ := setupTestServer(t)
+	e := echo.New()
+
+	t.Run("InvalidURL", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/api/websocket/sessions", strings.NewReader(`{"url": "http://example.com"}`))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		if err := server.handleCreateWebSocketSession(c); err != nil {
+			t.Fatalf("handleCreateWebSocketSession() error = %v", err)
+		}
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d, got %d", http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("HandshakeFailure", func(t *testing.T) {
+		upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+		}))
+		defer upstream.Close()
+
+		reqBody := `{"url": "ws` + strings.TrimPrefix(upstream.URL, "http") + `"}`
+		req := httptest.NewRequest("POST", "/api/websocket/sessions", strings.NewReader(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		if err := server.handleCreateWebSocketSession(c); err != nil {
+			t.Fatalf("handleCreateWebSocketSession() error = %v", err)
+		}
+		if rec.Code != http.StatusBadGateway {
+			t.Errorf("Expected status %d, got %d", http.StatusBadGateway, rec.Code)
+		}
+	})
+
+	t.Run("StreamForUnknownSession", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/websocket/sessions/999/stream", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues("999")
+
+		if err := server.handleWebSocketStream(c); err != nil {
+			t.Fatalf("handleWebSocketStream() error = %v", err)
+		}
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("Expected status %d, got %d", http.StatusNotFound, rec.Code)
+		}
+	})
+
+	t.Run("ListSessions", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/websocket/sessions", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		if err := server.handleGetWebSocketSessions(c); err != nil {
+			t.Fatalf("handleGetWebSocketSessions() error = %v", err)
+		}
+		var sessions []models.WebSocketSession
+		if err := json.Unmarshal(rec.Body.Bytes(), &sessions); err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+		if len(sessions) != 1 || sessions[0].Status != models.WebSocketStatusFailed {
+			t.Errorf("Expected one failed session, got %+v", sessions)
+		}
+	})
+}
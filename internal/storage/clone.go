@@ -0,0 +1,210 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"strconv"
+
+	"github.com/hc/hc/internal/models"
+)
+
+const (
+	selectFolderNamesQuery    = `SELECT name FROM folders WHERE parent_id IS ? AND deleted_at IS NULL`
+	selectRequestNamesQuery   = `SELECT name FROM requests WHERE folder_id IS ? AND deleted_at IS NULL`
+	selectChildFoldersQuery   = `SELECT ` + folderColumns + ` FROM folders WHERE parent_id = ? AND deleted_at IS NULL ORDER BY position, name`
+	selectFolderRequestsQuery = `SELECT ` + requestColumns + ` FROM requests WHERE folder_id = ? AND deleted_at IS NULL ORDER BY position, name`
+)
+
+func (db *DB) CloneRequest(ctx context.Context, id int) (*models.Request, error) {
+	db.log.Info("Cloning request", slog.Int("id", id))
+	var clone models.Request
+	err := db.WithTx(ctx, func(tx *sql.Tx) error {
+		if err := getRequest(tx, id, &clone); err != nil {
+			return err
+		}
+		clone.Favorite = false
+		names, err := siblingNames(tx, selectRequestNamesQuery, clone.FolderID)
+		if err != nil {
+			return err
+		}
+		clone.Name = copyName(clone.Name, names)
+		return insertRequest(tx, &clone)
+	})
+	if err != nil {
+		db.log.Error("Failed to clone request", slog.Int("id", id), slog.String("error", err.Error()))
+		return nil, err
+	}
+	if err := db.GetRequest(clone.ID, &clone); err != nil {
+		return nil, err
+	}
+	return &clone, nil
+}
+
+func (db *DB) CloneFolder(ctx context.Context, id int) (*models.Folder, error) {
+	db.log.Info("Cloning folder", slog.Int("id", id))
+	var clone models.Folder
+	err := db.WithTx(ctx, func(tx *sql.Tx) error {
+		if err := getFolder(tx, id, &clone); err != nil {
+			return err
+		}
+		names, err := siblingNames(tx, selectFolderNamesQuery, clone.ParentID)
+		if err != nil {
+			return err
+		}
+		clone.Name = copyName(clone.Name, names)
+		return cloneFolderTree(tx, id, &clone)
+	})
+	if err != nil {
+		db.log.Error("Failed to clone folder", slog.Int("id", id), slog.String("error", err.Error()))
+		return nil, err
+	}
+	if err := db.GetFolder(clone.ID, &clone); err != nil {
+		return nil, err
+	}
+	return &clone, nil
+}
+
+func cloneFolderTree(tx *sql.Tx, sourceID int, folder *models.Folder) error {
+	if err := insertFolder(tx, folder); err != nil {
+		return err
+	}
+	requests, err := queryRequests(tx, selectFolderRequestsQuery, sourceID)
+	if err != nil {
+		return err
+	}
+	if err := attachTags(tx, requests); err != nil {
+		return err
+	}
+	if err := attachExamples(tx, requests); err != nil {
+		return err
+	}
+	for _, request := range requests {
+		request.FolderID = &folder.ID
+		request.Favorite = false
+		if err := insertRequest(tx, &request); err != nil {
+			return err
+		}
+	}
+	children, err := queryFolders(tx, selectChildFoldersQuery, sourceID)
+	if err != nil {
+		return err
+	}
+	for _, child := range children {
+		childID := child.ID
+		child.ParentID = &folder.ID
+		if err := cloneFolderTree(tx, childID, &child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func insertFolder(tx *sql.Tx, folder *models.Folder) error {
+	args, err := folderArgs(folder)
+	if err != nil {
+		return err
+	}
+	result, err := tx.Exec(insertFolderQuery, append(args, folder.ParentID)...)
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	folder.ID = int(id)
+	return nil
+}
+
+func insertRequest(tx *sql.Tx, request *models.Request) error {
+	args, err := requestArgs(request)
+	if err != nil {
+		return err
+	}
+	result, err := tx.Exec(insertRequestQuery, append(args, request.FolderID)...)
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	request.ID = int(id)
+	if err := setRequestTags(tx, request.ID, request.Tags); err != nil {
+		return err
+	}
+	return setRequestExamples(tx, request.ID, request.Examples)
+}
+
+type querier interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+}
+
+type readQuerier interface {
+	querier
+	rowQuerier
+}
+
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+func queryFolders(q querier, query string, args ...any) ([]models.Folder, error) {
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var folders []models.Folder
+	for rows.Next() {
+		var folder models.Folder
+		if err := scanFolder(rows, &folder); err != nil {
+			return nil, err
+		}
+		folders = append(folders, folder)
+	}
+	return folders, rows.Err()
+}
+
+func queryRequests(q querier, query string, args ...any) ([]models.Request, error) {
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var requests []models.Request
+	for rows.Next() {
+		var request models.Request
+		if err := scanRequest(rows, &request); err != nil {
+			return nil, err
+		}
+		requests = append(requests, request)
+	}
+	return requests, rows.Err()
+}
+
+func siblingNames(tx *sql.Tx, query string, parentID *int) (map[string]bool, error) {
+	rows, err := tx.Query(query, parentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	names := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names[name] = true
+	}
+	return names, rows.Err()
+}
+
+func copyName(name string, existing map[string]bool) string {
+	candidate := name + " (copy)"
+	for i := 2; existing[candidate]; i++ {
+		candidate = name + " (copy " + strconv.Itoa(i) + ")"
+	}
+	return candidate
+}
@@ -0,0 +1,122 @@
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hc/hc/internal/models"
+)
+
+func TestCloneRequest(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+
+	folder := createTestFolder(t, db, "Folder", nil)
+	original := &models.Request{
+		Name:     "Login",
+		FolderID: &folder.ID,
+		Method:   "POST",
+		URL:      "https://example.com/login",
+		Headers:  map[string]string{"Accept": "application/json"},
+		Body:     `{"user": "a"}`,
+		Auth:     &models.Auth{Type: models.AuthTypeBasic, Username: "a", Password: "b"},
+		Tags:     []string{"auth"},
+		Favorite: true,
+	}
+	if err := db.CreateRequest(original); err != nil {
+		t.Fatalf("CreateRequest() error = %v", err)
+	}
+
+	first, err := db.CloneRequest(ctx, original.ID)
+	if err != nil {
+		t.Fatalf("CloneRequest() error = %v", err)
+	}
+	if first.ID == original.ID || first.Name != "Login (copy)" {
+		t.Errorf("Expected a new request named 'Login (copy)', got %d %q", first.ID, first.Name)
+	}
+	if first.FolderID == nil || *first.FolderID != folder.ID || first.Body != original.Body || first.Headers["Accept"] != "application/json" {
+		t.Errorf("Expected fields to be copied, got %+v", first)
+	}
+	if first.Auth == nil || first.Auth.Password != "b" {
+		t.Errorf("Expected auth to be copied, got %+v", first.Auth)
+	}
+	if first.Favorite || len(first.Tags) != 1 || first.Tags[0] != "auth" {
+		t.Errorf("Expected tags to be copied and favorite to be reset, got %v %v", first.Tags, first.Favorite)
+	}
+	if first.Position != 1 {
+		t.Errorf("Expected clone to be appended at position 1, got %d", first.Position)
+	}
+
+	second, err := db.CloneRequest(ctx, original.ID)
+	if err != nil {
+		t.Fatalf("CloneRequest() error = %v", err)
+	}
+	if second.Name != "Login (copy 2)" {
+		t.Errorf("Expected 'Login (copy 2)', got %q", second.Name)
+	}
+
+	if _, err := db.CloneRequest(ctx, 999); err == nil {
+		t.Error("Expected error for missing request")
+	}
+}
+
+func TestCloneFolder(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+
+	root := &models.Folder{
+		Name:      "Service",
+		Headers:   map[string]string{"Accept": "application/json"},
+		Auth:      &models.Auth{Type: models.AuthTypeBearer, Token: "t"},
+		Variables: map[string]string{"env": "dev"},
+	}
+	if err := db.CreateFolder(root); err != nil {
+		t.Fatalf("CreateFolder() error = %v", err)
+	}
+	nested := createTestFolder(t, db, "Nested", &root.ID)
+	createTestFolder(t, db, "Deep", &nested.ID)
+	createTestRequest(t, db, "Root request", &root.ID)
+	createTestRequest(t, db, "Nested request", &nested.ID)
+
+	clone, err := db.CloneFolder(ctx, root.ID)
+	if err != nil {
+		t.Fatalf("CloneFolder() error = %v", err)
+	}
+	if clone.Name != "Service (copy)" || clone.ParentID != nil {
+		t.Errorf("Expected root level 'Service (copy)', got %q parent %v", clone.Name, clone.ParentID)
+	}
+	if clone.Auth == nil || clone.Auth.Token != "t" || clone.Variables["env"] != "dev" || clone.Headers["Accept"] != "application/json" {
+		t.Errorf("Expected folder settings to be copied, got %+v", clone)
+	}
+
+	tree, err := db.GetTree()
+	if err != nil {
+		t.Fatalf("GetTree() error = %v", err)
+	}
+	if len(tree.Folders) != 2 {
+		t.Fatalf("Expected original and clone at root, got %d folders", len(tree.Folders))
+	}
+	copied := tree.Folders[1]
+	if copied.ID != clone.ID {
+		t.Fatalf("Expected clone after original, got %+v", copied.Folder)
+	}
+	if len(copied.Requests) != 1 || copied.Requests[0].Name != "Root request" {
+		t.Errorf("Expected copied root request, got %+v", copied.Requests)
+	}
+	if len(copied.Folders) != 1 || copied.Folders[0].Name != "Nested" || copied.Folders[0].ID == nested.ID {
+		t.Fatalf("Expected a new Nested folder, got %+v", copied.Folders)
+	}
+	if len(copied.Folders[0].Requests) != 1 || len(copied.Folders[0].Folders) != 1 || copied.Folders[0].Folders[0].Name != "Deep" {
+		t.Errorf("Expected nested contents to be copied, got %+v", copied.Folders[0])
+	}
+
+	var count int
+	db.QueryRow("SELECT COUNT(*) FROM requests").Scan(&count)
+	if count != 4 {
+		t.Errorf("Expected 4 requests after clone, got %d", count)
+	}
+
+	if _, err := db.CloneFolder(ctx, 999); err == nil {
+		t.Error("Expected error for missing folder")
+	}
+}
@@ -0,0 +1,195 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/hc/hc/internal/models"
+)
+
+const (
+	createRequestExamplesTableQuery = `
+		CREATE TABLE IF NOT EXISTS request_examples (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			request_id INTEGER NOT NULL,
+			name TEXT NOT NULL,
+			status_code INTEGER NOT NULL,
+			headers TEXT,
+			body TEXT NOT NULL DEFAULT '',
+			history_id INTEGER,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (request_id) REFERENCES requests(id) ON DELETE CASCADE,
+			FOREIGN KEY (history_id) REFERENCES history(id) ON DELETE SET NULL
+		)`
+	exampleColumns              = `id, request_id, name, status_code, headers, body, history_id, created_at, updated_at`
+	insertExampleQuery          = `INSERT INTO request_examples (request_id, name, status_code, headers, body, history_id) VALUES (?, ?, ?, ?, ?, ?)`
+	selectExampleQuery          = `SELECT ` + exampleColumns + ` FROM request_examples WHERE request_id = ? AND id = ?`
+	selectExamplesQuery         = `SELECT ` + exampleColumns + ` FROM request_examples WHERE request_id = ? ORDER BY id`
+	selectRequestsExamplesQuery = `SELECT ` + exampleColumns + ` FROM request_examples WHERE request_id IN (%s) ORDER BY id`
+	updateExampleQuery          = `UPDATE request_examples SET name = ?, status_code = ?, headers = ?, body = ?, updated_at = CURRENT_TIMESTAMP WHERE request_id = ? AND id = ?`
+	deleteExampleQuery          = `DELETE FROM request_examples WHERE request_id = ? AND id = ?`
+	purgeRequestExamplesQuery   = `DELETE FROM request_examples WHERE request_id NOT IN (SELECT id FROM requests)`
+	countLiveRequestQuery       = `SELECT COUNT(*) FROM requests WHERE id = ? AND deleted_at IS NULL`
+)
+
+func (db *DB) CreateExample(example *models.ExampleResponse) error {
+	db.log.Info("Creating example response", slog.Int("request_id", example.RequestID), slog.String("name", example.Name))
+	var count int
+	if err := db.QueryRow(countLiveRequestQuery, example.RequestID).Scan(&count); err != nil {
+		return err
+	}
+	if count == 0 {
+		return fmt.Errorf("request not found")
+	}
+	if err := insertExample(db, example.RequestID, example); err != nil {
+		db.log.Error("Failed to create example response", slog.String("error", err.Error()))
+		return err
+	}
+	return db.GetExample(example.RequestID, example.ID, example)
+}
+
+func (db *DB) GetExample(requestID, id int, example *models.ExampleResponse) error {
+	err := scanExample(db.QueryRow(selectExampleQuery, requestID, id), example)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("example not found")
+	}
+	if err != nil {
+		db.log.Error("Failed to get example response", slog.Int("id", id), slog.String("error", err.Error()))
+		return err
+	}
+	return nil
+}
+
+func (db *DB) GetExamples(requestID int) ([]models.ExampleResponse, error) {
+	examples, err := queryExamples(db, selectExamplesQuery, requestID)
+	if err != nil {
+		db.log.Error("Failed to get example responses", slog.Int("request_id", requestID), slog.String("error", err.Error()))
+		return nil, err
+	}
+	return examples, nil
+}
+
+func (db *DB) UpdateExample(example *models.ExampleResponse) error {
+	db.log.Info("Updating example response", slog.Int("id", example.ID))
+	headers, err := serializeHeaders(example.Headers)
+	if err != nil {
+		return err
+	}
+	result, err := db.Exec(updateExampleQuery, example.Name, example.StatusCode, headers, example.Body, example.RequestID, example.ID)
+	if err != nil {
+		db.log.Error("Failed to update example response", slog.String("error", err.Error()))
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("example not found")
+	}
+	return db.GetExample(example.RequestID, example.ID, example)
+}
+
+func (db *DB) DeleteExample(requestID, id int) error {
+	db.log.Info("Deleting example response", slog.Int("id", id))
+	result, err := db.Exec(deleteExampleQuery, requestID, id)
+	if err != nil {
+		db.log.Error("Failed to delete example response", slog.String("error", err.Error()))
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("example not found")
+	}
+	return nil
+}
+
+func insertExample(e execer, requestID int, example *models.ExampleResponse) error {
+	headers, err := serializeHeaders(example.Headers)
+	if err != nil {
+		return err
+	}
+	result, err := e.Exec(insertExampleQuery, requestID, example.Name, example.StatusCode, headers, example.Body, example.HistoryID)
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	example.ID = int(id)
+	example.RequestID = requestID
+	return nil
+}
+
+func setRequestExamples(tx *sql.Tx, requestID int, examples []models.ExampleResponse) error {
+	for _, example := range examples {
+		if err := insertExample(tx, requestID, &example); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func attachExamples(q querier, requests []models.Request) error {
+	byRequest := make(map[int][]models.ExampleResponse)
+	for _, ids := range requestIDBatches(requests) {
+		examples, err := queryExamples(q, strings.Replace(selectRequestsExamplesQuery, "%s", placeholders(len(ids)), 1), ids...)
+		if err != nil {
+			return err
+		}
+		for _, example := range examples {
+			byRequest[example.RequestID] = append(byRequest[example.RequestID], example)
+		}
+	}
+	for i := range requests {
+		requests[i].Examples = byRequest[requests[i].ID]
+		if requests[i].Examples == nil {
+			requests[i].Examples = []models.ExampleResponse{}
+		}
+	}
+	return nil
+}
+
+func queryExamples(q querier, query string, args ...any) ([]models.ExampleResponse, error) {
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	examples := []models.ExampleResponse{}
+	for rows.Next() {
+		var example models.ExampleResponse
+		if err := scanExample(rows, &example); err != nil {
+			return nil, err
+		}
+		examples = append(examples, example)
+	}
+	return examples, rows.Err()
+}
+
+func scanExample(row rowScanner, example *models.ExampleResponse) error {
+	var headers sql.NullString
+	if err := row.Scan(
+		&example.ID,
+		&example.RequestID,
+		&example.Name,
+		&example.StatusCode,
+		&headers,
+		&example.Body,
+		&example.HistoryID,
+		&example.CreatedAt,
+		&example.UpdatedAt,
+	); err != nil {
+		return err
+	}
+	var err error
+	example.Headers, err = deserializeHeaders(headers.String)
+	return err
+}
@@ -0,0 +1,96 @@
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hc/hc/internal/models"
+)
+
+func TestRequestExamples(t *testing.T) {
+	db := setupTestDB(t)
+
+	request := &models.Request{
+		Name:     "Get user",
+		Method:   "GET",
+		URL:      "https://example.com/users/1",
+		Examples: []models.ExampleResponse{{Name: "Found", StatusCode: 200, Body: `{"id": 1}`}},
+	}
+	if err := db.CreateRequest(request); err != nil {
+		t.Fatalf("CreateRequest() error = %v", err)
+	}
+	if len(request.Examples) != 1 || request.Examples[0].Name != "Found" || request.Examples[0].RequestID != request.ID {
+		t.Fatalf("Expected example to be created with the request, got %+v", request.Examples)
+	}
+
+	missing := &models.ExampleResponse{RequestID: request.ID, Name: "Missing", StatusCode: 404, Headers: map[string]string{"Content-Type": "application/json"}, Body: `{"error": "not found"}`}
+	if err := db.CreateExample(missing); err != nil {
+		t.Fatalf("CreateExample() error = %v", err)
+	}
+	if missing.ID == 0 || missing.Headers["Content-Type"] != "application/json" {
+		t.Errorf("Expected created example with headers, got %+v", missing)
+	}
+	if err := db.CreateExample(&models.ExampleResponse{RequestID: 999, Name: "Orphan", StatusCode: 200}); err == nil {
+		t.Error("Expected error for missing request")
+	}
+
+	missing.Body = `{"error": "gone"}`
+	missing.StatusCode = 410
+	if err := db.UpdateExample(missing); err != nil {
+		t.Fatalf("UpdateExample() error = %v", err)
+	}
+	var updated models.ExampleResponse
+	if err := db.GetExample(request.ID, missing.ID, &updated); err != nil {
+		t.Fatalf("GetExample() error = %v", err)
+	}
+	if updated.StatusCode != 410 || updated.Body != `{"error": "gone"}` {
+		t.Errorf("Expected updated example, got %+v", updated)
+	}
+	if err := db.GetExample(request.ID+1, missing.ID, &updated); err == nil {
+		t.Error("Expected error for example of another request")
+	}
+
+	requests, err := db.GetRequests()
+	if err != nil {
+		t.Fatalf("GetRequests() error = %v", err)
+	}
+	if len(requests) != 1 || len(requests[0].Examples) != 2 {
+		t.Errorf("Expected examples to be returned with requests, got %+v", requests)
+	}
+
+	clone, err := db.CloneRequest(context.Background(), request.ID)
+	if err != nil {
+		t.Fatalf("CloneRequest() error = %v", err)
+	}
+	if len(clone.Examples) != 2 || clone.Examples[0].RequestID != clone.ID {
+		t.Errorf("Expected clone to copy examples, got %+v", clone.Examples)
+	}
+
+	if err := db.DeleteExample(request.ID, missing.ID); err != nil {
+		t.Fatalf("DeleteExample() error = %v", err)
+	}
+	if err := db.DeleteExample(request.ID, missing.ID); err == nil {
+		t.Error("Expected error deleting a missing example")
+	}
+	examples, err := db.GetExamples(request.ID)
+	if err != nil {
+		t.Fatalf("GetExamples() error = %v", err)
+	}
+	if len(examples) != 1 {
+		t.Errorf("Expected 1 example, got %d", len(examples))
+	}
+
+	if err := db.DeleteRequest(request.ID); err != nil {
+		t.Fatalf("DeleteRequest() error = %v", err)
+	}
+	if _, err := db.EmptyTrash(); err != nil {
+		t.Fatalf("EmptyTrash() error = %v", err)
+	}
+	var count int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM request_examples WHERE request_id = ?`, request.ID).Scan(&count); err != nil {
+		t.Fatalf("QueryRow() error = %v", err)
+	}
+	if count != 0 {
+		t.Errorf("Expected examples to be purged with the request, got %d", count)
+	}
+}
@@ -0,0 +1,45 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"log/slog"
+
+	"github.com/hc/hc/internal/models"
+)
+
+const (
+	createGraphQLSchemasTableQuery = `
+		CREATE TABLE IF NOT EXISTS graphql_schemas (
+			url TEXT PRIMARY KEY,
+			schema TEXT NOT NULL,
+			fetched_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)`
+	selectGraphQLSchemaQuery = `SELECT url, schema, fetched_at FROM graphql_schemas WHERE url = ?`
+	upsertGraphQLSchemaQuery = `
+		INSERT INTO graphql_schemas (url, schema) VALUES (?, ?)
+		ON CONFLICT(url) DO UPDATE SET schema = excluded.schema, fetched_at = CURRENT_TIMESTAMP`
+)
+
+func (db *DB) SaveGraphQLSchema(schema *models.GraphQLSchema) error {
+	db.log.Info("Saving GraphQL schema", slog.String("url", schema.URL))
+	if _, err := db.Exec(upsertGraphQLSchemaQuery, schema.URL, string(schema.Schema)); err != nil {
+		db.log.Error("Failed to save GraphQL schema", slog.String("error", err.Error()))
+		return err
+	}
+	return db.GetGraphQLSchema(schema.URL, schema)
+}
+
+func (db *DB) GetGraphQLSchema(url string, schema *models.GraphQLSchema) error {
+	var data string
+	err := db.QueryRow(selectGraphQLSchemaQuery, url).Scan(&schema.URL, &data, &schema.FetchedAt)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("graphql schema not found")
+	}
+	if err != nil {
+		db.log.Error("Failed to get GraphQL schema", slog.String("url", url), slog.String("error", err.Error()))
+		return err
+	}
+	schema.Schema = []byte(data)
+	return nil
+}
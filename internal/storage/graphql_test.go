@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/hc/hc/internal/models"
+)
+
+func TestGraphQLSchemaCache(t *testing.T) {
+	db := setupTestDB(t)
+
+	var schema models.GraphQLSchema
+	if err := db.GetGraphQLSchema("http://api.local/graphql", &schema); err == nil {
+		t.Error("Expected error for missing schema")
+	}
+
+	saved := models.GraphQLSchema{
+		URL:    "http://api.local/graphql",
+		Schema: json.RawMessage(`{"queryType":{"name":"Query"}}`),
+	}
+	if err := db.SaveGraphQLSchema(&saved); err != nil {
+		t.Fatalf("SaveGraphQLSchema() error = %v", err)
+	}
+	if saved.FetchedAt.IsZero() {
+		t.Error("Expected fetched_at to be set")
+	}
+
+	saved.Schema = json.RawMessage(`{"queryType":{"name":"Root"}}`)
+	if err := db.SaveGraphQLSchema(&saved); err != nil {
+		t.Fatalf("SaveGraphQLSchema() error = %v", err)
+	}
+	if err := db.GetGraphQLSchema("http://api.local/graphql", &schema); err != nil {
+		t.Fatalf("GetGraphQLSchema() error = %v", err)
+	}
+	if string(schema.Schema) != `{"queryType":{"name":"Root"}}` {
+		t.Errorf("Expected schema to be replaced, got %s", schema.Schema)
+	}
+
+	var count int
+	db.QueryRow("SELECT COUNT(*) FROM graphql_schemas").Scan(&count)
+	if count != 1 {
+		t.Errorf("Expected a single cached schema, got %d", count)
+	}
+}
+
+func TestRequestGraphQLBody(t *testing.T) {
+	db := setupTestDB(t)
+
+	request := models.Request{
+		Name:   "Get user",
+		Type:   models.RequestTypeGraphQL,
+		Method: "POST",
+		URL:    "http://api.local/graphql",
+		GraphQL: &models.GraphQLBody{
+			Query:         "query GetUser { me { id } }",
+			Variables:     `{"id": 1}`,
+			OperationName: "GetUser",
+		},
+	}
+	if err := db.CreateRequest(&request); err != nil {
+		t.Fatalf("CreateRequest() error = %v", err)
+	}
+
+	var got models.Request
+	if err := db.GetRequest(request.ID, &got); err != nil {
+		t.Fatalf("GetRequest() error = %v", err)
+	}
+	if got.GraphQL == nil || *got.GraphQL != *request.GraphQL {
+		t.Errorf("Expected GraphQL body %+v, got %+v", request.GraphQL, got.GraphQL)
+	}
+
+	got.GraphQL = nil
+	got.Type = models.RequestTypeHTTP
+	if err := db.UpdateRequest(&got); err != nil {
+		t.Fatalf("UpdateRequest() error = %v", err)
+	}
+	if err := db.GetRequest(request.ID, &got); err != nil {
+		t.Fatalf("GetRequest() error = %v", err)
+	}
+	if got.GraphQL != nil {
+		t.Errorf("Expected GraphQL body to be cleared, got %+v", got.GraphQL)
+	}
+}
@@ -0,0 +1,104 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/hc/hc/internal/models"
+)
+
+const (
+	createGRPCProtoSetsTableQuery = `
+		CREATE TABLE IF NOT EXISTS grpc_proto_sets (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			files TEXT NOT NULL,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)`
+	insertGRPCProtoSetQuery  = `INSERT INTO grpc_proto_sets (name, files) VALUES (?, ?)`
+	selectGRPCProtoSetQuery  = `SELECT id, name, files, created_at FROM grpc_proto_sets WHERE id = ?`
+	selectGRPCProtoSetsQuery = `SELECT id, name, files, created_at FROM grpc_proto_sets ORDER BY name`
+	deleteGRPCProtoSetQuery  = `DELETE FROM grpc_proto_sets WHERE id = ?`
+)
+
+func (db *DB) CreateGRPCProtoSet(protoSet *models.GRPCProtoSet) error {
+	db.log.Info("Creating gRPC proto set", slog.String("name", protoSet.Name))
+	filesJSON, err := json.Marshal(protoSet.Files)
+	if err != nil {
+		return err
+	}
+	result, err := db.Exec(insertGRPCProtoSetQuery, protoSet.Name, string(filesJSON))
+	if err != nil {
+		db.log.Error("Failed to create gRPC proto set", slog.String("error", err.Error()))
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	protoSet.ID = int(id)
+	return db.GetGRPCProtoSet(protoSet.ID, protoSet)
+}
+
+func (db *DB) GetGRPCProtoSet(id int, protoSet *models.GRPCProtoSet) error {
+	err := scanGRPCProtoSet(db.QueryRow(selectGRPCProtoSetQuery, id), protoSet)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("grpc proto set not found")
+	}
+	if err != nil {
+		db.log.Error("Failed to get gRPC proto set", slog.Int("id", id), slog.String("error", err.Error()))
+		return err
+	}
+	return nil
+}
+
+func (db *DB) GetGRPCProtoSets() ([]models.GRPCProtoSet, error) {
+	rows, err := db.Query(selectGRPCProtoSetsQuery)
+	if err != nil {
+		db.log.Error("Failed to get gRPC proto sets", slog.String("error", err.Error()))
+		return nil, err
+	}
+	defer rows.Close()
+	var protoSets []models.GRPCProtoSet
+	for rows.Next() {
+		var protoSet models.GRPCProtoSet
+		if err := scanGRPCProtoSet(rows, &protoSet); err != nil {
+			return nil, err
+		}
+		protoSets = append(protoSets, protoSet)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return protoSets, nil
+}
+
+func (db *DB) DeleteGRPCProtoSet(id int) error {
+	db.log.Info("Deleting gRPC proto set", slog.Int("id", id))
+	result, err := db.Exec(deleteGRPCProtoSetQuery, id)
+	if err != nil {
+		db.log.Error("Failed to delete gRPC proto set", slog.String("error", err.Error()))
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("grpc proto set not found")
+	}
+	return nil
+}
+
+func scanGRPCProtoSet(row rowScanner, protoSet *models.GRPCProtoSet) error {
+	var filesStr string
+	if err := row.Scan(&protoSet.ID, &protoSet.Name, &filesStr, &protoSet.CreatedAt); err != nil {
+		return err
+	}
+	if err := json.Unmarshal([]byte(filesStr), &protoSet.Files); err != nil {
+		return fmt.Errorf("failed to deserialize proto files: %w", err)
+	}
+	return nil
+}
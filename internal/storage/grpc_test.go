@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hc/hc/internal/models"
+)
+
+func TestGRPCProtoSetCRUD(t *testing.T) {
+	db := setupTestDB(t)
+
+	protoSet := models.GRPCProtoSet{
+		Name:  "greeter",
+		Files: map[string]string{"greeter.proto": `syntax = "proto3";`},
+	}
+	if err := db.CreateGRPCProtoSet(&protoSet); err != nil {
+		t.Fatalf("CreateGRPCProtoSet() error = %v", err)
+	}
+	if protoSet.ID == 0 || protoSet.CreatedAt.IsZero() {
+		t.Errorf("Expected ID and created_at to be set, got %+v", protoSet)
+	}
+
+	var got models.GRPCProtoSet
+	if err := db.GetGRPCProtoSet(protoSet.ID, &got); err != nil {
+		t.Fatalf("GetGRPCProtoSet() error = %v", err)
+	}
+	if !reflect.DeepEqual(got.Files, protoSet.Files) {
+		t.Errorf("Expected files %v, got %v", protoSet.Files, got.Files)
+	}
+
+	protoSets, err := db.GetGRPCProtoSets()
+	if err != nil {
+		t.Fatalf("GetGRPCProtoSets() error = %v", err)
+	}
+	if len(protoSets) != 1 {
+		t.Errorf("Expected 1 proto set, got %d", len(protoSets))
+	}
+
+	if err := db.DeleteGRPCProtoSet(protoSet.ID); err != nil {
+		t.Fatalf("DeleteGRPCProtoSet() error = %v", err)
+	}
+	if err := db.GetGRPCProtoSet(protoSet.ID, &got); err == nil {
+		t.Error("Expected error for deleted proto set")
+	}
+	if err := db.DeleteGRPCProtoSet(protoSet.ID); err == nil {
+		t.Error("Expected error deleting missing proto set")
+	}
+}
+
+func TestRequestGRPC(t *testing.T) {
+	db := setupTestDB(t)
+
+	protoSetID := 3
+	request := models.Request{
+		Name: "Say hello",
+		Type: models.RequestTypeGRPC,
+		GRPC: &models.GRPCRequest{
+			Target:     "localhost:50051",
+			Method:     "test.v1.Greeter/SayHello",
+			Message:    `{"name": "hc"}`,
+			Metadata:   map[string]string{"authorization": "Bearer token"},
+			ProtoSetID: &protoSetID,
+		},
+	}
+	if err := db.CreateRequest(&request); err != nil {
+		t.Fatalf("CreateRequest() error = %v", err)
+	}
+
+	var got models.Request
+	if err := db.GetRequest(request.ID, &got); err != nil {
+		t.Fatalf("GetRequest() error = %v", err)
+	}
+	if !reflect.DeepEqual(got.GRPC, request.GRPC) {
+		t.Errorf("Expected gRPC request %+v, got %+v", request.GRPC, got.GRPC)
+	}
+	if got.GraphQL != nil {
+		t.Errorf("Expected no GraphQL body, got %+v", got.GraphQL)
+	}
+}
@@ -0,0 +1,152 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/hc/hc/internal/models"
+)
+
+const (
+	createHistoryTableQuery = `
+		CREATE TABLE IF NOT EXISTS history (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			request_id INTEGER,
+			method TEXT NOT NULL,
+			url TEXT NOT NULL,
+			request_headers TEXT,
+			request_body TEXT,
+			status_code INTEGER NOT NULL,
+			response_headers TEXT,
+			response_body TEXT,
+			duration INTEGER NOT NULL DEFAULT 0,
+			metrics TEXT,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (request_id) REFERENCES requests(id) ON DELETE SET NULL
+		)`
+	historyColumns          = `id, request_id, method, url, request_headers, request_body, status_code, response_headers, response_body, duration, metrics, error_code, streamed, created_at`
+	insertHistoryEntryQuery = `INSERT INTO history (request_id, method, url, request_headers, request_body, status_code, response_headers, response_body, duration, metrics, error_code, streamed) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	selectHistoryEntryQuery = `SELECT ` + historyColumns + ` FROM history WHERE id = ?`
+	selectHistoryQuery      = `SELECT ` + historyColumns + ` FROM history ORDER BY id DESC LIMIT ?`
+	purgeHistoryQuery       = `DELETE FROM history WHERE created_at < ? OR id NOT IN (SELECT id FROM history ORDER BY id DESC LIMIT ?)`
+)
+
+func (db *DB) CreateHistoryEntry(entry *models.HistoryEntry) error {
+	requestHeaders, err := serializeHeaders(entry.RequestHeaders)
+	if err != nil {
+		return err
+	}
+	responseHeaders, err := serializeHeaders(entry.ResponseHeaders)
+	if err != nil {
+		return err
+	}
+	metricsJSON, err := serializeOptionalJSON(entry.Metrics)
+	if err != nil {
+		return err
+	}
+	result, err := db.Exec(insertHistoryEntryQuery,
+		entry.RequestID,
+		entry.Method,
+		entry.URL,
+		requestHeaders,
+		entry.RequestBody,
+		entry.StatusCode,
+		responseHeaders,
+		entry.ResponseBody,
+		entry.Duration,
+		metricsJSON,
+		entry.ErrorCode,
+		entry.Streamed,
+	)
+	if err != nil {
+		db.log.Error("Failed to create history entry", slog.String("error", err.Error()))
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	entry.ID = int(id)
+	return db.GetHistoryEntry(entry.ID, entry)
+}
+
+func (db *DB) GetHistoryEntry(id int, entry *models.HistoryEntry) error {
+	err := scanHistoryEntry(db.QueryRow(selectHistoryEntryQuery, id), entry)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("history entry not found")
+	}
+	if err != nil {
+		db.log.Error("Failed to get history entry", slog.Int("id", id), slog.String("error", err.Error()))
+		return err
+	}
+	return nil
+}
+
+func (db *DB) GetHistory(limit int) ([]models.HistoryEntry, error) {
+	rows, err := db.Query(selectHistoryQuery, limit)
+	if err != nil {
+		db.log.Error("Failed to get history", slog.String("error", err.Error()))
+		return nil, err
+	}
+	defer rows.Close()
+	var entries []models.HistoryEntry
+	for rows.Next() {
+		var entry models.HistoryEntry
+		if err := scanHistoryEntry(rows, &entry); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (db *DB) PurgeHistory(before time.Time, keep int) (int64, error) {
+	result, err := db.Exec(purgeHistoryQuery, before.UTC(), keep)
+	if err != nil {
+		db.log.Error("Failed to purge history", slog.String("error", err.Error()))
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func scanHistoryEntry(row rowScanner, entry *models.HistoryEntry) error {
+	var (
+		requestHeaders  string
+		responseHeaders string
+		metricsStr      sql.NullString
+	)
+	if err := row.Scan(
+		&entry.ID,
+		&entry.RequestID,
+		&entry.Method,
+		&entry.URL,
+		&requestHeaders,
+		&entry.RequestBody,
+		&entry.StatusCode,
+		&responseHeaders,
+		&entry.ResponseBody,
+		&entry.Duration,
+		&metricsStr,
+		&entry.ErrorCode,
+		&entry.Streamed,
+		&entry.CreatedAt,
+	); err != nil {
+		return err
+	}
+	var err error
+	if entry.RequestHeaders, err = deserializeHeaders(requestHeaders); err != nil {
+		return fmt.Errorf("failed to deserialize request headers: %w", err)
+	}
+	if entry.ResponseHeaders, err = deserializeHeaders(responseHeaders); err != nil {
+		return fmt.Errorf("failed to deserialize response headers: %w", err)
+	}
+	if entry.Metrics, err = deserializeOptionalJSON[models.ResponseMetrics](metricsStr); err != nil {
+		return fmt.Errorf("failed to deserialize metrics: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hc/hc/internal/models"
+)
+
+func TestHistory(t *testing.T) {
+	db := setupTestDB(t)
+
+	var missing models.HistoryEntry
+	if err := db.GetHistoryEntry(1, &missing); err == nil {
+		t.Error("Expected error for missing history entry")
+	}
+
+	requestID := 7
+	for i, url := range []string{"https://example.com/first", "https://example.com/second", "https://example.com/third"} {
+		entry := &models.HistoryEntry{
+			RequestID:       &requestID,
+			Method:          "GET",
+			URL:             url,
+			RequestHeaders:  map[string]string{"Accept": "application/json"},
+			StatusCode:      200 + i,
+			ResponseHeaders: map[string]string{"Content-Type": "text/plain"},
+			ResponseBody:    "ok",
+			Duration:        12,
+			Metrics: &models.ResponseMetrics{
+				Protocol:    "HTTP/2",
+				TLSVersion:  "TLS 1.3",
+				CipherSuite: "TLS_AES_128_GCM_SHA256",
+				HeadersSize: 120,
+				EncodedSize: 40,
+				BodySize:    2,
+			},
+		}
+		if err := db.CreateHistoryEntry(entry); err != nil {
+			t.Fatalf("CreateHistoryEntry() error = %v", err)
+		}
+		if entry.ID == 0 || entry.CreatedAt.IsZero() {
+			t.Errorf("Expected ID and CreatedAt to be set, got %d and %v", entry.ID, entry.CreatedAt)
+		}
+	}
+
+	var entry models.HistoryEntry
+	if err := db.GetHistoryEntry(1, &entry); err != nil {
+		t.Fatalf("GetHistoryEntry() error = %v", err)
+	}
+	if entry.RequestID == nil || *entry.RequestID != requestID {
+		t.Errorf("Expected request ID %d, got %v", requestID, entry.RequestID)
+	}
+	if entry.RequestHeaders["Accept"] != "application/json" {
+		t.Errorf("Expected request headers to round trip, got %v", entry.RequestHeaders)
+	}
+	if entry.Metrics == nil || entry.Metrics.Protocol != "HTTP/2" || entry.Metrics.CipherSuite != "TLS_AES_128_GCM_SHA256" || entry.Metrics.EncodedSize != 40 {
+		t.Errorf("Expected metrics to round trip, got %+v", entry.Metrics)
+	}
+
+	entries, err := db.GetHistory(2)
+	if err != nil {
+		t.Fatalf("GetHistory() error = %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].URL != "https://example.com/third" {
+		t.Errorf("Expected newest entry first, got %s", entries[0].URL)
+	}
+}
+
+func TestPurgeHistory(t *testing.T) {
+	db := setupTestDB(t)
+
+	for i := 0; i < 3; i++ {
+		entry := &models.HistoryEntry{Method: "GET", URL: "https://example.com", StatusCode: 200}
+		if err := db.CreateHistoryEntry(entry); err != nil {
+			t.Fatalf("CreateHistoryEntry() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		before    time.Time
+		keep      int
+		wantCount int64
+	}{
+		{name: "Entries within retention are kept", before: time.Now().Add(-time.Hour), keep: 10, wantCount: 0},
+		{name: "Entries beyond the limit are purged", before: time.Now().Add(-time.Hour), keep: 2, wantCount: 1},
+		{name: "Entries older than cutoff are purged", before: time.Now().Add(time.Hour), keep: 10, wantCount: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			count, err := db.PurgeHistory(tt.before, tt.keep)
+			if err != nil {
+				t.Fatalf("PurgeHistory() error = %v", err)
+			}
+			if count != tt.wantCount {
+				t.Errorf("Expected %d purged entries, got %d", tt.wantCount, count)
+			}
+		})
+	}
+}
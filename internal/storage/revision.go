@@ -0,0 +1,130 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"reflect"
+	"slices"
+
+	"github.com/hc/hc/internal/models"
+)
+
+const (
+	createRequestRevisionsTableQuery = `
+		CREATE TABLE IF NOT EXISTS request_revisions (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			request_id INTEGER NOT NULL,
+			revision INTEGER NOT NULL,
+			snapshot TEXT NOT NULL,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE (request_id, revision),
+			FOREIGN KEY (request_id) REFERENCES requests(id) ON DELETE CASCADE
+		)`
+	revisionColumns             = `id, request_id, revision, snapshot, created_at`
+	insertRequestRevisionQuery  = `INSERT INTO request_revisions (request_id, revision, snapshot) VALUES (?, (SELECT COALESCE(MAX(revision), 0) + 1 FROM request_revisions WHERE request_id = ?), ?)`
+	selectRequestRevisionQuery  = `SELECT ` + revisionColumns + ` FROM request_revisions WHERE request_id = ? AND revision = ?`
+	selectRequestRevisionsQuery = `SELECT ` + revisionColumns + ` FROM request_revisions WHERE request_id = ? ORDER BY revision DESC`
+	purgeRequestRevisionsQuery  = `DELETE FROM request_revisions WHERE request_id NOT IN (SELECT id FROM requests)`
+)
+
+func (db *DB) UpdateRequest(request *models.Request) error {
+	db.log.Info("Updating request", slog.Int("id", request.ID))
+	return db.WithTx(context.Background(), func(tx *sql.Tx) error {
+		return db.updateRequest(tx, request)
+	})
+}
+
+func (db *DB) updateRequest(tx *sql.Tx, request *models.Request) error {
+	args, err := requestArgs(request)
+	if err != nil {
+		return err
+	}
+	var current models.Request
+	if err := scanRequest(tx.QueryRow(selectRequestQuery, request.ID), &current); err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("request not found")
+		}
+		return err
+	}
+	tags, err := requestTags(tx, current.ID)
+	if err != nil {
+		return err
+	}
+	current.Tags = tags
+	currentArgs, err := requestArgs(&current)
+	if err != nil {
+		return err
+	}
+	if reflect.DeepEqual(args, currentArgs) && slices.Equal(NormalizeTags(request.Tags), current.Tags) {
+		return nil
+	}
+	snapshot, err := json.Marshal(current)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(insertRequestRevisionQuery, current.ID, current.ID, string(snapshot)); err != nil {
+		db.log.Error("Failed to create request revision", slog.String("error", err.Error()))
+		return err
+	}
+	if !equalParent(current.FolderID, request.FolderID) {
+		if err := requestOrder.move(tx, request.ID, models.MoveTarget{ParentID: request.FolderID, Position: math.MaxInt}); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.Exec(updateRequestQuery, append(args, request.ID)...); err != nil {
+		db.log.Error("Failed to update request", slog.String("error", err.Error()))
+		return err
+	}
+	return setRequestTags(tx, request.ID, request.Tags)
+}
+
+func (db *DB) GetRequestRevisions(requestID int) ([]models.RequestRevision, error) {
+	rows, err := db.Query(selectRequestRevisionsQuery, requestID)
+	if err != nil {
+		db.log.Error("Failed to get request revisions", slog.String("error", err.Error()))
+		return nil, err
+	}
+	defer rows.Close()
+	var revisions []models.RequestRevision
+	for rows.Next() {
+		var revision models.RequestRevision
+		if err := scanRequestRevision(rows, &revision); err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, revision)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+func (db *DB) GetRequestRevision(requestID, number int, revision *models.RequestRevision) error {
+	err := scanRequestRevision(db.QueryRow(selectRequestRevisionQuery, requestID, number), revision)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("revision not found")
+	}
+	if err != nil {
+		db.log.Error("Failed to get request revision", slog.Int("request_id", requestID), slog.String("error", err.Error()))
+		return err
+	}
+	return nil
+}
+
+func scanRequestRevision(row rowScanner, revision *models.RequestRevision) error {
+	var snapshot string
+	if err := row.Scan(
+		&revision.ID,
+		&revision.RequestID,
+		&revision.Revision,
+		&snapshot,
+		&revision.CreatedAt,
+	); err != nil {
+		return err
+	}
+	return json.Unmarshal([]byte(snapshot), &revision.Request)
+}
@@ -0,0 +1,87 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/hc/hc/internal/models"
+)
+
+func TestUpdateRequestRecordsRevisions(t *testing.T) {
+	db := setupTestDB(t)
+
+	request := createTestRequest(t, db, "Login", nil)
+	updates := []struct {
+		name string
+		url  string
+	}{
+		{name: "Login", url: "https://example.com/v1"},
+		{name: "Login", url: "https://example.com/v1"},
+		{name: "Sign in", url: "https://example.com/v2"},
+	}
+	for _, update := range updates {
+		request.Name = update.name
+		request.URL = update.url
+		if err := db.UpdateRequest(request); err != nil {
+			t.Fatalf("UpdateRequest() error = %v", err)
+		}
+	}
+
+	revisions, err := db.GetRequestRevisions(request.ID)
+	if err != nil {
+		t.Fatalf("GetRequestRevisions() error = %v", err)
+	}
+	if len(revisions) != 2 {
+		t.Fatalf("Expected 2 revisions (unchanged update skipped), got %d", len(revisions))
+	}
+	tests := []struct {
+		revision int
+		wantName string
+		wantURL  string
+	}{
+		{revision: 2, wantName: "Login", wantURL: "https://example.com/v1"},
+		{revision: 1, wantName: "Login", wantURL: "https://example.com"},
+	}
+	for i, tt := range tests {
+		if revisions[i].Revision != tt.revision {
+			t.Errorf("Expected revision %d at index %d, got %d", tt.revision, i, revisions[i].Revision)
+		}
+		var revision models.RequestRevision
+		if err := db.GetRequestRevision(request.ID, tt.revision, &revision); err != nil {
+			t.Fatalf("GetRequestRevision() error = %v", err)
+		}
+		if revision.Request.Name != tt.wantName || revision.Request.URL != tt.wantURL {
+			t.Errorf("Expected revision %d to be %q %q, got %q %q", tt.revision, tt.wantName, tt.wantURL, revision.Request.Name, revision.Request.URL)
+		}
+	}
+
+	var revision models.RequestRevision
+	if err := db.GetRequestRevision(request.ID, 99, &revision); err == nil {
+		t.Error("Expected error for missing revision")
+	}
+	if err := db.UpdateRequest(&models.Request{ID: 999, Name: "Missing"}); err == nil {
+		t.Error("Expected error when updating a missing request")
+	}
+}
+
+func TestPurgeTrashRemovesRevisions(t *testing.T) {
+	db := setupTestDB(t)
+
+	request := createTestRequest(t, db, "Request", nil)
+	request.URL = "https://example.com/changed"
+	if err := db.UpdateRequest(request); err != nil {
+		t.Fatalf("UpdateRequest() error = %v", err)
+	}
+	if err := db.DeleteRequest(request.ID); err != nil {
+		t.Fatalf("DeleteRequest() error = %v", err)
+	}
+	if _, err := db.EmptyTrash(); err != nil {
+		t.Fatalf("EmptyTrash() error = %v", err)
+	}
+	revisions, err := db.GetRequestRevisions(request.ID)
+	if err != nil {
+		t.Fatalf("GetRequestRevisions() error = %v", err)
+	}
+	if len(revisions) != 0 {
+		t.Errorf("Expected revisions to be purged with the request, got %d", len(revisions))
+	}
+}
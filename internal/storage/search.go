@@ -0,0 +1,335 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"html"
+	"log/slog"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hc/hc/internal/models"
+)
+
+const (
+	highlightStart   = "<mark>"
+	highlightEnd     = "</mark>"
+	snippetStart     = "\ue000"
+	snippetEnd       = "\ue001"
+	snippetEllipsis  = "…"
+	snippetTokens    = 12
+	snippetRadius    = 60
+	searchDateFormat = "2006-01-02 15:04:05"
+	searchFolderTree = `IN (WITH RECURSIVE subtree(id) AS (SELECT ? UNION SELECT folders.id FROM folders JOIN subtree ON folders.parent_id = subtree.id WHERE folders.deleted_at IS NULL) SELECT id FROM subtree)`
+)
+
+type searchSource struct {
+	kind         string
+	table        string
+	index        string
+	fields       []string
+	columns      string
+	dateColumn   string
+	folderColumn string
+	methodColumn string
+	statusColumn string
+	where        string
+}
+
+var searchSources = []searchSource{
+	{
+		kind:         models.SearchTypeRequest,
+		table:        "requests",
+		index:        "requests_fts",
+		fields:       []string{"name", "url", "headers", "body"},
+		columns:      `t.id, t.name, t.method, t.url, t.folder_id, 0, t.updated_at`,
+		dateColumn:   "t.updated_at",
+		folderColumn: "t.folder_id",
+		methodColumn: "t.method",
+		where:        "t.deleted_at IS NULL",
+	},
+	{
+		kind:         models.SearchTypeFolder,
+		table:        "folders",
+		index:        "folders_fts",
+		fields:       []string{"name"},
+		columns:      `t.id, t.name, '', '', t.parent_id, 0, t.updated_at`,
+		dateColumn:   "t.updated_at",
+		folderColumn: "t.parent_id",
+		where:        "t.deleted_at IS NULL",
+	},
+	{
+		kind:         models.SearchTypeHistory,
+		table:        "history",
+		index:        "history_fts",
+		fields:       []string{"url", "response_body"},
+		columns:      `t.id, '', t.method, t.url, (SELECT folder_id FROM requests WHERE requests.id = t.request_id), t.status_code, t.created_at`,
+		dateColumn:   "t.created_at",
+		folderColumn: "(SELECT folder_id FROM requests WHERE requests.id = t.request_id)",
+		methodColumn: "t.method",
+		statusColumn: "t.status_code",
+	},
+}
+
+func (db *DB) createSearchIndexes() error {
+	if err := db.QueryRow(`SELECT sqlite_compileoption_used('ENABLE_FTS5')`).Scan(&db.fts); err != nil {
+		return err
+	}
+	for _, source := range searchSources {
+		if !db.fts {
+			for _, trigger := range source.triggers() {
+				if _, err := db.Exec(`DROP TRIGGER IF EXISTS ` + trigger.name); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		var existing int
+		if err := db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'trigger' AND tbl_name = ? AND name LIKE ?`, source.table, source.index+"%").Scan(&existing); err != nil {
+			return err
+		}
+		if _, err := db.Exec(fmt.Sprintf(`CREATE VIRTUAL TABLE IF NOT EXISTS %s USING fts5(%s, content='%s', content_rowid='id')`, source.index, strings.Join(source.fields, ", "), source.table)); err != nil {
+			return err
+		}
+		if existing == len(source.triggers()) {
+			continue
+		}
+		db.log.Info("Building search index", slog.String("index", source.index))
+		if _, err := db.Exec(fmt.Sprintf(`INSERT INTO %s(%s) VALUES('rebuild')`, source.index, source.index)); err != nil {
+			return err
+		}
+		for _, trigger := range source.triggers() {
+			if _, err := db.Exec(trigger.query); err != nil {
+				return err
+			}
+		}
+	}
+	if !db.fts {
+		db.log.Info("Full-text search is unavailable, falling back to substring search")
+	}
+	return nil
+}
+
+type searchTrigger struct {
+	name  string
+	query string
+}
+
+func (s searchSource) triggers() []searchTrigger {
+	fields := strings.Join(s.fields, ", ")
+	newValues := "new." + strings.Join(s.fields, ", new.")
+	oldValues := "old." + strings.Join(s.fields, ", old.")
+	insert := fmt.Sprintf(`INSERT INTO %s(rowid, %s) VALUES (new.id, %s);`, s.index, fields, newValues)
+	remove := fmt.Sprintf(`INSERT INTO %s(%s, rowid, %s) VALUES ('delete', old.id, %s);`, s.index, s.index, fields, oldValues)
+	return []searchTrigger{
+		{name: s.index + "_insert", query: fmt.Sprintf(`CREATE TRIGGER IF NOT EXISTS %s_insert AFTER INSERT ON %s BEGIN %s END`, s.index, s.table, insert)},
+		{name: s.index + "_delete", query: fmt.Sprintf(`CREATE TRIGGER IF NOT EXISTS %s_delete AFTER DELETE ON %s BEGIN %s END`, s.index, s.table, remove)},
+		{name: s.index + "_update", query: fmt.Sprintf(`CREATE TRIGGER IF NOT EXISTS %s_update AFTER UPDATE OF %s ON %s BEGIN %s %s END`, s.index, fields, s.table, remove, insert)},
+	}
+}
+
+func (db *DB) Search(query *models.SearchQuery) ([]models.SearchResult, error) {
+	terms := strings.Fields(query.Query)
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("search query is required")
+	}
+	highlight := highlightPattern(terms)
+	results := []models.SearchResult{}
+	var relevance []float64
+	for _, source := range searchSources {
+		if (query.Method != "" && source.methodColumn == "") || (query.StatusCode != 0 && source.statusColumn == "") {
+			continue
+		}
+		sqlQuery, args := source.query(query, terms, db.fts)
+		rows, err := db.Query(sqlQuery, args...)
+		if err != nil {
+			db.log.Error("Failed to search", slog.String("source", source.kind), slog.String("error", err.Error()))
+			return nil, err
+		}
+		found, ranks, err := source.scan(rows, db.fts, highlight)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, found...)
+		relevance = append(relevance, normalizeRanks(ranks)...)
+	}
+	order := make([]int, len(results))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := order[i], order[j]
+		if relevance[a] != relevance[b] {
+			return relevance[a] > relevance[b]
+		}
+		return results[a].Date.After(results[b].Date)
+	})
+	sorted := make([]models.SearchResult, 0, min(len(order), query.Limit))
+	for _, i := range order {
+		if len(sorted) == query.Limit {
+			break
+		}
+		sorted = append(sorted, results[i])
+	}
+	return sorted, nil
+}
+
+func (s searchSource) query(query *models.SearchQuery, terms []string, fts bool) (string, []any) {
+	var conditions []string
+	var args []any
+	var selectColumns, from, order string
+	if fts {
+		selectColumns = fmt.Sprintf(`%s, snippet(%s, -1, ?, ?, ?, ?), rank`, s.columns, s.index)
+		args = append(args, snippetStart, snippetEnd, snippetEllipsis, snippetTokens)
+		from = fmt.Sprintf(`%s JOIN %s AS t ON t.id = %s.rowid`, s.index, s.table, s.index)
+		conditions = append(conditions, s.index+" MATCH ?")
+		args = append(args, matchExpression(terms))
+		order = "rank"
+	} else {
+		selectColumns = s.columns + ", t." + strings.Join(s.fields, ", t.")
+		from = s.table + " AS t"
+		for _, term := range terms {
+			var matches []string
+			for _, field := range s.fields {
+				matches = append(matches, "t."+field+` LIKE ? ESCAPE '\'`)
+				args = append(args, "%"+escapeLike(term)+"%")
+			}
+			conditions = append(conditions, "("+strings.Join(matches, " OR ")+")")
+		}
+		order = s.dateColumn + " DESC"
+	}
+	if s.where != "" {
+		conditions = append(conditions, s.where)
+	}
+	if query.Method != "" {
+		conditions = append(conditions, "UPPER("+s.methodColumn+") = ?")
+		args = append(args, strings.ToUpper(query.Method))
+	}
+	if query.StatusCode != 0 {
+		conditions = append(conditions, s.statusColumn+" = ?")
+		args = append(args, query.StatusCode)
+	}
+	if query.FolderID != nil {
+		conditions = append(conditions, s.folderColumn+" "+searchFolderTree)
+		args = append(args, *query.FolderID)
+	}
+	if query.From != nil {
+		conditions = append(conditions, s.dateColumn+" >= ?")
+		args = append(args, query.From.UTC().Format(searchDateFormat))
+	}
+	if query.To != nil {
+		conditions = append(conditions, s.dateColumn+" < ?")
+		args = append(args, query.To.UTC().Format(searchDateFormat))
+	}
+	args = append(args, query.Limit)
+	return fmt.Sprintf(`SELECT %s FROM %s WHERE %s ORDER BY %s LIMIT ?`, selectColumns, from, strings.Join(conditions, " AND "), order), args
+}
+
+func (s searchSource) scan(rows *sql.Rows, fts bool, highlight *regexp.Regexp) ([]models.SearchResult, []float64, error) {
+	defer rows.Close()
+	var results []models.SearchResult
+	var ranks []float64
+	for rows.Next() {
+		result := models.SearchResult{Type: s.kind}
+		var method, url sql.NullString
+		dest := []any{&result.ID, &result.Name, &method, &url, &result.FolderID, &result.StatusCode, &result.Date}
+		var rank float64
+		fields := make([]sql.NullString, len(s.fields))
+		if fts {
+			dest = append(dest, &result.Snippet, &rank)
+		} else {
+			for i := range fields {
+				dest = append(dest, &fields[i])
+			}
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, nil, err
+		}
+		result.Method = method.String
+		result.URL = url.String
+		if fts {
+			result.Snippet = markSnippet(result.Snippet)
+		} else {
+			for _, field := range fields {
+				if snippet, ok := highlightSnippet(field.String, highlight); ok {
+					result.Snippet = snippet
+					break
+				}
+			}
+		}
+		results = append(results, result)
+		ranks = append(ranks, rank)
+	}
+	return results, ranks, rows.Err()
+}
+
+func normalizeRanks(ranks []float64) []float64 {
+	best := 0.0
+	for _, rank := range ranks {
+		best = min(best, rank)
+	}
+	relevance := make([]float64, len(ranks))
+	for i, rank := range ranks {
+		relevance[i] = 1
+		if best < 0 {
+			relevance[i] = rank / best
+		}
+	}
+	return relevance
+}
+
+func markSnippet(snippet string) string {
+	return strings.NewReplacer(snippetStart, highlightStart, snippetEnd, highlightEnd).Replace(html.EscapeString(snippet))
+}
+
+func matchExpression(terms []string) string {
+	quoted := make([]string, len(terms))
+	for i, term := range terms {
+		quoted[i] = `"` + strings.ReplaceAll(term, `"`, `""`) + `"*`
+	}
+	return strings.Join(quoted, " ")
+}
+
+func escapeLike(term string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(term)
+}
+
+func highlightPattern(terms []string) *regexp.Regexp {
+	quoted := make([]string, len(terms))
+	for i, term := range terms {
+		quoted[i] = regexp.QuoteMeta(term)
+	}
+	return regexp.MustCompile(`(?i)` + strings.Join(quoted, "|"))
+}
+
+func highlightSnippet(text string, pattern *regexp.Regexp) (string, bool) {
+	match := pattern.FindStringIndex(text)
+	if match == nil {
+		return "", false
+	}
+	start, end := max(match[0]-snippetRadius, 0), min(match[1]+snippetRadius, len(text))
+	for start > 0 && !utf8.RuneStart(text[start]) {
+		start--
+	}
+	for end < len(text) && !utf8.RuneStart(text[end]) {
+		end++
+	}
+	var b strings.Builder
+	last := start
+	for _, m := range pattern.FindAllStringIndex(text[start:end], -1) {
+		b.WriteString(html.EscapeString(text[last : start+m[0]]))
+		b.WriteString(highlightStart + html.EscapeString(text[start+m[0]:start+m[1]]) + highlightEnd)
+		last = start + m[1]
+	}
+	b.WriteString(html.EscapeString(text[last:end]))
+	snippet := b.String()
+	if start > 0 {
+		snippet = snippetEllipsis + snippet
+	}
+	if end < len(text) {
+		snippet += snippetEllipsis
+	}
+	return snippet, true
+}
@@ -0,0 +1,186 @@
+package storage
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hc/hc/internal/models"
+)
+
+func TestSearch(t *testing.T) {
+	db := setupTestDB(t)
+
+	billing := createTestFolder(t, db, "Billing", nil)
+	invoices := createTestFolder(t, db, "Invoices", &billing.ID)
+	create := &models.Request{
+		Name:     "Create invoice",
+		FolderID: &invoices.ID,
+		Method:   "POST",
+		URL:      "https://api.example.com/v1/invoices",
+		Body:     `{"amount": 42, "currency": "EUR"}`,
+	}
+	users := &models.Request{
+		Name:    "List users",
+		Method:  "GET",
+		URL:     "https://api.example.com/users",
+		Headers: map[string]string{"X-Tenant": "acme"},
+	}
+	deleted := &models.Request{Name: "Old invoice", Method: "POST", URL: "https://api.example.com/v0/invoices"}
+	for _, request := range []*models.Request{create, users, deleted} {
+		if err := db.CreateRequest(request); err != nil {
+			t.Fatalf("CreateRequest() error = %v", err)
+		}
+	}
+	if err := db.DeleteRequest(deleted.ID); err != nil {
+		t.Fatalf("DeleteRequest() error = %v", err)
+	}
+	found := &models.HistoryEntry{RequestID: &users.ID, Method: "GET", URL: users.URL, StatusCode: 200, ResponseBody: `[{"tenant": "acme"}]`}
+	failed := &models.HistoryEntry{Method: "GET", URL: "https://api.example.com/health", StatusCode: 500, ResponseBody: "internal failure"}
+	for _, entry := range []*models.HistoryEntry{found, failed} {
+		if err := db.CreateHistoryEntry(entry); err != nil {
+			t.Fatalf("CreateHistoryEntry() error = %v", err)
+		}
+	}
+	past := time.Now().Add(-48 * time.Hour)
+
+	tests := []struct {
+		name  string
+		query models.SearchQuery
+		want  []string
+	}{
+		{name: "Matches names and URLs", query: models.SearchQuery{Query: "invoices"}, want: []string{key(models.SearchTypeFolder, invoices.ID), key(models.SearchTypeRequest, create.ID)}},
+		{name: "Matches bodies", query: models.SearchQuery{Query: "EUR"}, want: []string{key(models.SearchTypeRequest, create.ID)}},
+		{name: "Matches headers and history responses", query: models.SearchQuery{Query: "acme"}, want: []string{key(models.SearchTypeHistory, found.ID), key(models.SearchTypeRequest, users.ID)}},
+		{name: "Filters by status", query: models.SearchQuery{Query: "acme", StatusCode: 200}, want: []string{key(models.SearchTypeHistory, found.ID)}},
+		{name: "Filters by method", query: models.SearchQuery{Query: "acme", Method: "post"}, want: nil},
+		{name: "Filters by folder subtree", query: models.SearchQuery{Query: "invoice", FolderID: &billing.ID}, want: []string{key(models.SearchTypeFolder, invoices.ID), key(models.SearchTypeRequest, create.ID)}},
+		{name: "Filters by date range", query: models.SearchQuery{Query: "acme", To: &past}, want: nil},
+		{name: "Matches URL fragments", query: models.SearchQuery{Query: "v1/invoices"}, want: []string{key(models.SearchTypeRequest, create.ID)}},
+		{name: "Requires all terms", query: models.SearchQuery{Query: "internal failure"}, want: []string{key(models.SearchTypeHistory, failed.ID)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.query.Limit = 50
+			results, err := db.Search(&tt.query)
+			if err != nil {
+				t.Fatalf("Search() error = %v", err)
+			}
+			var got []string
+			for _, result := range results {
+				got = append(got, key(result.Type, result.ID))
+				if !strings.Contains(result.Snippet, highlightStart) {
+					t.Errorf("Expected highlighted snippet for %s, got %q", key(result.Type, result.ID), result.Snippet)
+				}
+			}
+			sort.Strings(got)
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("Expected results %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	if _, err := db.Search(&models.SearchQuery{Query: "  ", Limit: 10}); err == nil {
+		t.Error("Expected error for empty query")
+	}
+}
+
+func TestSearchIndexFollowsUpdates(t *testing.T) {
+	db := setupTestDB(t)
+
+	request := createTestRequest(t, db, "Original", nil)
+	request.Name = "Renamed"
+	if err := db.UpdateRequest(request); err != nil {
+		t.Fatalf("UpdateRequest() error = %v", err)
+	}
+
+	tests := []struct {
+		query string
+		want  int
+	}{
+		{query: "Original", want: 0},
+		{query: "Renamed", want: 1},
+	}
+	for _, tt := range tests {
+		results, err := db.Search(&models.SearchQuery{Query: tt.query, Limit: 10})
+		if err != nil {
+			t.Fatalf("Search() error = %v", err)
+		}
+		if len(results) != tt.want {
+			t.Errorf("Expected %d results for %q, got %d", tt.want, tt.query, len(results))
+		}
+	}
+}
+
+func TestHighlightSnippet(t *testing.T) {
+	long := strings.Repeat("a", 100) + " token " + strings.Repeat("b", 100)
+	tests := []struct {
+		name string
+		text string
+		want string
+		ok   bool
+	}{
+		{name: "Short text", text: "find the Token here", want: "find the <mark>Token</mark> here", ok: true},
+		{name: "Long text is trimmed", text: long, want: snippetEllipsis + strings.Repeat("a", 59) + " <mark>token</mark> " + strings.Repeat("b", 59) + snippetEllipsis, ok: true},
+		{name: "Surrounding text is escaped", text: `<img src=x onerror="alert(1)"> token & <b>`, want: `&lt;img src=x onerror=&#34;alert(1)&#34;&gt; <mark>token</mark> &amp; &lt;b&gt;`, ok: true},
+		{name: "No match", text: "nothing", ok: false},
+	}
+	pattern := highlightPattern([]string{"token"})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := highlightSnippet(tt.text, pattern)
+			if ok != tt.ok || got != tt.want {
+				t.Errorf("Expected %q (%v), got %q (%v)", tt.want, tt.ok, got, ok)
+			}
+		})
+	}
+}
+
+func TestSearchEscapesSnippets(t *testing.T) {
+	db := setupTestDB(t)
+
+	request := &models.Request{Name: "Render", Method: "POST", URL: "https://example.com", Body: `<script>alert("marker")</script>`}
+	if err := db.CreateRequest(request); err != nil {
+		t.Fatalf("CreateRequest() error = %v", err)
+	}
+	results, err := db.Search(&models.SearchQuery{Query: "marker", Limit: 10})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 result, got %d", len(results))
+	}
+	if strings.Contains(results[0].Snippet, "<script>") || !strings.Contains(results[0].Snippet, "&lt;script&gt;") {
+		t.Errorf("Expected escaped snippet, got %q", results[0].Snippet)
+	}
+	if !strings.Contains(results[0].Snippet, highlightStart+"marker"+highlightEnd) {
+		t.Errorf("Expected highlighted match, got %q", results[0].Snippet)
+	}
+}
+
+func TestNormalizeRanks(t *testing.T) {
+	tests := []struct {
+		name  string
+		ranks []float64
+		want  []float64
+	}{
+		{name: "Best match scores one", ranks: []float64{-8, -4, -2}, want: []float64{1, 0.5, 0.25}},
+		{name: "Unranked results score one", ranks: []float64{0, 0}, want: []float64{1, 1}},
+		{name: "Empty", ranks: nil, want: []float64{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := normalizeRanks(tt.ranks)
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func key(kind string, id int) string {
+	return fmt.Sprintf("%s:%d", kind, id)
+}
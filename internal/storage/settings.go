@@ -0,0 +1,52 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"log/slog"
+
+	"github.com/hc/hc/internal/models"
+)
+
+const (
+	createSettingsTableQuery = `
+		CREATE TABLE IF NOT EXISTS settings (
+			id INTEGER PRIMARY KEY CHECK (id = 1),
+			data TEXT NOT NULL,
+			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)`
+	selectSettingsQuery = `SELECT data FROM settings WHERE id = 1`
+	upsertSettingsQuery = `
+		INSERT INTO settings (id, data) VALUES (1, ?)
+		ON CONFLICT(id) DO UPDATE SET data = excluded.data, updated_at = CURRENT_TIMESTAMP`
+)
+
+func (db *DB) GetSettings() (*models.Settings, error) {
+	var data string
+	err := db.QueryRow(selectSettingsQuery).Scan(&data)
+	if err == sql.ErrNoRows {
+		return &models.Settings{}, nil
+	}
+	if err != nil {
+		db.log.Error("Failed to get settings", slog.String("error", err.Error()))
+		return nil, err
+	}
+	var settings models.Settings
+	if err := json.Unmarshal([]byte(data), &settings); err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+func (db *DB) UpdateSettings(settings *models.Settings) error {
+	db.log.Info("Updating settings")
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return err
+	}
+	if _, err := db.Exec(upsertSettingsQuery, string(data)); err != nil {
+		db.log.Error("Failed to update settings", slog.String("error", err.Error()))
+		return err
+	}
+	return nil
+}
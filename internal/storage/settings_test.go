@@ -0,0 +1,65 @@
+package storage
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hc/hc/internal/models"
+)
+
+func TestGetSettingsDefault(t *testing.T) {
+	db := setupTestDB(t)
+
+	settings, err := db.GetSettings()
+	if err != nil {
+		t.Fatalf("GetSettings() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(settings, &models.Settings{}) {
+		t.Errorf("Expected empty settings, got %+v", settings)
+	}
+}
+
+func TestUpdateSettings(t *testing.T) {
+	db := setupTestDB(t)
+
+	want := &models.Settings{
+		Proxy: models.ProxySettings{
+			Mode:     models.ProxyModeManual,
+			URL:      "http://proxy.local:3128",
+			Username: "alice",
+			Password: "s3cret",
+			NoProxy:  []string{"localhost", ".corp.local"},
+		},
+	}
+	if err := db.UpdateSettings(want); err != nil {
+		t.Fatalf("UpdateSettings() error = %v", err)
+	}
+
+	got, err := db.GetSettings()
+	if err != nil {
+		t.Fatalf("GetSettings() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetSettings() = %+v, want %+v", got, want)
+	}
+
+	// Updating again replaces the stored settings
+	want.Proxy = models.ProxySettings{Mode: models.ProxyModeNone}
+	if err := db.UpdateSettings(want); err != nil {
+		t.Fatalf("UpdateSettings() error = %v", err)
+	}
+	got, err = db.GetSettings()
+	if err != nil {
+		t.Fatalf("GetSettings() error = %v", err)
+	}
+	if got.Proxy.Mode != models.ProxyModeNone || got.Proxy.URL != "" {
+		t.Errorf("Expected proxy mode none, got %+v", got.Proxy)
+	}
+
+	var count int
+	db.QueryRow("SELECT COUNT(*) FROM settings").Scan(&count)
+	if count != 1 {
+		t.Errorf("Expected a single settings row, got %d", count)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"math"
 	"os"
 	"path/filepath"
 
@@ -37,21 +38,51 @@ const (
 			updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 			FOREIGN KEY (folder_id) REFERENCES folders(id) ON DELETE SET NULL
 		)`
-	insertFolderQuery   = `INSERT INTO folders (name, parent_id) VALUES (?, ?)`
-	selectFolderQuery   = `SELECT id, name, parent_id, created_at, updated_at FROM folders WHERE id = ?`
-	selectFoldersQuery  = `SELECT id, name, parent_id, created_at, updated_at FROM folders ORDER BY name`
-	updateFolderQuery   = `UPDATE folders SET name = ?, parent_id = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?`
-	deleteFolderQuery   = `DELETE FROM folders WHERE id = ?`
-	insertRequestQuery  = `INSERT INTO requests (name, folder_id, method, url, headers, body) VALUES (?, ?, ?, ?, ?, ?)`
-	selectRequestQuery  = `SELECT id, name, folder_id, method, url, headers, body, created_at, updated_at FROM requests WHERE id = ?`
-	selectRequestsQuery = `SELECT id, name, folder_id, method, url, headers, body, created_at, updated_at FROM requests ORDER BY updated_at DESC`
-	updateRequestQuery  = `UPDATE requests SET name = ?, folder_id = ?, method = ?, url = ?, headers = ?, body = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?`
-	deleteRequestQuery  = `DELETE FROM requests WHERE id = ?`
+	folderColumns       = `id, name, description, parent_id, headers, auth, base_url, variables, host_overrides, position, deleted_at, created_at, updated_at`
+	insertFolderQuery   = `INSERT INTO folders (name, description, parent_id, headers, auth, base_url, variables, host_overrides, position) VALUES (?, ?, ?, ?, ?, ?, ?, ?, (SELECT COALESCE(MAX(position), -1) + 1 FROM folders WHERE parent_id IS ? AND deleted_at IS NULL))`
+	selectFolderQuery   = `SELECT ` + folderColumns + ` FROM folders WHERE id = ? AND deleted_at IS NULL`
+	selectFoldersQuery  = `SELECT ` + folderColumns + ` FROM folders WHERE deleted_at IS NULL ORDER BY position, name`
+	updateFolderQuery   = `UPDATE folders SET name = ?, description = ?, parent_id = ?, headers = ?, auth = ?, base_url = ?, variables = ?, host_overrides = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ? AND deleted_at IS NULL`
+	requestColumns      = `id, name, description, folder_id, type, method, url, headers, body, compression, protocol, auth, graphql, grpc, favorite, position, deleted_at, created_at, updated_at`
+	insertRequestQuery  = `INSERT INTO requests (name, description, folder_id, type, method, url, headers, body, compression, protocol, auth, graphql, grpc, favorite, position) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, (SELECT COALESCE(MAX(position), -1) + 1 FROM requests WHERE folder_id IS ? AND deleted_at IS NULL))`
+	selectRequestQuery  = `SELECT ` + requestColumns + ` FROM requests WHERE id = ? AND deleted_at IS NULL`
+	selectRequestsQuery = `SELECT ` + requestColumns + ` FROM requests WHERE deleted_at IS NULL ORDER BY updated_at DESC`
+	updateRequestQuery  = `UPDATE requests SET name = ?, description = ?, folder_id = ?, type = ?, method = ?, url = ?, headers = ?, body = ?, compression = ?, protocol = ?, auth = ?, graphql = ?, grpc = ?, favorite = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ? AND deleted_at IS NULL`
 )
 
+type columnMigration struct {
+	table      string
+	column     string
+	definition string
+}
+
+var columnMigrations = []columnMigration{
+	{table: "requests", column: "type", definition: "TEXT NOT NULL DEFAULT 'http'"},
+	{table: "requests", column: "graphql", definition: "TEXT"},
+	{table: "requests", column: "grpc", definition: "TEXT"},
+	{table: "requests", column: "compression", definition: "TEXT NOT NULL DEFAULT ''"},
+	{table: "requests", column: "protocol", definition: "TEXT NOT NULL DEFAULT ''"},
+	{table: "requests", column: "auth", definition: "TEXT"},
+	{table: "folders", column: "headers", definition: "TEXT"},
+	{table: "folders", column: "auth", definition: "TEXT"},
+	{table: "folders", column: "base_url", definition: "TEXT NOT NULL DEFAULT ''"},
+	{table: "folders", column: "variables", definition: "TEXT"},
+	{table: "folders", column: "position", definition: "INTEGER NOT NULL DEFAULT 0"},
+	{table: "requests", column: "position", definition: "INTEGER NOT NULL DEFAULT 0"},
+	{table: "folders", column: "deleted_at", definition: "DATETIME"},
+	{table: "requests", column: "deleted_at", definition: "DATETIME"},
+	{table: "requests", column: "favorite", definition: "INTEGER NOT NULL DEFAULT 0"},
+	{table: "folders", column: "description", definition: "TEXT NOT NULL DEFAULT ''"},
+	{table: "requests", column: "description", definition: "TEXT NOT NULL DEFAULT ''"},
+	{table: "folders", column: "host_overrides", definition: "TEXT"},
+	{table: "history", column: "error_code", definition: "TEXT NOT NULL DEFAULT ''"},
+	{table: "history", column: "streamed", definition: "INTEGER NOT NULL DEFAULT 0"},
+}
+
 type DB struct {
 	*sql.DB
 	log *slog.Logger
+	fts bool
 }
 
 func InitDB() (*DB, error) {
@@ -99,14 +130,71 @@ func defaultGetDBPath() (string, error) {
 }
 
 func (db *DB) createTables() error {
-	for _, query := range []string{createFoldersTableQuery, createRequestsTableQuery} {
+	for _, query := range []string{
+		createFoldersTableQuery,
+		createRequestsTableQuery,
+		createSettingsTableQuery,
+		createWebSocketSessionsTableQuery,
+		createWebSocketMessagesTableQuery,
+		createGraphQLSchemasTableQuery,
+		createGRPCProtoSetsTableQuery,
+		createHistoryTableQuery,
+		createRequestRevisionsTableQuery,
+		createRequestTagsTableQuery,
+		createRequestExamplesTableQuery,
+	} {
 		if _, err := db.Exec(query); err != nil {
 			return err
 		}
 	}
+	if err := db.migrateColumns(); err != nil {
+		return err
+	}
+	return db.createSearchIndexes()
+}
+
+func (db *DB) migrateColumns() error {
+	for _, m := range columnMigrations {
+		exists, err := db.columnExists(m.table, m.column)
+		if err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+		db.log.Info("Adding column", slog.String("table", m.table), slog.String("column", m.column))
+		if _, err := db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", m.table, m.column, m.definition)); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+func (db *DB) columnExists(table, column string) (bool, error) {
+	rows, err := db.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			cid        int
+			name       string
+			columnType string
+			notNull    int
+			defaultVal sql.NullString
+			primaryKey int
+		)
+		if err := rows.Scan(&cid, &name, &columnType, &notNull, &defaultVal, &primaryKey); err != nil {
+			return false, err
+		}
+		if name == column {
+			return true, nil
+		}
+	}
+	return false, rows.Err()
+}
+
 func (db *DB) WithTx(ctx context.Context, fn func(*sql.Tx) error) error {
 	tx, err := db.BeginTx(ctx, nil)
 	if err != nil {
@@ -126,7 +214,11 @@ func (db *DB) WithTx(ctx context.Context, fn func(*sql.Tx) error) error {
 
 func (db *DB) CreateFolder(folder *models.Folder) error {
 	db.log.Info("Creating folder", slog.String("name", folder.Name))
-	result, err := db.Exec(insertFolderQuery, folder.Name, folder.ParentID)
+	args, err := folderArgs(folder)
+	if err != nil {
+		return err
+	}
+	result, err := db.Exec(insertFolderQuery, append(args, folder.ParentID)...)
 	if err != nil {
 		db.log.Error("Failed to create folder", slog.String("error", err.Error()))
 		return err
@@ -140,21 +232,19 @@ func (db *DB) CreateFolder(folder *models.Folder) error {
 }
 
 func (db *DB) GetFolder(id int, folder *models.Folder) error {
-	err := db.QueryRow(selectFolderQuery, id).Scan(
-		&folder.ID,
-		&folder.Name,
-		&folder.ParentID,
-		&folder.CreatedAt,
-		&folder.UpdatedAt,
-	)
-	if err == sql.ErrNoRows {
-		return fmt.Errorf("folder not found")
-	}
+	err := getFolder(db, id, folder)
 	if err != nil {
 		db.log.Error("Failed to get folder", slog.Int("id", id), slog.String("error", err.Error()))
-		return err
 	}
-	return nil
+	return err
+}
+
+func getFolder(q rowQuerier, id int, folder *models.Folder) error {
+	err := scanFolder(q.QueryRow(selectFolderQuery, id), folder)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("folder not found")
+	}
+	return err
 }
 
 func (db *DB) GetFolders() ([]models.Folder, error) {
@@ -167,13 +257,7 @@ func (db *DB) GetFolders() ([]models.Folder, error) {
 	var folders []models.Folder
 	for rows.Next() {
 		var folder models.Folder
-		if err := rows.Scan(
-			&folder.ID,
-			&folder.Name,
-			&folder.ParentID,
-			&folder.CreatedAt,
-			&folder.UpdatedAt,
-		); err != nil {
+		if err := scanFolder(rows, &folder); err != nil {
 			return nil, err
 		}
 		folders = append(folders, folder)
@@ -184,92 +268,90 @@ func (db *DB) GetFolders() ([]models.Folder, error) {
 	return folders, nil
 }
 
-func (db *DB) UpdateFolder(folder *models.Folder) error {
-	db.log.Info("Updating folder", slog.Int("id", folder.ID))
-	result, err := db.Exec(updateFolderQuery, folder.Name, folder.ParentID, folder.ID)
-	if err != nil {
-		db.log.Error("Failed to update folder", slog.String("error", err.Error()))
-		return err
-	}
-	rows, err := result.RowsAffected()
-	if err != nil {
-		return err
-	}
-	if rows == 0 {
-		return fmt.Errorf("folder not found")
+func (db *DB) GetFolderChain(id int) ([]models.Folder, error) {
+	var chain []models.Folder
+	seen := make(map[int]bool)
+	next := &id
+	for next != nil {
+		if seen[*next] {
+			return nil, fmt.Errorf("folder cycle detected at folder %d", *next)
+		}
+		seen[*next] = true
+		var folder models.Folder
+		if err := db.GetFolder(*next, &folder); err != nil {
+			return nil, err
+		}
+		chain = append([]models.Folder{folder}, chain...)
+		next = folder.ParentID
 	}
-	return nil
+	return chain, nil
 }
 
-func (db *DB) DeleteFolder(id int) error {
-	db.log.Info("Deleting folder", slog.Int("id", id))
-	result, err := db.Exec(deleteFolderQuery, id)
-	if err != nil {
-		db.log.Error("Failed to delete folder", slog.String("error", err.Error()))
-		return err
-	}
-	rows, err := result.RowsAffected()
+func (db *DB) UpdateFolder(folder *models.Folder) error {
+	db.log.Info("Updating folder", slog.Int("id", folder.ID))
+	args, err := folderArgs(folder)
 	if err != nil {
 		return err
 	}
-	if rows == 0 {
-		return fmt.Errorf("folder not found")
-	}
-	return nil
+	return db.WithTx(context.Background(), func(tx *sql.Tx) error {
+		var parentID *int
+		err := tx.QueryRow(selectFolderParentQuery, folder.ID).Scan(&parentID)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("folder not found")
+		}
+		if err != nil {
+			return err
+		}
+		if !equalParent(parentID, folder.ParentID) {
+			if err := checkFolderParent(tx, folder.ID, folder.ParentID); err != nil {
+				return err
+			}
+			if err := folderOrder.move(tx, folder.ID, models.MoveTarget{ParentID: folder.ParentID, Position: math.MaxInt}); err != nil {
+				return err
+			}
+		}
+		if _, err := tx.Exec(updateFolderQuery, append(args, folder.ID)...); err != nil {
+			db.log.Error("Failed to update folder", slog.String("error", err.Error()))
+			return err
+		}
+		return nil
+	})
 }
 
 func (db *DB) CreateRequest(request *models.Request) error {
 	db.log.Info("Creating request", slog.String("name", request.Name))
-	headersJSON, err := serializeHeaders(request.Headers)
-	if err != nil {
-		return err
-	}
-	result, err := db.Exec(insertRequestQuery,
-		request.Name,
-		request.FolderID,
-		request.Method,
-		request.URL,
-		headersJSON,
-		request.Body,
-	)
+	err := db.WithTx(context.Background(), func(tx *sql.Tx) error {
+		return insertRequest(tx, request)
+	})
 	if err != nil {
 		db.log.Error("Failed to create request", slog.String("error", err.Error()))
 		return err
 	}
-	id, err := result.LastInsertId()
-	if err != nil {
-		return err
-	}
-	request.ID = int(id)
 	return db.GetRequest(request.ID, request)
 }
 
 func (db *DB) GetRequest(id int, request *models.Request) error {
-	var headersStr string
-	err := db.QueryRow(selectRequestQuery, id).Scan(
-		&request.ID,
-		&request.Name,
-		&request.FolderID,
-		&request.Method,
-		&request.URL,
-		&headersStr,
-		&request.Body,
-		&request.CreatedAt,
-		&request.UpdatedAt,
-	)
+	err := getRequest(db, id, request)
+	if err != nil {
+		db.log.Error("Failed to get request", slog.Int("id", id), slog.String("error", err.Error()))
+	}
+	return err
+}
+
+func getRequest(q readQuerier, id int, request *models.Request) error {
+	err := scanRequest(q.QueryRow(selectRequestQuery, id), request)
 	if err == sql.ErrNoRows {
 		return fmt.Errorf("request not found")
 	}
 	if err != nil {
-		db.log.Error("Failed to get request", slog.Int("id", id), slog.String("error", err.Error()))
 		return err
 	}
-	headers, err := deserializeHeaders(headersStr)
+	request.Tags, err = requestTags(q, id)
 	if err != nil {
 		return err
 	}
-	request.Headers = headers
-	return nil
+	request.Examples, err = queryExamples(q, selectExamplesQuery, id)
+	return err
 }
 
 func (db *DB) GetRequests() ([]models.Request, error) {
@@ -282,76 +364,182 @@ func (db *DB) GetRequests() ([]models.Request, error) {
 	var requests []models.Request
 	for rows.Next() {
 		var request models.Request
-		var headersStr string
-		if err := rows.Scan(
-			&request.ID,
-			&request.Name,
-			&request.FolderID,
-			&request.Method,
-			&request.URL,
-			&headersStr,
-			&request.Body,
-			&request.CreatedAt,
-			&request.UpdatedAt,
-		); err != nil {
+		if err := scanRequest(rows, &request); err != nil {
 			return nil, err
 		}
-		headers, err := deserializeHeaders(headersStr)
-		if err != nil {
-			return nil, fmt.Errorf("failed to deserialize headers: %w", err)
-		}
-		request.Headers = headers
 		requests = append(requests, request)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
+	if err := attachTags(db, requests); err != nil {
+		return nil, err
+	}
+	if err := attachExamples(db, requests); err != nil {
+		return nil, err
+	}
 	return requests, nil
 }
 
-func (db *DB) UpdateRequest(request *models.Request) error {
-	db.log.Info("Updating request", slog.Int("id", request.ID))
-	headersJSON, err := serializeHeaders(request.Headers)
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func folderArgs(folder *models.Folder) ([]any, error) {
+	headersJSON, err := serializeHeaders(folder.Headers)
+	if err != nil {
+		return nil, err
+	}
+	authJSON, err := serializeOptionalJSON(folder.Auth)
+	if err != nil {
+		return nil, err
+	}
+	variablesJSON, err := serializeHeaders(folder.Variables)
 	if err != nil {
+		return nil, err
+	}
+	hostOverridesJSON, err := serializeHeaders(folder.HostOverrides)
+	if err != nil {
+		return nil, err
+	}
+	return []any{folder.Name, folder.Description, folder.ParentID, headersJSON, authJSON, folder.BaseURL, variablesJSON, hostOverridesJSON}, nil
+}
+
+func scanFolder(row rowScanner, folder *models.Folder) error {
+	var (
+		headersStr   sql.NullString
+		authStr      sql.NullString
+		variablesStr sql.NullString
+		overridesStr sql.NullString
+	)
+	if err := row.Scan(
+		&folder.ID,
+		&folder.Name,
+		&folder.Description,
+		&folder.ParentID,
+		&headersStr,
+		&authStr,
+		&folder.BaseURL,
+		&variablesStr,
+		&overridesStr,
+		&folder.Position,
+		&folder.DeletedAt,
+		&folder.CreatedAt,
+		&folder.UpdatedAt,
+	); err != nil {
 		return err
 	}
-	result, err := db.Exec(updateRequestQuery,
+	headers, err := deserializeHeaders(headersStr.String)
+	if err != nil {
+		return fmt.Errorf("failed to deserialize folder headers: %w", err)
+	}
+	folder.Headers = headers
+	auth, err := deserializeOptionalJSON[models.Auth](authStr)
+	if err != nil {
+		return fmt.Errorf("failed to deserialize folder auth: %w", err)
+	}
+	folder.Auth = auth
+	variables, err := deserializeHeaders(variablesStr.String)
+	if err != nil {
+		return fmt.Errorf("failed to deserialize folder variables: %w", err)
+	}
+	folder.Variables = variables
+	hostOverrides, err := deserializeHeaders(overridesStr.String)
+	if err != nil {
+		return fmt.Errorf("failed to deserialize folder host overrides: %w", err)
+	}
+	folder.HostOverrides = hostOverrides
+	return nil
+}
+
+func requestArgs(request *models.Request) ([]any, error) {
+	headersJSON, err := serializeHeaders(request.Headers)
+	if err != nil {
+		return nil, err
+	}
+	authJSON, err := serializeOptionalJSON(request.Auth)
+	if err != nil {
+		return nil, err
+	}
+	graphQLJSON, err := serializeOptionalJSON(request.GraphQL)
+	if err != nil {
+		return nil, err
+	}
+	grpcJSON, err := serializeOptionalJSON(request.GRPC)
+	if err != nil {
+		return nil, err
+	}
+	if request.Type == "" {
+		request.Type = models.RequestTypeHTTP
+	}
+	return []any{
 		request.Name,
+		request.Description,
 		request.FolderID,
+		request.Type,
 		request.Method,
 		request.URL,
 		headersJSON,
 		request.Body,
-		request.ID,
+		request.Compression,
+		request.Protocol,
+		authJSON,
+		graphQLJSON,
+		grpcJSON,
+		request.Favorite,
+	}, nil
+}
+
+func scanRequest(row rowScanner, request *models.Request) error {
+	var (
+		headersStr string
+		authStr    sql.NullString
+		graphQLStr sql.NullString
+		grpcStr    sql.NullString
 	)
-	if err != nil {
-		db.log.Error("Failed to update request", slog.String("error", err.Error()))
+	if err := row.Scan(
+		&request.ID,
+		&request.Name,
+		&request.Description,
+		&request.FolderID,
+		&request.Type,
+		&request.Method,
+		&request.URL,
+		&headersStr,
+		&request.Body,
+		&request.Compression,
+		&request.Protocol,
+		&authStr,
+		&graphQLStr,
+		&grpcStr,
+		&request.Favorite,
+		&request.Position,
+		&request.DeletedAt,
+		&request.CreatedAt,
+		&request.UpdatedAt,
+	); err != nil {
 		return err
 	}
-	rows, err := result.RowsAffected()
+	headers, err := deserializeHeaders(headersStr)
 	if err != nil {
-		return err
-	}
-	if rows == 0 {
-		return fmt.Errorf("request not found")
+		return fmt.Errorf("failed to deserialize headers: %w", err)
 	}
-	return nil
-}
-
-func (db *DB) DeleteRequest(id int) error {
-	db.log.Info("Deleting request", slog.Int("id", id))
-	result, err := db.Exec(deleteRequestQuery, id)
+	request.Headers = headers
+	auth, err := deserializeOptionalJSON[models.Auth](authStr)
 	if err != nil {
-		db.log.Error("Failed to delete request", slog.String("error", err.Error()))
-		return err
+		return fmt.Errorf("failed to deserialize auth: %w", err)
 	}
-	rows, err := result.RowsAffected()
+	request.Auth = auth
+	graphQL, err := deserializeOptionalJSON[models.GraphQLBody](graphQLStr)
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to deserialize graphql body: %w", err)
 	}
-	if rows == 0 {
-		return fmt.Errorf("request not found")
+	request.GraphQL = graphQL
+	grpc, err := deserializeOptionalJSON[models.GRPCRequest](grpcStr)
+	if err != nil {
+		return fmt.Errorf("failed to deserialize grpc request: %w", err)
 	}
+	request.GRPC = grpc
 	return nil
 }
 
@@ -376,3 +564,25 @@ func deserializeHeaders(headersStr string) (map[string]string, error) {
 	}
 	return headers, nil
 }
+
+func serializeOptionalJSON(value any) (sql.NullString, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+	if string(data) == "null" {
+		return sql.NullString{}, nil
+	}
+	return sql.NullString{String: string(data), Valid: true}, nil
+}
+
+func deserializeOptionalJSON[T any](data sql.NullString) (*T, error) {
+	if !data.Valid || data.String == "" {
+		return nil, nil
+	}
+	var value T
+	if err := json.Unmarshal([]byte(data.String), &value); err != nil {
+		return nil, err
+	}
+	return &value, nil
+}
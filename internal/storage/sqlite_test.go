@@ -53,7 +53,7 @@ func TestInitDB(t *testing.T) {
 	db := setupTestDB(t)
 
 	// Test that tables exist
-	tables := []string{"folders", "requests"}
+	tables := []string{"folders", "requests", "settings", "websocket_sessions", "websocket_messages", "graphql_schemas", "grpc_proto_sets", "history", "request_revisions", "request_tags", "request_examples"}
 	for _, table := range tables {
 		var name string
 		err := db.QueryRow("SELECT name FROM sqlite_master WHERE type='table' AND name=?", table).Scan(&name)
@@ -115,6 +115,61 @@ func TestWithTx(t *testing.T) {
 	}
 }
 
+func TestMigrateColumns(t *testing.T) {
+	tempDir := t.TempDir()
+	dbPath := filepath.Join(tempDir, "legacy.db")
+
+	// Create a database with the original requests schema
+	legacy, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		t.Fatalf("Failed to open legacy database: %v", err)
+	}
+	if _, err := legacy.Exec(createRequestsTableQuery); err != nil {
+		t.Fatalf("Failed to create legacy table: %v", err)
+	}
+	if _, err := legacy.Exec("INSERT INTO requests (name, method, url, headers, body) VALUES ('Old', 'GET', 'https://example.com', '{}', '')"); err != nil {
+		t.Fatalf("Failed to insert legacy request: %v", err)
+	}
+	legacy.Close()
+
+	oldGetDBPath := getDBPath
+	getDBPath = func() (string, error) {
+		return dbPath, nil
+	}
+	t.Cleanup(func() {
+		getDBPath = oldGetDBPath
+	})
+
+	db, err := InitDB()
+	if err != nil {
+		t.Fatalf("InitDB() on legacy database error = %v", err)
+	}
+	defer db.Close()
+
+	for _, m := range columnMigrations {
+		exists, err := db.columnExists(m.table, m.column)
+		if err != nil {
+			t.Fatalf("columnExists() error = %v", err)
+		}
+		if !exists {
+			t.Errorf("Expected column %s.%s to be added", m.table, m.column)
+		}
+	}
+
+	var request models.Request
+	if err := db.GetRequest(1, &request); err != nil {
+		t.Fatalf("GetRequest() error = %v", err)
+	}
+	if request.Type != models.RequestTypeHTTP {
+		t.Errorf("Expected legacy request type http, got %s", request.Type)
+	}
+
+	// Running the migrations again is a no-op
+	if err := db.migrateColumns(); err != nil {
+		t.Errorf("migrateColumns() second run error = %v", err)
+	}
+}
+
 // Folder tests
 
 func TestCreateFolder(t *testing.T) {
@@ -167,6 +222,77 @@ func TestCreateFolder(t *testing.T) {
 	}
 }
 
+func TestFolderSettings(t *testing.T) {
+	db := setupTestDB(t)
+
+	folder := &models.Folder{
+		Name:          "Service",
+		Headers:       map[string]string{"Accept": "application/json"},
+		Auth:          &models.Auth{Type: models.AuthTypeBearer, Token: "{{token}}"},
+		BaseURL:       "https://api.example.com",
+		Variables:     map[string]string{"token": "abc"},
+		HostOverrides: map[string]string{"api.example.com": "127.0.0.1"},
+	}
+	if err := db.CreateFolder(folder); err != nil {
+		t.Fatalf("CreateFolder() error = %v", err)
+	}
+	if folder.Headers["Accept"] != "application/json" || folder.BaseURL != "https://api.example.com" || folder.Variables["token"] != "abc" || folder.HostOverrides["api.example.com"] != "127.0.0.1" {
+		t.Errorf("Expected folder settings to round trip, got %+v", folder)
+	}
+	if folder.Auth == nil || folder.Auth.Token != "{{token}}" {
+		t.Errorf("Expected folder auth to round trip, got %+v", folder.Auth)
+	}
+
+	folder.Auth = nil
+	folder.BaseURL = ""
+	if err := db.UpdateFolder(folder); err != nil {
+		t.Fatalf("UpdateFolder() error = %v", err)
+	}
+	var updated models.Folder
+	if err := db.GetFolder(folder.ID, &updated); err != nil {
+		t.Fatalf("GetFolder() error = %v", err)
+	}
+	if updated.Auth != nil || updated.BaseURL != "" {
+		t.Errorf("Expected auth and base URL to be cleared, got %+v", updated)
+	}
+}
+
+func TestGetFolderChain(t *testing.T) {
+	db := setupTestDB(t)
+
+	root := &models.Folder{Name: "Root"}
+	if err := db.CreateFolder(root); err != nil {
+		t.Fatalf("CreateFolder() error = %v", err)
+	}
+	child := &models.Folder{Name: "Child", ParentID: &root.ID}
+	if err := db.CreateFolder(child); err != nil {
+		t.Fatalf("CreateFolder() error = %v", err)
+	}
+	leaf := &models.Folder{Name: "Leaf", ParentID: &child.ID}
+	if err := db.CreateFolder(leaf); err != nil {
+		t.Fatalf("CreateFolder() error = %v", err)
+	}
+
+	chain, err := db.GetFolderChain(leaf.ID)
+	if err != nil {
+		t.Fatalf("GetFolderChain() error = %v", err)
+	}
+	if len(chain) != 3 || chain[0].Name != "Root" || chain[2].Name != "Leaf" {
+		t.Errorf("Expected chain Root > Child > Leaf, got %+v", chain)
+	}
+
+	if _, err := db.GetFolderChain(999); err == nil {
+		t.Error("Expected error for missing folder")
+	}
+
+	if _, err := db.Exec("UPDATE folders SET parent_id = ? WHERE id = ?", leaf.ID, root.ID); err != nil {
+		t.Fatalf("Failed to create cycle: %v", err)
+	}
+	if _, err := db.GetFolderChain(leaf.ID); err == nil {
+		t.Error("Expected error for folder cycle")
+	}
+}
+
 func TestGetFolder(t *testing.T) {
 	db := setupTestDB(t)
 
@@ -357,6 +483,18 @@ func TestCreateRequest(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "Create compressed request",
+			request: &models.Request{
+				Name:        "Compressed Request",
+				Method:      "POST",
+				URL:         "https://example.com/ingest",
+				Body:        `{"events": []}`,
+				Compression: models.CompressionGzip,
+				Protocol:    models.ProtocolHTTP1,
+			},
+			wantErr: false,
+		},
 		{
 			name: "Create request without folder",
 			request: &models.Request{
@@ -389,6 +527,17 @@ func TestCreateRequest(t *testing.T) {
 				if tt.request.UpdatedAt.IsZero() {
 					t.Error("Expected UpdatedAt to be set")
 				}
+
+				var stored models.Request
+				if err := db.GetRequest(tt.request.ID, &stored); err != nil {
+					t.Fatalf("GetRequest() error = %v", err)
+				}
+				if stored.Compression != tt.request.Compression {
+					t.Errorf("Expected compression %q, got %q", tt.request.Compression, stored.Compression)
+				}
+				if stored.Protocol != tt.request.Protocol {
+					t.Errorf("Expected protocol %q, got %q", tt.request.Protocol, stored.Protocol)
+				}
 			}
 		})
 	}
@@ -0,0 +1,185 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/hc/hc/internal/models"
+)
+
+const (
+	createRequestTagsTableQuery = `
+		CREATE TABLE IF NOT EXISTS request_tags (
+			request_id INTEGER NOT NULL,
+			tag TEXT NOT NULL,
+			PRIMARY KEY (request_id, tag),
+			FOREIGN KEY (request_id) REFERENCES requests(id) ON DELETE CASCADE
+		)`
+	selectRequestTagsQuery      = `SELECT tag FROM request_tags WHERE request_id = ? ORDER BY tag`
+	selectRequestsTagsQuery     = `SELECT request_id, tag FROM request_tags WHERE request_id IN (%s) ORDER BY tag`
+	deleteRequestTagsQuery      = `DELETE FROM request_tags WHERE request_id = ?`
+	insertRequestTagQuery       = `INSERT OR IGNORE INTO request_tags (request_id, tag) VALUES (?, ?)`
+	purgeRequestTagsQuery       = `DELETE FROM request_tags WHERE request_id NOT IN (SELECT id FROM requests)`
+	selectTagCountsQuery        = `SELECT tag, COUNT(*) FROM request_tags JOIN requests ON requests.id = request_tags.request_id WHERE requests.deleted_at IS NULL GROUP BY tag ORDER BY tag`
+	selectFilteredRequestsQuery = `SELECT ` + requestColumns + ` FROM requests WHERE deleted_at IS NULL`
+	filterRequestsByTagsQuery   = ` AND id IN (SELECT request_id FROM request_tags WHERE tag IN (%s) GROUP BY request_id HAVING COUNT(*) = ?)`
+	filterRequestsFavoriteQuery = ` AND favorite = 1`
+	requestBatchSize            = 500
+	filterRequestsByFolderQuery = ` AND folder_id ` + searchFolderTree
+	orderFilteredRequestsQuery  = ` ORDER BY position, name`
+)
+
+func NormalizeTags(tags []string) []string {
+	normalized := []string{}
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag != "" && !slices.Contains(normalized, tag) {
+			normalized = append(normalized, tag)
+		}
+	}
+	slices.Sort(normalized)
+	return normalized
+}
+
+func (db *DB) GetTags() ([]models.TagCount, error) {
+	rows, err := db.Query(selectTagCountsQuery)
+	if err != nil {
+		db.log.Error("Failed to get tags", slog.String("error", err.Error()))
+		return nil, err
+	}
+	defer rows.Close()
+	tags := []models.TagCount{}
+	for rows.Next() {
+		var tag models.TagCount
+		if err := rows.Scan(&tag.Tag, &tag.Count); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, rows.Err()
+}
+
+func (db *DB) FilterRequests(filter *models.RequestFilter) ([]models.Request, error) {
+	query := selectFilteredRequestsQuery
+	var args []any
+	if tags := NormalizeTags(filter.Tags); len(tags) > 0 {
+		query += strings.Replace(filterRequestsByTagsQuery, "%s", placeholders(len(tags)), 1)
+		for _, tag := range tags {
+			args = append(args, tag)
+		}
+		args = append(args, len(tags))
+	}
+	if filter.Favorite {
+		query += filterRequestsFavoriteQuery
+	}
+	if filter.FolderID != nil {
+		query += filterRequestsByFolderQuery
+		args = append(args, *filter.FolderID)
+	}
+	requests, err := queryRequests(db, query+orderFilteredRequestsQuery, args...)
+	if err != nil {
+		db.log.Error("Failed to filter requests", slog.String("error", err.Error()))
+		return nil, err
+	}
+	if err := attachTags(db, requests); err != nil {
+		return nil, err
+	}
+	if err := attachExamples(db, requests); err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
+
+func (db *DB) SetRequestFavorite(id int, favorite bool) error {
+	db.log.Info("Updating favorite", slog.Int("id", id), slog.Bool("favorite", favorite))
+	return db.WithTx(context.Background(), func(tx *sql.Tx) error {
+		var request models.Request
+		if err := getRequest(tx, id, &request); err != nil {
+			return err
+		}
+		request.Favorite = favorite
+		return db.updateRequest(tx, &request)
+	})
+}
+
+func requestTags(q querier, id int) ([]string, error) {
+	rows, err := q.Query(selectRequestTagsQuery, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	tags := []string{}
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, rows.Err()
+}
+
+func attachTags(q querier, requests []models.Request) error {
+	tags := make(map[int][]string)
+	for _, ids := range requestIDBatches(requests) {
+		if err := queryRequestsTags(q, ids, tags); err != nil {
+			return err
+		}
+	}
+	for i := range requests {
+		requests[i].Tags = tags[requests[i].ID]
+		if requests[i].Tags == nil {
+			requests[i].Tags = []string{}
+		}
+	}
+	return nil
+}
+
+func queryRequestsTags(q querier, ids []any, tags map[int][]string) error {
+	rows, err := q.Query(strings.Replace(selectRequestsTagsQuery, "%s", placeholders(len(ids)), 1), ids...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id int
+		var tag string
+		if err := rows.Scan(&id, &tag); err != nil {
+			return err
+		}
+		tags[id] = append(tags[id], tag)
+	}
+	return rows.Err()
+}
+
+func requestIDBatches(requests []models.Request) [][]any {
+	var batches [][]any
+	for start := 0; start < len(requests); start += requestBatchSize {
+		end := min(start+requestBatchSize, len(requests))
+		ids := make([]any, 0, end-start)
+		for _, request := range requests[start:end] {
+			ids = append(ids, request.ID)
+		}
+		batches = append(batches, ids)
+	}
+	return batches
+}
+
+func placeholders(n int) string {
+	return strings.Repeat("?, ", n-1) + "?"
+}
+
+func setRequestTags(tx *sql.Tx, id int, tags []string) error {
+	if _, err := tx.Exec(deleteRequestTagsQuery, id); err != nil {
+		return err
+	}
+	for _, tag := range NormalizeTags(tags) {
+		if _, err := tx.Exec(insertRequestTagQuery, id, tag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+package storage
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/hc/hc/internal/models"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	tests := []struct {
+		name string
+		tags []string
+		want []string
+	}{
+		{name: "Nil", tags: nil, want: []string{}},
+		{name: "Lowercases, trims and sorts", tags: []string{" Smoke", "flaky", "smoke", ""}, want: []string{"flaky", "smoke"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeTags(tt.tags); !slices.Equal(got, tt.want) || got == nil {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRequestTags(t *testing.T) {
+	db := setupTestDB(t)
+
+	request := &models.Request{Name: "Tagged", Method: "GET", URL: "https://example.com", Tags: []string{"Smoke", "prod-only"}}
+	if err := db.CreateRequest(request); err != nil {
+		t.Fatalf("CreateRequest() error = %v", err)
+	}
+	if !slices.Equal(request.Tags, []string{"prod-only", "smoke"}) {
+		t.Errorf("Expected normalized tags, got %v", request.Tags)
+	}
+
+	request.Tags = []string{"flaky"}
+	if err := db.UpdateRequest(request); err != nil {
+		t.Fatalf("UpdateRequest() error = %v", err)
+	}
+	var updated models.Request
+	if err := db.GetRequest(request.ID, &updated); err != nil {
+		t.Fatalf("GetRequest() error = %v", err)
+	}
+	if !slices.Equal(updated.Tags, []string{"flaky"}) {
+		t.Errorf("Expected tags to be replaced, got %v", updated.Tags)
+	}
+	revisions, err := db.GetRequestRevisions(request.ID)
+	if err != nil || len(revisions) != 1 || !slices.Equal(revisions[0].Request.Tags, []string{"prod-only", "smoke"}) {
+		t.Errorf("Expected a revision with the previous tags, got %+v, %v", revisions, err)
+	}
+
+	clone, err := db.CloneRequest(context.Background(), request.ID)
+	if err != nil {
+		t.Fatalf("CloneRequest() error = %v", err)
+	}
+	if !slices.Equal(clone.Tags, []string{"flaky"}) {
+		t.Errorf("Expected clone to copy tags, got %v", clone.Tags)
+	}
+
+	tags, err := db.GetTags()
+	if err != nil {
+		t.Fatalf("GetTags() error = %v", err)
+	}
+	if len(tags) != 1 || tags[0] != (models.TagCount{Tag: "flaky", Count: 2}) {
+		t.Errorf("Expected flaky to be used twice, got %+v", tags)
+	}
+}
+
+func TestFilterRequests(t *testing.T) {
+	db := setupTestDB(t)
+
+	parent := createTestFolder(t, db, "Parent", nil)
+	child := createTestFolder(t, db, "Child", &parent.ID)
+	smoke := &models.Request{Name: "Smoke", Method: "GET", URL: "https://example.com", FolderID: &child.ID, Tags: []string{"smoke"}}
+	both := &models.Request{Name: "Both", Method: "GET", URL: "https://example.com", Tags: []string{"smoke", "flaky"}, Favorite: true}
+	deleted := &models.Request{Name: "Deleted", Method: "GET", URL: "https://example.com", Tags: []string{"smoke"}}
+	for _, request := range []*models.Request{smoke, both, deleted} {
+		if err := db.CreateRequest(request); err != nil {
+			t.Fatalf("CreateRequest() error = %v", err)
+		}
+	}
+	if err := db.DeleteRequest(deleted.ID); err != nil {
+		t.Fatalf("DeleteRequest() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		filter models.RequestFilter
+		want   []string
+	}{
+		{name: "Single tag", filter: models.RequestFilter{Tags: []string{"smoke"}}, want: []string{"Both", "Smoke"}},
+		{name: "All tags", filter: models.RequestFilter{Tags: []string{"smoke", "flaky"}}, want: []string{"Both"}},
+		{name: "Favorites", filter: models.RequestFilter{Favorite: true}, want: []string{"Both"}},
+		{name: "Folder subtree", filter: models.RequestFilter{FolderID: &parent.ID}, want: []string{"Smoke"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests, err := db.FilterRequests(&tt.filter)
+			if err != nil {
+				t.Fatalf("FilterRequests() error = %v", err)
+			}
+			var names []string
+			for _, request := range requests {
+				names = append(names, request.Name)
+			}
+			slices.Sort(names)
+			if !slices.Equal(names, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, names)
+			}
+		})
+	}
+
+	if err := db.SetRequestFavorite(smoke.ID, true); err != nil {
+		t.Fatalf("SetRequestFavorite() error = %v", err)
+	}
+	var favorite models.Request
+	if err := db.GetRequest(smoke.ID, &favorite); err != nil || !favorite.Favorite {
+		t.Errorf("Expected request to be a favorite, got %+v, %v", favorite, err)
+	}
+	revisions, err := db.GetRequestRevisions(smoke.ID)
+	if err != nil {
+		t.Fatalf("GetRequestRevisions() error = %v", err)
+	}
+	if len(revisions) != 1 || revisions[0].Request.Favorite {
+		t.Errorf("Expected a revision of the non-favorite request, got %+v", revisions)
+	}
+	if err := db.SetRequestFavorite(smoke.ID, true); err != nil {
+		t.Fatalf("SetRequestFavorite() error = %v", err)
+	}
+	if revisions, _ := db.GetRequestRevisions(smoke.ID); len(revisions) != 1 {
+		t.Errorf("Expected no revision for an unchanged favorite, got %d", len(revisions))
+	}
+	if err := db.SetRequestFavorite(999, true); err == nil {
+		t.Error("Expected error for missing request")
+	}
+}
+
+func TestAttachTagsForSelectedRequests(t *testing.T) {
+	db := setupTestDB(t)
+
+	tagged := &models.Request{Name: "Tagged", Method: "GET", URL: "https://example.com", Tags: []string{"smoke"}}
+	other := &models.Request{Name: "Other", Method: "GET", URL: "https://example.com", Tags: []string{"slow"}}
+	for _, request := range []*models.Request{tagged, other} {
+		if err := db.CreateRequest(request); err != nil {
+			t.Fatalf("CreateRequest() error = %v", err)
+		}
+	}
+
+	requests := []models.Request{{ID: tagged.ID}}
+	if err := attachTags(db, requests); err != nil {
+		t.Fatalf("attachTags() error = %v", err)
+	}
+	if !slices.Equal(requests[0].Tags, []string{"smoke"}) {
+		t.Errorf("Expected tags [smoke], got %v", requests[0].Tags)
+	}
+	if err := attachExamples(db, requests); err != nil {
+		t.Fatalf("attachExamples() error = %v", err)
+	}
+	if requests[0].Examples == nil {
+		t.Error("Expected empty examples, got nil")
+	}
+}
+
+func TestRequestIDBatches(t *testing.T) {
+	tests := []struct {
+		name  string
+		count int
+		want  []int
+	}{
+		{name: "No requests", count: 0, want: nil},
+		{name: "Single batch", count: 3, want: []int{3}},
+		{name: "Split batches", count: requestBatchSize*2 + 1, want: []int{requestBatchSize, requestBatchSize, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []int
+			for _, batch := range requestIDBatches(make([]models.Request, tt.count)) {
+				got = append(got, len(batch))
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Expected batch sizes %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/hc/hc/internal/models"
+)
+
+const (
+	folderSubtreeQuery = `
+		WITH RECURSIVE subtree(id) AS (
+			SELECT id FROM folders WHERE id = ?
+			UNION
+			SELECT folders.id FROM folders JOIN subtree ON folders.parent_id = subtree.id WHERE folders.deleted_at IS ?
+		)`
+	trashFolderQuery           = `UPDATE folders SET deleted_at = ? WHERE id = ? AND deleted_at IS NULL`
+	trashSubfoldersQuery       = folderSubtreeQuery + ` UPDATE folders SET deleted_at = ? WHERE id IN subtree AND deleted_at IS NULL`
+	trashFolderRequestsQuery   = folderSubtreeQuery + ` UPDATE requests SET deleted_at = ? WHERE folder_id IN subtree AND deleted_at IS NULL`
+	trashRequestQuery          = `UPDATE requests SET deleted_at = ? WHERE id = ? AND deleted_at IS NULL`
+	selectTrashedFolderQuery   = `SELECT parent_id, deleted_at FROM folders WHERE id = ? AND deleted_at IS NOT NULL`
+	selectTrashedRequestQuery  = `SELECT folder_id FROM requests WHERE id = ? AND deleted_at IS NOT NULL`
+	liveFolderQuery            = `SELECT COUNT(*) FROM folders WHERE id = ? AND deleted_at IS NULL`
+	restoreFolderQuery         = `UPDATE folders SET parent_id = ?, position = (SELECT COALESCE(MAX(position), -1) + 1 FROM folders WHERE parent_id IS ? AND deleted_at IS NULL), updated_at = CURRENT_TIMESTAMP WHERE id = ?`
+	restoreSubfoldersQuery     = folderSubtreeQuery + ` UPDATE folders SET deleted_at = NULL WHERE id IN subtree AND deleted_at IS ?`
+	restoreFolderRequestsQuery = folderSubtreeQuery + ` UPDATE requests SET deleted_at = NULL WHERE folder_id IN subtree AND deleted_at IS ?`
+	restoreRequestQuery        = `UPDATE requests SET folder_id = ?, position = (SELECT COALESCE(MAX(position), -1) + 1 FROM requests WHERE folder_id IS ? AND deleted_at IS NULL), deleted_at = NULL, updated_at = CURRENT_TIMESTAMP WHERE id = ?`
+	selectTrashFoldersQuery    = `SELECT ` + folderColumns + ` FROM folders AS f WHERE deleted_at IS NOT NULL AND NOT EXISTS (SELECT 1 FROM folders AS p WHERE p.id = f.parent_id AND p.deleted_at IS f.deleted_at) ORDER BY deleted_at DESC, name`
+	selectTrashRequestsQuery   = `SELECT ` + requestColumns + ` FROM requests AS r WHERE deleted_at IS NOT NULL AND NOT EXISTS (SELECT 1 FROM folders AS p WHERE p.id = r.folder_id AND p.deleted_at IS r.deleted_at) ORDER BY deleted_at DESC, name`
+	purgeRequestsQuery         = `DELETE FROM requests WHERE deleted_at IS NOT NULL AND deleted_at < ?`
+	purgeFoldersQuery          = `DELETE FROM folders WHERE deleted_at IS NOT NULL AND deleted_at < ?`
+	emptyRequestsQuery         = `DELETE FROM requests WHERE deleted_at IS NOT NULL`
+	emptyFoldersQuery          = `DELETE FROM folders WHERE deleted_at IS NOT NULL`
+)
+
+func (db *DB) DeleteFolder(id int) error {
+	db.log.Info("Moving folder to trash", slog.Int("id", id))
+	deletedAt := time.Now().UTC()
+	return db.WithTx(context.Background(), func(tx *sql.Tx) error {
+		result, err := tx.Exec(trashFolderQuery, deletedAt, id)
+		if err != nil {
+			db.log.Error("Failed to delete folder", slog.String("error", err.Error()))
+			return err
+		}
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return fmt.Errorf("folder not found")
+		}
+		if _, err := tx.Exec(trashSubfoldersQuery, id, nil, deletedAt); err != nil {
+			return err
+		}
+		_, err = tx.Exec(trashFolderRequestsQuery, id, deletedAt, deletedAt)
+		return err
+	})
+}
+
+func (db *DB) DeleteRequest(id int) error {
+	db.log.Info("Moving request to trash", slog.Int("id", id))
+	result, err := db.Exec(trashRequestQuery, time.Now().UTC(), id)
+	if err != nil {
+		db.log.Error("Failed to delete request", slog.String("error", err.Error()))
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("request not found")
+	}
+	return nil
+}
+
+func (db *DB) GetTrash() (*models.Trash, error) {
+	trash := &models.Trash{Folders: []models.Folder{}, Requests: []models.Request{}}
+	folders, err := queryFolders(db, selectTrashFoldersQuery)
+	if err != nil {
+		db.log.Error("Failed to get trash", slog.String("error", err.Error()))
+		return nil, err
+	}
+	requests, err := queryRequests(db, selectTrashRequestsQuery)
+	if err != nil {
+		db.log.Error("Failed to get trash", slog.String("error", err.Error()))
+		return nil, err
+	}
+	if err := attachTags(db, requests); err != nil {
+		return nil, err
+	}
+	if err := attachExamples(db, requests); err != nil {
+		return nil, err
+	}
+	trash.Folders = append(trash.Folders, folders...)
+	trash.Requests = append(trash.Requests, requests...)
+	return trash, nil
+}
+
+func (db *DB) RestoreFolder(ctx context.Context, id int) error {
+	db.log.Info("Restoring folder", slog.Int("id", id))
+	return db.WithTx(ctx, func(tx *sql.Tx) error {
+		var parentID *int
+		var deletedAt time.Time
+		if err := tx.QueryRow(selectTrashedFolderQuery, id).Scan(&parentID, &deletedAt); err != nil {
+			if err == sql.ErrNoRows {
+				return fmt.Errorf("folder not found")
+			}
+			return err
+		}
+		parentID, err := liveParent(tx, parentID)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(restoreFolderQuery, parentID, parentID, id); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(restoreFolderRequestsQuery, id, deletedAt, deletedAt); err != nil {
+			return err
+		}
+		_, err = tx.Exec(restoreSubfoldersQuery, id, deletedAt, deletedAt)
+		return err
+	})
+}
+
+func (db *DB) RestoreRequest(ctx context.Context, id int) error {
+	db.log.Info("Restoring request", slog.Int("id", id))
+	return db.WithTx(ctx, func(tx *sql.Tx) error {
+		var folderID *int
+		if err := tx.QueryRow(selectTrashedRequestQuery, id).Scan(&folderID); err != nil {
+			if err == sql.ErrNoRows {
+				return fmt.Errorf("request not found")
+			}
+			return err
+		}
+		folderID, err := liveParent(tx, folderID)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(restoreRequestQuery, folderID, folderID, id)
+		return err
+	})
+}
+
+func (db *DB) PurgeTrash(before time.Time) (int64, error) {
+	return db.deleteTrashed(purgeRequestsQuery, purgeFoldersQuery, before.UTC())
+}
+
+func (db *DB) EmptyTrash() (int64, error) {
+	return db.deleteTrashed(emptyRequestsQuery, emptyFoldersQuery)
+}
+
+func (db *DB) deleteTrashed(requestsQuery, foldersQuery string, args ...any) (int64, error) {
+	var total int64
+	err := db.WithTx(context.Background(), func(tx *sql.Tx) error {
+		for _, query := range []string{requestsQuery, foldersQuery} {
+			result, err := tx.Exec(query, args...)
+			if err != nil {
+				return err
+			}
+			rows, err := result.RowsAffected()
+			if err != nil {
+				return err
+			}
+			total += rows
+		}
+		for _, query := range []string{purgeRequestRevisionsQuery, purgeRequestTagsQuery, purgeRequestExamplesQuery} {
+			if _, err := tx.Exec(query); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.log.Error("Failed to purge trash", slog.String("error", err.Error()))
+		return 0, err
+	}
+	if total > 0 {
+		db.log.Info("Purged trash", slog.Int64("items", total))
+	}
+	return total, nil
+}
+
+func liveParent(tx *sql.Tx, parentID *int) (*int, error) {
+	if parentID == nil {
+		return nil, nil
+	}
+	var count int
+	if err := tx.QueryRow(liveFolderQuery, *parentID).Scan(&count); err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, nil
+	}
+	return parentID, nil
+}
@@ -0,0 +1,164 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hc/hc/internal/models"
+)
+
+func TestDeleteFolderMovesSubtreeToTrash(t *testing.T) {
+	db := setupTestDB(t)
+
+	root := createTestFolder(t, db, "Root", nil)
+	child := createTestFolder(t, db, "Child", &root.ID)
+	rootRequest := createTestRequest(t, db, "Root request", &root.ID)
+	childRequest := createTestRequest(t, db, "Child request", &child.ID)
+	loose := createTestRequest(t, db, "Loose", nil)
+
+	if err := db.DeleteFolder(root.ID); err != nil {
+		t.Fatalf("DeleteFolder() error = %v", err)
+	}
+	if err := db.DeleteFolder(root.ID); err == nil {
+		t.Error("Expected error when deleting a folder that is already in the trash")
+	}
+
+	var folder models.Folder
+	if err := db.GetFolder(child.ID, &folder); err == nil {
+		t.Error("Expected subfolder to be hidden after delete")
+	}
+	var request models.Request
+	for _, id := range []int{rootRequest.ID, childRequest.ID} {
+		if err := db.GetRequest(id, &request); err == nil {
+			t.Errorf("Expected request %d to be hidden after delete", id)
+		}
+	}
+	if err := db.GetRequest(loose.ID, &request); err != nil {
+		t.Errorf("Expected unrelated request to remain, got %v", err)
+	}
+
+	trash, err := db.GetTrash()
+	if err != nil {
+		t.Fatalf("GetTrash() error = %v", err)
+	}
+	if len(trash.Folders) != 1 || trash.Folders[0].ID != root.ID || trash.Folders[0].DeletedAt == nil {
+		t.Errorf("Expected only the deleted root folder in trash, got %+v", trash.Folders)
+	}
+	if len(trash.Requests) != 0 {
+		t.Errorf("Expected requests inside the folder to be listed with it, got %+v", trash.Requests)
+	}
+}
+
+func TestRestoreFolder(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+
+	root := createTestFolder(t, db, "Root", nil)
+	child := createTestFolder(t, db, "Child", &root.ID)
+	earlier := createTestRequest(t, db, "Earlier", &child.ID)
+	later := createTestRequest(t, db, "Later", &child.ID)
+
+	if err := db.DeleteRequest(earlier.ID); err != nil {
+		t.Fatalf("DeleteRequest() error = %v", err)
+	}
+	time.Sleep(time.Millisecond)
+	if err := db.DeleteFolder(root.ID); err != nil {
+		t.Fatalf("DeleteFolder() error = %v", err)
+	}
+	if err := db.RestoreFolder(ctx, root.ID); err != nil {
+		t.Fatalf("RestoreFolder() error = %v", err)
+	}
+
+	var folder models.Folder
+	if err := db.GetFolder(child.ID, &folder); err != nil || folder.ParentID == nil || *folder.ParentID != root.ID {
+		t.Errorf("Expected subfolder to be restored under root, got %+v, %v", folder, err)
+	}
+	var request models.Request
+	if err := db.GetRequest(later.ID, &request); err != nil {
+		t.Errorf("Expected request deleted with the folder to be restored, got %v", err)
+	}
+	if err := db.GetRequest(earlier.ID, &request); err == nil {
+		t.Error("Expected request deleted earlier to stay in trash")
+	}
+	if err := db.RestoreFolder(ctx, root.ID); err == nil {
+		t.Error("Expected error when restoring a folder that is not in the trash")
+	}
+}
+
+func TestRestoreRequest(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+
+	folder := createTestFolder(t, db, "Folder", nil)
+	kept := createTestRequest(t, db, "Kept", &folder.ID)
+	orphaned := createTestRequest(t, db, "Orphaned", &folder.ID)
+	createTestRequest(t, db, "Sibling", nil)
+
+	if err := db.DeleteRequest(kept.ID); err != nil {
+		t.Fatalf("DeleteRequest() error = %v", err)
+	}
+	if err := db.RestoreRequest(ctx, kept.ID); err != nil {
+		t.Fatalf("RestoreRequest() error = %v", err)
+	}
+	var request models.Request
+	if err := db.GetRequest(kept.ID, &request); err != nil || request.FolderID == nil || *request.FolderID != folder.ID {
+		t.Errorf("Expected request to be restored into its folder, got %+v, %v", request, err)
+	}
+
+	if err := db.DeleteRequest(orphaned.ID); err != nil {
+		t.Fatalf("DeleteRequest() error = %v", err)
+	}
+	if err := db.DeleteFolder(folder.ID); err != nil {
+		t.Fatalf("DeleteFolder() error = %v", err)
+	}
+	if err := db.RestoreRequest(ctx, orphaned.ID); err != nil {
+		t.Fatalf("RestoreRequest() error = %v", err)
+	}
+	if err := db.GetRequest(orphaned.ID, &request); err != nil || request.FolderID != nil {
+		t.Errorf("Expected request to be restored to the root when its folder is deleted, got %+v, %v", request, err)
+	}
+	if request.Position != 1 {
+		t.Errorf("Expected restored request to be appended at position 1, got %d", request.Position)
+	}
+
+	if err := db.RestoreRequest(ctx, 999); err == nil {
+		t.Error("Expected error for missing request")
+	}
+}
+
+func TestPurgeTrash(t *testing.T) {
+	db := setupTestDB(t)
+
+	folder := createTestFolder(t, db, "Folder", nil)
+	createTestRequest(t, db, "Inside", &folder.ID)
+	request := createTestRequest(t, db, "Request", nil)
+	if err := db.DeleteFolder(folder.ID); err != nil {
+		t.Fatalf("DeleteFolder() error = %v", err)
+	}
+	if err := db.DeleteRequest(request.ID); err != nil {
+		t.Fatalf("DeleteRequest() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		purge     func() (int64, error)
+		wantCount int64
+	}{
+		{name: "Items newer than cutoff are kept", purge: func() (int64, error) { return db.PurgeTrash(time.Now().Add(-time.Hour)) }, wantCount: 0},
+		{name: "Items older than cutoff are purged", purge: func() (int64, error) { return db.PurgeTrash(time.Now().Add(time.Hour)) }, wantCount: 3},
+		{name: "Empty trash", purge: db.EmptyTrash, wantCount: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			count, err := tt.purge()
+			if err != nil {
+				t.Fatalf("purge error = %v", err)
+			}
+			if count != tt.wantCount {
+				t.Errorf("Expected %d purged items, got %d", tt.wantCount, count)
+			}
+		})
+	}
+}
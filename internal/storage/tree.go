@@ -0,0 +1,224 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+
+	"github.com/hc/hc/internal/models"
+)
+
+type orderedTable struct {
+	parentQuery   string
+	siblingsQuery string
+	moveQuery     string
+	positionQuery string
+}
+
+var (
+	folderOrder = orderedTable{
+		parentQuery:   `SELECT parent_id FROM folders WHERE id = ?`,
+		siblingsQuery: `SELECT id FROM folders WHERE parent_id IS ? AND id != ? AND deleted_at IS NULL ORDER BY position, name`,
+		moveQuery:     `UPDATE folders SET parent_id = ?, position = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?`,
+		positionQuery: `UPDATE folders SET position = ? WHERE id = ?`,
+	}
+	requestOrder = orderedTable{
+		parentQuery:   `SELECT folder_id FROM requests WHERE id = ?`,
+		siblingsQuery: `SELECT id FROM requests WHERE folder_id IS ? AND id != ? AND deleted_at IS NULL ORDER BY position, name`,
+		moveQuery:     `UPDATE requests SET folder_id = ?, position = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?`,
+		positionQuery: `UPDATE requests SET position = ? WHERE id = ?`,
+	}
+)
+
+const selectFolderParentQuery = `SELECT parent_id FROM folders WHERE id = ? AND deleted_at IS NULL`
+
+var (
+	ErrFolderOwnParent = errors.New("folder cannot be its own parent")
+	ErrFolderCycle     = errors.New("folder cannot be moved into its own subfolder")
+	ErrParentNotFound  = errors.New("parent folder not found")
+)
+
+type rowQuerier interface {
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+func (db *DB) CheckFolderParent(id int, parentID *int) error {
+	return checkFolderParent(db, id, parentID)
+}
+
+func checkFolderParent(q rowQuerier, id int, parentID *int) error {
+	if parentID == nil {
+		return nil
+	}
+	if *parentID == id {
+		return ErrFolderOwnParent
+	}
+	seen := make(map[int]bool)
+	for next := parentID; next != nil; {
+		if *next == id {
+			return ErrFolderCycle
+		}
+		if seen[*next] {
+			return fmt.Errorf("folder cycle detected at folder %d", *next)
+		}
+		seen[*next] = true
+		var parent *int
+		err := q.QueryRow(selectFolderParentQuery, *next).Scan(&parent)
+		if err == sql.ErrNoRows {
+			return ErrParentNotFound
+		}
+		if err != nil {
+			return err
+		}
+		next = parent
+	}
+	return nil
+}
+
+func (db *DB) CheckRequestFolder(folderID *int) error {
+	if folderID == nil {
+		return nil
+	}
+	var folder models.Folder
+	if err := db.GetFolder(*folderID, &folder); err != nil {
+		return fmt.Errorf("folder not found")
+	}
+	return nil
+}
+
+func (db *DB) GetTree() (*models.Tree, error) {
+	folders, err := db.GetFolders()
+	if err != nil {
+		return nil, err
+	}
+	requests, err := db.GetRequests()
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(requests, func(i, j int) bool {
+		if requests[i].Position != requests[j].Position {
+			return requests[i].Position < requests[j].Position
+		}
+		return requests[i].Name < requests[j].Name
+	})
+	known := make(map[int]bool, len(folders))
+	for _, folder := range folders {
+		known[folder.ID] = true
+	}
+	childFolders := make(map[int][]models.Folder)
+	childRequests := make(map[int][]models.Request)
+	tree := &models.Tree{}
+	var roots []models.Folder
+	for _, folder := range folders {
+		if folder.ParentID == nil || !known[*folder.ParentID] {
+			roots = append(roots, folder)
+			continue
+		}
+		childFolders[*folder.ParentID] = append(childFolders[*folder.ParentID], folder)
+	}
+	tree.Requests = []models.Request{}
+	for _, request := range requests {
+		if request.FolderID == nil || !known[*request.FolderID] {
+			tree.Requests = append(tree.Requests, request)
+			continue
+		}
+		childRequests[*request.FolderID] = append(childRequests[*request.FolderID], request)
+	}
+	var build func(folders []models.Folder) []models.TreeFolder
+	build = func(folders []models.Folder) []models.TreeFolder {
+		nodes := make([]models.TreeFolder, 0, len(folders))
+		for _, folder := range folders {
+			node := models.TreeFolder{
+				Folder:   folder,
+				Folders:  build(childFolders[folder.ID]),
+				Requests: childRequests[folder.ID],
+			}
+			if node.Requests == nil {
+				node.Requests = []models.Request{}
+			}
+			nodes = append(nodes, node)
+		}
+		return nodes
+	}
+	tree.Folders = build(roots)
+	return tree, nil
+}
+
+func (db *DB) MoveFolder(ctx context.Context, id int, target models.MoveTarget) error {
+	db.log.Info("Moving folder", slog.Int("id", id))
+	return db.WithTx(ctx, func(tx *sql.Tx) error {
+		if err := checkFolderParent(tx, id, target.ParentID); err != nil {
+			return err
+		}
+		return folderOrder.move(tx, id, target)
+	})
+}
+
+func (db *DB) MoveRequest(ctx context.Context, id int, target models.MoveTarget) error {
+	db.log.Info("Moving request", slog.Int("id", id))
+	return db.WithTx(ctx, func(tx *sql.Tx) error {
+		return requestOrder.move(tx, id, target)
+	})
+}
+
+func (t orderedTable) move(tx *sql.Tx, id int, target models.MoveTarget) error {
+	var oldParentID *int
+	if err := tx.QueryRow(t.parentQuery, id).Scan(&oldParentID); err != nil {
+		return err
+	}
+	siblings, err := t.siblingIDs(tx, target.ParentID, id)
+	if err != nil {
+		return err
+	}
+	position := min(max(target.Position, 0), len(siblings))
+	siblings = append(siblings[:position], append([]int{id}, siblings[position:]...)...)
+	for i, siblingID := range siblings {
+		query, args := t.positionQuery, []any{i, siblingID}
+		if siblingID == id {
+			query, args = t.moveQuery, []any{target.ParentID, i, id}
+		}
+		if _, err := tx.Exec(query, args...); err != nil {
+			return err
+		}
+	}
+	if equalParent(oldParentID, target.ParentID) {
+		return nil
+	}
+	oldSiblings, err := t.siblingIDs(tx, oldParentID, id)
+	if err != nil {
+		return err
+	}
+	for i, siblingID := range oldSiblings {
+		if _, err := tx.Exec(t.positionQuery, i, siblingID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t orderedTable) siblingIDs(tx *sql.Tx, parentID *int, id int) ([]int, error) {
+	rows, err := tx.Query(t.siblingsQuery, parentID, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var ids []int
+	for rows.Next() {
+		var siblingID int
+		if err := rows.Scan(&siblingID); err != nil {
+			return nil, err
+		}
+		ids = append(ids, siblingID)
+	}
+	return ids, rows.Err()
+}
+
+func equalParent(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,255 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hc/hc/internal/models"
+)
+
+func createTestFolder(t *testing.T, db *DB, name string, parentID *int) *models.Folder {
+	t.Helper()
+	folder := &models.Folder{Name: name, ParentID: parentID}
+	if err := db.CreateFolder(folder); err != nil {
+		t.Fatalf("CreateFolder() error = %v", err)
+	}
+	return folder
+}
+
+func createTestRequest(t *testing.T, db *DB, name string, folderID *int) *models.Request {
+	t.Helper()
+	request := &models.Request{Name: name, Method: "GET", URL: "https://example.com", FolderID: folderID}
+	if err := db.CreateRequest(request); err != nil {
+		t.Fatalf("CreateRequest() error = %v", err)
+	}
+	return request
+}
+
+func TestCreateAssignsPositions(t *testing.T) {
+	db := setupTestDB(t)
+
+	first := createTestFolder(t, db, "B", nil)
+	second := createTestFolder(t, db, "A", nil)
+	child := createTestFolder(t, db, "Child", &first.ID)
+	if first.Position != 0 || second.Position != 1 || child.Position != 0 {
+		t.Errorf("Expected positions 0, 1 and 0, got %d, %d and %d", first.Position, second.Position, child.Position)
+	}
+
+	r1 := createTestRequest(t, db, "One", &first.ID)
+	r2 := createTestRequest(t, db, "Two", &first.ID)
+	r3 := createTestRequest(t, db, "Root", nil)
+	if r1.Position != 0 || r2.Position != 1 || r3.Position != 0 {
+		t.Errorf("Expected positions 0, 1 and 0, got %d, %d and %d", r1.Position, r2.Position, r3.Position)
+	}
+}
+
+func TestCheckFolderParent(t *testing.T) {
+	db := setupTestDB(t)
+
+	root := createTestFolder(t, db, "Root", nil)
+	child := createTestFolder(t, db, "Child", &root.ID)
+	grandchild := createTestFolder(t, db, "Grandchild", &child.ID)
+
+	tests := []struct {
+		name     string
+		id       int
+		parentID *int
+		wantErr  bool
+	}{
+		{name: "root level", id: child.ID, parentID: nil},
+		{name: "valid parent", id: grandchild.ID, parentID: &root.ID},
+		{name: "new folder", id: 0, parentID: &grandchild.ID},
+		{name: "self", id: root.ID, parentID: &root.ID, wantErr: true},
+		{name: "descendant", id: root.ID, parentID: &grandchild.ID, wantErr: true},
+		{name: "missing parent", id: root.ID, parentID: intPtr(999), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := db.CheckFolderParent(tt.id, tt.parentID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckFolderParent() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGetTree(t *testing.T) {
+	db := setupTestDB(t)
+
+	api := createTestFolder(t, db, "API", nil)
+	users := createTestFolder(t, db, "Users", &api.ID)
+	createTestFolder(t, db, "Admin", nil)
+	createTestRequest(t, db, "List users", &users.ID)
+	createTestRequest(t, db, "Get user", &users.ID)
+	createTestRequest(t, db, "Health", nil)
+
+	tree, err := db.GetTree()
+	if err != nil {
+		t.Fatalf("GetTree() error = %v", err)
+	}
+	if len(tree.Folders) != 2 || tree.Folders[0].Name != "API" || tree.Folders[1].Name != "Admin" {
+		t.Fatalf("Expected root folders API and Admin in position order, got %+v", tree.Folders)
+	}
+	if len(tree.Requests) != 1 || tree.Requests[0].Name != "Health" {
+		t.Errorf("Expected root request Health, got %+v", tree.Requests)
+	}
+	nested := tree.Folders[0].Folders
+	if len(nested) != 1 || nested[0].Name != "Users" {
+		t.Fatalf("Expected nested Users folder, got %+v", nested)
+	}
+	if len(nested[0].Requests) != 2 || nested[0].Requests[0].Name != "List users" || nested[0].Requests[1].Name != "Get user" {
+		t.Errorf("Expected requests in position order, got %+v", nested[0].Requests)
+	}
+	if tree.Folders[1].Folders == nil || tree.Folders[1].Requests == nil {
+		t.Error("Expected empty folders to have empty lists")
+	}
+}
+
+func TestMoveFolder(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+
+	a := createTestFolder(t, db, "A", nil)
+	b := createTestFolder(t, db, "B", nil)
+	c := createTestFolder(t, db, "C", nil)
+
+	if err := db.MoveFolder(ctx, c.ID, models.MoveTarget{Position: 0}); err != nil {
+		t.Fatalf("MoveFolder() error = %v", err)
+	}
+	folders, err := db.GetFolders()
+	if err != nil {
+		t.Fatalf("GetFolders() error = %v", err)
+	}
+	if folders[0].Name != "C" || folders[1].Name != "A" || folders[2].Name != "B" {
+		t.Errorf("Expected order C, A, B, got %s, %s, %s", folders[0].Name, folders[1].Name, folders[2].Name)
+	}
+
+	if err := db.MoveFolder(ctx, a.ID, models.MoveTarget{ParentID: &b.ID, Position: 10}); err != nil {
+		t.Fatalf("MoveFolder() error = %v", err)
+	}
+	var moved models.Folder
+	if err := db.GetFolder(a.ID, &moved); err != nil {
+		t.Fatalf("GetFolder() error = %v", err)
+	}
+	if moved.ParentID == nil || *moved.ParentID != b.ID || moved.Position != 0 {
+		t.Errorf("Expected folder under B at position 0, got parent %v position %d", moved.ParentID, moved.Position)
+	}
+	var remaining models.Folder
+	if err := db.GetFolder(b.ID, &remaining); err != nil {
+		t.Fatalf("GetFolder() error = %v", err)
+	}
+	if remaining.Position != 1 {
+		t.Errorf("Expected B to keep position 1, got %d", remaining.Position)
+	}
+}
+
+func TestMoveFolderIntoDescendant(t *testing.T) {
+	db := setupTestDB(t)
+
+	root := createTestFolder(t, db, "Root", nil)
+	child := createTestFolder(t, db, "Child", &root.ID)
+
+	err := db.MoveFolder(context.Background(), root.ID, models.MoveTarget{ParentID: &child.ID})
+	if !errors.Is(err, ErrFolderCycle) {
+		t.Errorf("Expected ErrFolderCycle, got %v", err)
+	}
+}
+
+func TestUpdateFolderParent(t *testing.T) {
+	db := setupTestDB(t)
+
+	a := createTestFolder(t, db, "A", nil)
+	b := createTestFolder(t, db, "B", nil)
+	c := createTestFolder(t, db, "C", nil)
+	createTestFolder(t, db, "Inside", &c.ID)
+
+	a.ParentID = &c.ID
+	if err := db.UpdateFolder(a); err != nil {
+		t.Fatalf("UpdateFolder() error = %v", err)
+	}
+	var moved models.Folder
+	if err := db.GetFolder(a.ID, &moved); err != nil {
+		t.Fatalf("GetFolder() error = %v", err)
+	}
+	if moved.ParentID == nil || *moved.ParentID != c.ID || moved.Position != 1 {
+		t.Errorf("Expected folder appended under C at position 1, got parent %v position %d", moved.ParentID, moved.Position)
+	}
+	var remaining models.Folder
+	if err := db.GetFolder(b.ID, &remaining); err != nil {
+		t.Fatalf("GetFolder() error = %v", err)
+	}
+	if remaining.Position != 0 {
+		t.Errorf("Expected B to move up to position 0, got %d", remaining.Position)
+	}
+
+	c.ParentID = &a.ID
+	if err := db.UpdateFolder(c); !errors.Is(err, ErrFolderCycle) {
+		t.Errorf("Expected ErrFolderCycle, got %v", err)
+	}
+}
+
+func TestUpdateRequestFolder(t *testing.T) {
+	db := setupTestDB(t)
+
+	folder := createTestFolder(t, db, "Folder", nil)
+	createTestRequest(t, db, "Inside", &folder.ID)
+	first := createTestRequest(t, db, "First", nil)
+	second := createTestRequest(t, db, "Second", nil)
+
+	first.FolderID = &folder.ID
+	if err := db.UpdateRequest(first); err != nil {
+		t.Fatalf("UpdateRequest() error = %v", err)
+	}
+	for _, tt := range []struct {
+		id       int
+		folderID *int
+		position int
+	}{
+		{id: first.ID, folderID: &folder.ID, position: 1},
+		{id: second.ID, folderID: nil, position: 0},
+	} {
+		var request models.Request
+		if err := db.GetRequest(tt.id, &request); err != nil {
+			t.Fatalf("GetRequest() error = %v", err)
+		}
+		if request.Position != tt.position || !equalParent(request.FolderID, tt.folderID) {
+			t.Errorf("Expected request %d at position %d in folder %v, got position %d folder %v", tt.id, tt.position, tt.folderID, request.Position, request.FolderID)
+		}
+	}
+}
+
+func TestMoveRequest(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+
+	folder := createTestFolder(t, db, "Folder", nil)
+	first := createTestRequest(t, db, "First", &folder.ID)
+	second := createTestRequest(t, db, "Second", &folder.ID)
+	loose := createTestRequest(t, db, "Loose", nil)
+
+	if err := db.MoveRequest(ctx, loose.ID, models.MoveTarget{ParentID: &folder.ID, Position: 1}); err != nil {
+		t.Fatalf("MoveRequest() error = %v", err)
+	}
+	for _, tt := range []struct {
+		id       int
+		position int
+	}{
+		{id: first.ID, position: 0},
+		{id: loose.ID, position: 1},
+		{id: second.ID, position: 2},
+	} {
+		var request models.Request
+		if err := db.GetRequest(tt.id, &request); err != nil {
+			t.Fatalf("GetRequest() error = %v", err)
+		}
+		if request.Position != tt.position || request.FolderID == nil || *request.FolderID != folder.ID {
+			t.Errorf("Expected request %d at position %d in folder, got position %d folder %v", tt.id, tt.position, request.Position, request.FolderID)
+		}
+	}
+
+	if err := db.CheckRequestFolder(intPtr(999)); err == nil {
+		t.Error("Expected error for missing folder")
+	}
+}
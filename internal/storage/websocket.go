@@ -0,0 +1,213 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/hc/hc/internal/models"
+)
+
+const (
+	createWebSocketSessionsTableQuery = `
+		CREATE TABLE IF NOT EXISTS websocket_sessions (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			request_id INTEGER,
+			url TEXT NOT NULL,
+			headers TEXT,
+			status TEXT NOT NULL,
+			close_code INTEGER NOT NULL DEFAULT 0,
+			close_reason TEXT NOT NULL DEFAULT '',
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			closed_at DATETIME,
+			FOREIGN KEY (request_id) REFERENCES requests(id) ON DELETE SET NULL
+		)`
+	createWebSocketMessagesTableQuery = `
+		CREATE TABLE IF NOT EXISTS websocket_messages (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			session_id INTEGER NOT NULL,
+			direction TEXT NOT NULL,
+			type TEXT NOT NULL,
+			data TEXT,
+			created_at DATETIME NOT NULL,
+			FOREIGN KEY (session_id) REFERENCES websocket_sessions(id) ON DELETE CASCADE
+		)`
+	webSocketSessionColumns      = `id, request_id, url, headers, status, close_code, close_reason, created_at, closed_at`
+	insertWebSocketSessionQuery  = `INSERT INTO websocket_sessions (request_id, url, headers, status) VALUES (?, ?, ?, ?)`
+	selectWebSocketSessionQuery  = `SELECT ` + webSocketSessionColumns + ` FROM websocket_sessions WHERE id = ?`
+	selectWebSocketSessionsQuery = `SELECT ` + webSocketSessionColumns + ` FROM websocket_sessions ORDER BY created_at DESC, id DESC`
+	updateWebSocketSessionQuery  = `UPDATE websocket_sessions SET status = ?, close_code = ?, close_reason = ?, closed_at = ? WHERE id = ?`
+	insertWebSocketMessageQuery  = `INSERT INTO websocket_messages (session_id, direction, type, data, created_at) VALUES (?, ?, ?, ?, ?)`
+	selectWebSocketMessagesQuery = `SELECT id, session_id, direction, type, data, created_at FROM websocket_messages WHERE session_id = ? ORDER BY id`
+	deleteWebSocketMessagesQuery = `DELETE FROM websocket_messages WHERE session_id = ?`
+	deleteWebSocketSessionQuery  = `DELETE FROM websocket_sessions WHERE id = ?`
+)
+
+func (db *DB) CreateWebSocketSession(session *models.WebSocketSession) error {
+	db.log.Info("Creating websocket session", slog.String("url", session.URL))
+	headersJSON, err := serializeHeaders(session.Headers)
+	if err != nil {
+		return err
+	}
+	if session.Status == "" {
+		session.Status = models.WebSocketStatusOpen
+	}
+	result, err := db.Exec(insertWebSocketSessionQuery, session.RequestID, session.URL, headersJSON, session.Status)
+	if err != nil {
+		db.log.Error("Failed to create websocket session", slog.String("error", err.Error()))
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	session.ID = int(id)
+	return db.GetWebSocketSession(session.ID, session)
+}
+
+func (db *DB) GetWebSocketSession(id int, session *models.WebSocketSession) error {
+	err := scanWebSocketSession(db.QueryRow(selectWebSocketSessionQuery, id), session)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("websocket session not found")
+	}
+	if err != nil {
+		db.log.Error("Failed to get websocket session", slog.Int("id", id), slog.String("error", err.Error()))
+		return err
+	}
+	return nil
+}
+
+func (db *DB) GetWebSocketSessions() ([]models.WebSocketSession, error) {
+	rows, err := db.Query(selectWebSocketSessionsQuery)
+	if err != nil {
+		db.log.Error("Failed to get websocket sessions", slog.String("error", err.Error()))
+		return nil, err
+	}
+	defer rows.Close()
+	var sessions []models.WebSocketSession
+	for rows.Next() {
+		var session models.WebSocketSession
+		if err := scanWebSocketSession(rows, &session); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+func (db *DB) CloseWebSocketSession(id int, status string, code int, reason string) error {
+	db.log.Info("Closing websocket session", slog.Int("id", id), slog.String("status", status))
+	result, err := db.Exec(updateWebSocketSessionQuery, status, code, reason, time.Now().UTC(), id)
+	if err != nil {
+		db.log.Error("Failed to close websocket session", slog.String("error", err.Error()))
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("websocket session not found")
+	}
+	return nil
+}
+
+func (db *DB) DeleteWebSocketSession(id int) error {
+	db.log.Info("Deleting websocket session", slog.Int("id", id))
+	return db.WithTx(context.Background(), func(tx *sql.Tx) error {
+		if _, err := tx.Exec(deleteWebSocketMessagesQuery, id); err != nil {
+			return err
+		}
+		result, err := tx.Exec(deleteWebSocketSessionQuery, id)
+		if err != nil {
+			return err
+		}
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return fmt.Errorf("websocket session not found")
+		}
+		return nil
+	})
+}
+
+func (db *DB) AddWebSocketMessage(message *models.WebSocketMessage) error {
+	if message.CreatedAt.IsZero() {
+		message.CreatedAt = time.Now().UTC()
+	}
+	result, err := db.Exec(insertWebSocketMessageQuery,
+		message.SessionID,
+		message.Direction,
+		message.Type,
+		message.Data,
+		message.CreatedAt,
+	)
+	if err != nil {
+		db.log.Error("Failed to add websocket message", slog.String("error", err.Error()))
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	message.ID = int(id)
+	return nil
+}
+
+func (db *DB) GetWebSocketMessages(sessionID int) ([]models.WebSocketMessage, error) {
+	rows, err := db.Query(selectWebSocketMessagesQuery, sessionID)
+	if err != nil {
+		db.log.Error("Failed to get websocket messages", slog.String("error", err.Error()))
+		return nil, err
+	}
+	defer rows.Close()
+	var messages []models.WebSocketMessage
+	for rows.Next() {
+		var message models.WebSocketMessage
+		if err := rows.Scan(
+			&message.ID,
+			&message.SessionID,
+			&message.Direction,
+			&message.Type,
+			&message.Data,
+			&message.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		messages = append(messages, message)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+func scanWebSocketSession(row rowScanner, session *models.WebSocketSession) error {
+	var headersStr string
+	if err := row.Scan(
+		&session.ID,
+		&session.RequestID,
+		&session.URL,
+		&headersStr,
+		&session.Status,
+		&session.CloseCode,
+		&session.CloseReason,
+		&session.CreatedAt,
+		&session.ClosedAt,
+	); err != nil {
+		return err
+	}
+	headers, err := deserializeHeaders(headersStr)
+	if err != nil {
+		return fmt.Errorf("failed to deserialize headers: %w", err)
+	}
+	session.Headers = headers
+	return nil
+}
@@ -0,0 +1,129 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/hc/hc/internal/models"
+)
+
+func TestWebSocketSessions(t *testing.T) {
+	db := setupTestDB(t)
+
+	session := &models.WebSocketSession{
+		URL:     "wss://echo.example.com/socket",
+		Headers: map[string]string{"Authorization": "Bearer token"},
+	}
+	if err := db.CreateWebSocketSession(session); err != nil {
+		t.Fatalf("CreateWebSocketSession() error = %v", err)
+	}
+
+	if session.ID == 0 {
+		t.Error("Expected session ID to be set")
+	}
+	if session.Status != models.WebSocketStatusOpen {
+		t.Errorf("Expected status open, got %s", session.Status)
+	}
+	if session.Headers["Authorization"] != "Bearer token" {
+		t.Errorf("Expected headers to be stored, got %v", session.Headers)
+	}
+	if session.ClosedAt != nil {
+		t.Error("Expected ClosedAt to be nil for open session")
+	}
+
+	// Record messages in both directions
+	for _, msg := range []*models.WebSocketMessage{
+		{SessionID: session.ID, Direction: models.WebSocketDirectionSent, Type: models.WebSocketMessageText, Data: "ping"},
+		{SessionID: session.ID, Direction: models.WebSocketDirectionReceived, Type: models.WebSocketMessageText, Data: "pong"},
+	} {
+		if err := db.AddWebSocketMessage(msg); err != nil {
+			t.Fatalf("AddWebSocketMessage() error = %v", err)
+		}
+		if msg.ID == 0 || msg.CreatedAt.IsZero() {
+			t.Errorf("Expected message ID and timestamp to be set, got %+v", msg)
+		}
+	}
+
+	messages, err := db.GetWebSocketMessages(session.ID)
+	if err != nil {
+		t.Fatalf("GetWebSocketMessages() error = %v", err)
+	}
+	if len(messages) != 2 {
+		t.Fatalf("Expected 2 messages, got %d", len(messages))
+	}
+	if messages[0].Data != "ping" || messages[1].Data != "pong" {
+		t.Errorf("Expected messages in order, got %+v", messages)
+	}
+
+	// Close the session
+	if err := db.CloseWebSocketSession(session.ID, models.WebSocketStatusClosed, 1000, "bye"); err != nil {
+		t.Fatalf("CloseWebSocketSession() error = %v", err)
+	}
+	var closed models.WebSocketSession
+	if err := db.GetWebSocketSession(session.ID, &closed); err != nil {
+		t.Fatalf("GetWebSocketSession() error = %v", err)
+	}
+	if closed.Status != models.WebSocketStatusClosed || closed.CloseCode != 1000 || closed.CloseReason != "bye" {
+		t.Errorf("Unexpected closed session: %+v", closed)
+	}
+	if closed.ClosedAt == nil {
+		t.Error("Expected ClosedAt to be set")
+	}
+
+	if err := db.CloseWebSocketSession(9999, models.WebSocketStatusClosed, 1000, ""); err == nil {
+		t.Error("Expected error closing non-existent session")
+	}
+
+	sessions, err := db.GetWebSocketSessions()
+	if err != nil {
+		t.Fatalf("GetWebSocketSessions() error = %v", err)
+	}
+	if len(sessions) != 1 {
+		t.Errorf("Expected 1 session, got %d", len(sessions))
+	}
+
+	// Deleting a session removes its message log
+	if err := db.DeleteWebSocketSession(session.ID); err != nil {
+		t.Fatalf("DeleteWebSocketSession() error = %v", err)
+	}
+	messages, err = db.GetWebSocketMessages(session.ID)
+	if err != nil {
+		t.Fatalf("GetWebSocketMessages() error = %v", err)
+	}
+	if len(messages) != 0 {
+		t.Errorf("Expected messages to be deleted, got %d", len(messages))
+	}
+	if err := db.DeleteWebSocketSession(session.ID); err == nil || err.Error() != "websocket session not found" {
+		t.Errorf("Expected 'websocket session not found' error, got %v", err)
+	}
+}
+
+func TestWebSocketRequestType(t *testing.T) {
+	db := setupTestDB(t)
+
+	folder := &models.Folder{Name: "Sockets"}
+	if err := db.CreateFolder(folder); err != nil {
+		t.Fatalf("Failed to create folder: %v", err)
+	}
+
+	request := &models.Request{
+		Name:     "Chat",
+		FolderID: &folder.ID,
+		Type:     models.RequestTypeWebSocket,
+		Method:   "GET",
+		URL:      "wss://chat.example.com/ws",
+	}
+	if err := db.CreateRequest(request); err != nil {
+		t.Fatalf("CreateRequest() error = %v", err)
+	}
+
+	var got models.Request
+	if err := db.GetRequest(request.ID, &got); err != nil {
+		t.Fatalf("GetRequest() error = %v", err)
+	}
+	if got.Type != models.RequestTypeWebSocket {
+		t.Errorf("Expected type websocket, got %s", got.Type)
+	}
+	if got.FolderID == nil || *got.FolderID != folder.ID {
+		t.Errorf("Expected request in folder %d, got %v", folder.ID, got.FolderID)
+	}
+}
@@ -0,0 +1,229 @@
+package wssession
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/hc/hc/internal/logger"
+	"github.com/hc/hc/internal/models"
+)
+
+type Recorder interface {
+	AddWebSocketMessage(message *models.WebSocketMessage) error
+	CloseWebSocketSession(id int, status string, code int, reason string) error
+}
+
+type Session struct {
+	ID       int
+	conn     *websocket.Conn
+	recorder Recorder
+	writeMu  sync.Mutex
+	mu       sync.Mutex
+	nextSub  int
+	subs     map[int]func(models.WebSocketMessage)
+	done     chan struct{}
+}
+
+type Manager struct {
+	mu       sync.Mutex
+	sessions map[int]*Session
+	recorder Recorder
+}
+
+func NewManager(recorder Recorder) *Manager {
+	return &Manager{
+		sessions: make(map[int]*Session),
+		recorder: recorder,
+	}
+}
+
+func ValidateURL(url string) error {
+	if url == "" {
+		return errors.New("URL is required")
+	}
+	if !strings.HasPrefix(url, "ws://") && !strings.HasPrefix(url, "wss://") {
+		return errors.New("URL must start with ws:// or wss://")
+	}
+	return nil
+}
+
+func (m *Manager) Open(ctx context.Context, id int, url string, headers map[string]string, dialer *websocket.Dialer) (*Session, *http.Response, error) {
+	header := make(http.Header)
+	for key, value := range headers {
+		header.Set(key, value)
+	}
+	conn, resp, err := dialer.DialContext(ctx, url, header)
+	if err != nil {
+		return nil, resp, err
+	}
+	session := &Session{
+		ID:       id,
+		conn:     conn,
+		recorder: m.recorder,
+		subs:     make(map[int]func(models.WebSocketMessage)),
+		done:     make(chan struct{}),
+	}
+	m.mu.Lock()
+	m.sessions[id] = session
+	m.mu.Unlock()
+	go func() {
+		session.readLoop()
+		m.mu.Lock()
+		delete(m.sessions, id)
+		m.mu.Unlock()
+	}()
+	return session, resp, nil
+}
+
+func (m *Manager) Get(id int) (*Session, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	session, ok := m.sessions[id]
+	return session, ok
+}
+
+func (m *Manager) Close(id int) error {
+	session, ok := m.Get(id)
+	if !ok {
+		return errors.New("websocket session not open")
+	}
+	return session.Close()
+}
+
+func (s *Session) Done() <-chan struct{} {
+	return s.done
+}
+
+func (s *Session) Subscribe(fn func(models.WebSocketMessage)) func() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := s.nextSub
+	s.nextSub++
+	s.subs[id] = fn
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.subs, id)
+	}
+}
+
+func (s *Session) Send(messageType, data string) (*models.WebSocketMessage, error) {
+	var (
+		frameType int
+		payload   []byte
+	)
+	switch messageType {
+	case "", models.WebSocketMessageText:
+		messageType = models.WebSocketMessageText
+		frameType = websocket.TextMessage
+		payload = []byte(data)
+	case models.WebSocketMessageBinary:
+		decoded, err := base64.StdEncoding.DecodeString(data)
+		if err != nil {
+			return nil, errors.New("binary messages must be base64 encoded")
+		}
+		frameType = websocket.BinaryMessage
+		payload = decoded
+	default:
+		return nil, errors.New("invalid websocket message type: " + messageType)
+	}
+	s.writeMu.Lock()
+	err := s.conn.WriteMessage(frameType, payload)
+	s.writeMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	message := &models.WebSocketMessage{
+		SessionID: s.ID,
+		Direction: models.WebSocketDirectionSent,
+		Type:      messageType,
+		Data:      data,
+	}
+	s.record(message)
+	return message, nil
+}
+
+func (s *Session) Close() error {
+	s.writeMu.Lock()
+	err := s.conn.WriteControl(websocket.CloseMessage,
+		websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+		time.Now().Add(5*time.Second))
+	s.writeMu.Unlock()
+	if err != nil {
+		return s.conn.Close()
+	}
+	select {
+	case <-s.done:
+	case <-time.After(5 * time.Second):
+		return s.conn.Close()
+	}
+	return nil
+}
+
+func (s *Session) readLoop() {
+	defer close(s.done)
+	defer s.conn.Close()
+	for {
+		frameType, payload, err := s.conn.ReadMessage()
+		if err != nil {
+			s.finish(err)
+			return
+		}
+		message := &models.WebSocketMessage{
+			SessionID: s.ID,
+			Direction: models.WebSocketDirectionReceived,
+			Type:      models.WebSocketMessageText,
+			Data:      string(payload),
+		}
+		if frameType == websocket.BinaryMessage {
+			message.Type = models.WebSocketMessageBinary
+			message.Data = base64.StdEncoding.EncodeToString(payload)
+		}
+		s.record(message)
+	}
+}
+
+func (s *Session) finish(err error) {
+	status := models.WebSocketStatusClosed
+	code := websocket.CloseAbnormalClosure
+	reason := err.Error()
+	var closeErr *websocket.CloseError
+	if errors.As(err, &closeErr) {
+		code = closeErr.Code
+		reason = closeErr.Text
+	} else {
+		status = models.WebSocketStatusFailed
+	}
+	s.record(&models.WebSocketMessage{
+		SessionID: s.ID,
+		Direction: models.WebSocketDirectionReceived,
+		Type:      models.WebSocketMessageClose,
+		Data:      reason,
+	})
+	if err := s.recorder.CloseWebSocketSession(s.ID, status, code, reason); err != nil {
+		logger.Get().Error("Failed to record websocket close", slog.Int("session_id", s.ID), slog.String("error", err.Error()))
+	}
+}
+
+func (s *Session) record(message *models.WebSocketMessage) {
+	message.CreatedAt = time.Now().UTC()
+	if err := s.recorder.AddWebSocketMessage(message); err != nil {
+		logger.Get().Error("Failed to record websocket message", slog.Int("session_id", s.ID), slog.String("error", err.Error()))
+	}
+	s.mu.Lock()
+	subs := make([]func(models.WebSocketMessage), 0, len(s.subs))
+	for _, fn := range s.subs {
+		subs = append(subs, fn)
+	}
+	s.mu.Unlock()
+	for _, fn := range subs {
+		fn(*message)
+	}
+}
@@ -0,0 +1,198 @@
+package wssession
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/hc/hc/internal/models"
+)
+
+// memoryRecorder keeps recorded messages in memory
+type memoryRecorder struct {
+	mu       sync.Mutex
+	messages []models.WebSocketMessage
+	status   string
+	code     int
+}
+
+func (r *memoryRecorder) AddWebSocketMessage(message *models.WebSocketMessage) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.messages = append(r.messages, *message)
+	return nil
+}
+
+func (r *memoryRecorder) CloseWebSocketSession(id int, status string, code int, reason string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.status = status
+	r.code = code
+	return nil
+}
+
+func (r *memoryRecorder) snapshot() []models.WebSocketMessage {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]models.WebSocketMessage(nil), r.messages...)
+}
+
+// newEchoServer starts a websocket server that echoes every frame back
+func newEchoServer(t *testing.T) (*httptest.Server, *http.Header) {
+	t.Helper()
+	var gotHeader http.Header
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeader = r.Header.Clone()
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			frameType, payload, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if err := conn.WriteMessage(frameType, payload); err != nil {
+				return
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &gotHeader
+}
+
+func TestValidateURL(t *testing.T) {
+	tests := []struct {
+		url     string
+		wantErr bool
+	}{
+		{url: "ws://localhost:8080/socket", wantErr: false},
+		{url: "wss://example.com/socket", wantErr: false},
+		{url: "", wantErr: true},
+		{url: "http://example.com", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			err := ValidateURL(tt.url)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateURL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSessionRelay(t *testing.T) {
+	server, gotHeader := newEchoServer(t)
+	recorder := &memoryRecorder{}
+	manager := NewManager(recorder)
+
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http")
+	session, _, err := manager.Open(context.Background(), 1, wsURL, map[string]string{"X-Token": "abc"}, websocket.DefaultDialer)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	if gotHeader.Get("X-Token") != "abc" {
+		t.Errorf("Expected custom header to be sent, got %v", *gotHeader)
+	}
+
+	received := make(chan models.WebSocketMessage, 10)
+	unsubscribe := session.Subscribe(func(message models.WebSocketMessage) {
+		if message.Direction == models.WebSocketDirectionReceived {
+			received <- message
+		}
+	})
+	defer unsubscribe()
+
+	if _, err := session.Send(models.WebSocketMessageText, "hello"); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	binary := base64.StdEncoding.EncodeToString([]byte{0x01, 0x02})
+	if _, err := session.Send(models.WebSocketMessageBinary, binary); err != nil {
+		t.Fatalf("Send() binary error = %v", err)
+	}
+	if _, err := session.Send(models.WebSocketMessageBinary, "not base64!"); err == nil {
+		t.Error("Expected error for invalid base64 binary payload")
+	}
+	if _, err := session.Send("ping", "x"); err == nil {
+		t.Error("Expected error for unknown message type")
+	}
+
+	for _, want := range []models.WebSocketMessage{
+		{Type: models.WebSocketMessageText, Data: "hello"},
+		{Type: models.WebSocketMessageBinary, Data: binary},
+	} {
+		select {
+		case got := <-received:
+			if got.Type != want.Type || got.Data != want.Data {
+				t.Errorf("Received %s %q, want %s %q", got.Type, got.Data, want.Type, want.Data)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatal("Timed out waiting for echoed message")
+		}
+	}
+
+	if err := manager.Close(1); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	select {
+	case <-session.Done():
+	case <-time.After(2 * time.Second):
+		t.Fatal("Session did not finish after close")
+	}
+
+	messages := recorder.snapshot()
+	if len(messages) != 5 {
+		t.Fatalf("Expected 5 recorded messages, got %d: %+v", len(messages), messages)
+	}
+	if messages[4].Type != models.WebSocketMessageClose {
+		t.Errorf("Expected final message to be close, got %s", messages[4].Type)
+	}
+	if recorder.status != models.WebSocketStatusClosed || recorder.code != websocket.CloseNormalClosure {
+		t.Errorf("Expected normal closure, got status=%s code=%d", recorder.status, recorder.code)
+	}
+
+	// The manager forgets finished sessions
+	deadline := time.Now().Add(time.Second)
+	for {
+		if _, ok := manager.Get(1); !ok {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Expected session to be removed from manager")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err := manager.Close(1); err == nil {
+		t.Error("Expected error closing finished session")
+	}
+}
+
+func TestOpenHandshakeFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	manager := NewManager(&memoryRecorder{})
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http")
+	_, resp, err := manager.Open(context.Background(), 1, wsURL, nil, websocket.DefaultDialer)
+	if err == nil {
+		t.Fatal("Expected handshake error")
+	}
+	if resp == nil || resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected 401 handshake response, got %v", resp)
+	}
+	if _, ok := manager.Get(1); ok {
+		t.Error("Failed session should not be registered")
+	}
+}
@@ -8,7 +8,7 @@ echo "Running Go tests with coverage..."
 mkdir -p coverage
 
 # Run tests with coverage
-go test -v -race -coverprofile=coverage/coverage.out -covermode=atomic ./...
+go test -v -race -tags sqlite_fts5 -coverprofile=coverage/coverage.out -covermode=atomic ./...
 
 # Generate coverage report
 if [ -f coverage/coverage.out ]; then
@@ -25,7 +25,7 @@ fi
 # Run go vet
 echo ""
 echo "Running go vet..."
-go vet ./...
+go vet -tags sqlite_fts5 ./...
 
 # Check for formatting issues
 echo ""